@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+)
+
+// CreateApproveCommand creates and returns the approve command.
+func CreateApproveCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseApproveCommand()
+	cmd.Flags().String("decision", "", "decision for the pending approval: yes or no")
+	cmd.Flags().String("comment", "", "comment recorded with the decision")
+	_ = cmd.MarkFlagRequired("decision")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return approveFlow(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseApproveCommand creates the base command structure for approve.
+func createBaseApproveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve <session-id>",
+		Short: "Answer the approval step a paused flow run waits on",
+		Long: `Record a decision for the approval step a paused run waits on and
+continue the run.
+
+The decision and comment become the approval step's output, so later
+conditions can branch on them, e.g. steps.review.output.approved or
+steps.review.output.comment. The exit code is the same as for execute.
+
+Examples:
+  flow-test-go approve 20250110T093000-1a2b3c4d5e6f7a8b --decision yes
+  flow-test-go approve 20250110T093000-1a2b3c4d5e6f7a8b --decision no --comment "needs tests"`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ExactArgs(1),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// approveFlow implements the approve command logic.
+func approveFlow(cmd *cobra.Command, args []string, state *GlobalState) error {
+	decision, err := cmd.Flags().GetString("decision")
+	if err != nil {
+		return fmt.Errorf("failed to read decision flag: %w", err)
+	}
+
+	comment, err := cmd.Flags().GetString("comment")
+	if err != nil {
+		return fmt.Errorf("failed to read comment flag: %w", err)
+	}
+
+	saved, err := loadCheckpoint(state, args[0])
+	if err != nil {
+		return err
+	}
+
+	flow, execCtx := saved.Flow, saved.Execution
+
+	err = engine.Approve(flow, execCtx, decision, comment)
+	if err != nil {
+		return fmt.Errorf("failed to approve: %w", err)
+	}
+
+	cmd.Printf("✍️  Recorded decision %q for step %s\n", decision, execCtx.CurrentStep)
+
+	return continueFlow(cmd, state, flow, execCtx)
+}
@@ -0,0 +1,74 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+)
+
+const approvalFlowJSON = `{
+  "id": "publish",
+  "name": "Publish",
+  "initialStep": "review",
+  "steps": {
+    "review": {
+      "type": "approval",
+      "approval": {"question": "Publish {{.vars.version}}?"},
+      "next": "route"
+    },
+    "route": {
+      "type": "condition",
+      "conditions": [{"expression": "steps.review.output.approved", "next": "published"}],
+      "next": "rejected"
+    },
+    "published": {"type": "end"},
+    "rejected": {"type": "end"}
+  }
+}`
+
+var sessionPattern = regexp.MustCompile(`flow-test-go approve (\S+)`)
+
+func TestApproveCommand_ContinuesPausedRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "publish.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(approvalFlowJSON), 0o600))
+
+	output, err := runRootCommand(t, "execute", flowPath, "--context", "version=v1.2.0")
+	require.Error(t, err)
+
+	var exitErr *commands.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, commands.ExitCodePaused, exitErr.Code)
+	require.ErrorIs(t, err, commands.ErrAwaitingApproval)
+	assert.Contains(t, output, "Step review is waiting for approval: Publish v1.2.0?")
+
+	match := sessionPattern.FindStringSubmatch(output)
+	require.Len(t, match, 2)
+
+	_, err = runRootCommand(t, "approve", match[1], "--decision", "maybe")
+	require.ErrorIs(t, err, engine.ErrInvalidDecision)
+
+	output, err = runRootCommand(t, "approve", match[1], "--decision", "no", "--comment", "needs a changelog")
+	require.NoError(t, err)
+	assert.Contains(t, output, `Recorded decision "no" for step review`)
+	assert.Contains(t, output, "rejected (completed")
+	assert.NotContains(t, output, "published (completed")
+
+	_, err = runRootCommand(t, "approve", match[1], "--decision", "yes")
+	require.ErrorIs(t, err, engine.ErrNotAwaitingApproval)
+}
+
+func TestApproveCommand_RequiresDecision(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := runRootCommand(t, "approve", "20250110T093000-abc")
+	require.ErrorContains(t, err, "decision")
+}
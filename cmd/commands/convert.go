@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/internal/config"
+)
+
+var (
+	// ErrConflictingOutput is returned when convert is given both --output and --in-place.
+	ErrConflictingOutput = errors.New("--output and --in-place cannot be combined")
+
+	// ErrOutputExists is returned when converting in place would overwrite another flow file.
+	ErrOutputExists = errors.New("output file already exists")
+
+	// ErrOutputFormat is returned when the --output extension names another format than --to.
+	ErrOutputFormat = errors.New("output file extension does not match the target format")
+)
+
+// CreateConvertCommand creates and returns the convert command.
+func CreateConvertCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseConvertCommand()
+	cmd.Flags().String("to", "", "target format: json or yaml (defaults to the other format of the flow file)")
+	cmd.Flags().StringP("output", "o", "", "write the converted flow to this file instead of stdout")
+	cmd.Flags().Bool("in-place", false, "replace the flow file with one in the target format")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return convertFlow(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseConvertCommand creates the base command structure for convert.
+func createBaseConvertCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "convert <flow-id|path>",
+		Short: "Convert a flow between JSON and YAML",
+		Long: `Load a flow, validate it and write it out in the other format: JSON
+flows become YAML and YAML flows become JSON. Multi-line strings such as
+prompt templates are written as YAML literal blocks.
+
+The converted flow is printed unless --output names a file. With
+--in-place the flow file is replaced by one with the new extension, so
+the flow keeps a single definition. Comments and fields the flow schema
+does not define are not carried over.
+
+Examples:
+  flow-test-go convert my-flow
+  flow-test-go convert my-flow --in-place
+  flow-test-go convert ./flows/review.yaml --to json -o review.json`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ExactArgs(1),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// convertFlow implements the convert command logic.
+func convertFlow(cmd *cobra.Command, args []string, state *GlobalState) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return fmt.Errorf("failed to read output flag: %w", err)
+	}
+
+	inPlace, err := cmd.Flags().GetBool("in-place")
+	if err != nil {
+		return fmt.Errorf("failed to read in-place flag: %w", err)
+	}
+
+	if output != "" && inPlace {
+		return ErrConflictingOutput
+	}
+
+	flowPath, err := flowArgumentPath(state, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to convert flow: %w", err)
+	}
+
+	flow, err := loadFlowArgument(state, args[0])
+	if err != nil {
+		return err
+	}
+
+	format, err := convertTarget(cmd, flowPath, output)
+	if err != nil {
+		return err
+	}
+
+	if inPlace {
+		extension, err := config.FormatExtension(format)
+		if err != nil {
+			return fmt.Errorf("failed to convert flow: %w", err)
+		}
+
+		output = strings.TrimSuffix(flowPath, filepath.Ext(flowPath)) + extension
+		if output == flowPath {
+			cmd.PrintErrf("✅ %s is already %s\n", flowPath, format)
+
+			return nil
+		}
+
+		_, err = os.Stat(output)
+		if err == nil {
+			return fmt.Errorf("%w: %s", ErrOutputExists, output)
+		}
+	}
+
+	if output == "" {
+		data, err := config.EncodeFlow(flow, format)
+		if err != nil {
+			return fmt.Errorf("failed to convert flow: %w", err)
+		}
+
+		_, err = cmd.OutOrStdout().Write(data)
+		if err != nil {
+			return fmt.Errorf("failed to write flow: %w", err)
+		}
+
+		return nil
+	}
+
+	err = state.configMgr.SaveFlowFile(flow, output)
+	if err != nil {
+		return fmt.Errorf("failed to convert flow: %w", err)
+	}
+
+	if inPlace {
+		err = os.Remove(flowPath)
+		if err != nil {
+			return fmt.Errorf("failed to remove %s: %w", flowPath, err)
+		}
+	}
+
+	cmd.PrintErrf("✅ Converted %s to %s\n", flowPath, output)
+
+	return nil
+}
+
+// convertTarget returns the format to convert to: the --to flag, the format
+// of the --output file, or otherwise the format the flow file is not in.
+func convertTarget(cmd *cobra.Command, flowPath, output string) (string, error) {
+	target, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return "", fmt.Errorf("failed to read to flag: %w", err)
+	}
+
+	if target != "" {
+		_, err = config.FormatExtension(target)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert flow: %w", err)
+		}
+	}
+
+	if output != "" {
+		format, err := config.FlowFormat(output)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert flow: %w", err)
+		}
+
+		if target != "" && target != format {
+			return "", fmt.Errorf("%w: %s is not %s", ErrOutputFormat, output, target)
+		}
+
+		return format, nil
+	}
+
+	if target != "" {
+		return target, nil
+	}
+
+	source, err := config.FlowFormat(flowPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert flow: %w", err)
+	}
+
+	if source == config.FormatYAML {
+		return config.FormatJSON, nil
+	}
+
+	return config.FormatYAML, nil
+}
@@ -0,0 +1,66 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+	"github.com/ondatra-ai/flow-test-go/internal/config"
+)
+
+const multilineFlowJSON = `{
+  "id": "multiline",
+  "name": "Multiline",
+  "initialStep": "ask",
+  "steps": {
+    "ask": {"type": "prompt", "prompt": {"template": "Line one\nLine two"}, "next": "done"},
+    "done": {"type": "end"}
+  }
+}`
+
+func TestConvertCommand_PrintsYAML(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writeFlowFile(t, filepath.Join(".flows", "flows"), "multiline", multilineFlowJSON)
+
+	output, err := runRootCommand(t, "convert", "multiline")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "id: multiline\n")
+	assert.Contains(t, output, "      template: |-\n        Line one\n        Line two\n")
+}
+
+func TestConvertCommand_InPlace(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowsDir := filepath.Join(".flows", "flows")
+	jsonPath := writeFlowFile(t, flowsDir, "multiline", multilineFlowJSON)
+
+	_, err := runRootCommand(t, "convert", "multiline", "--in-place")
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, jsonPath)
+	assert.FileExists(t, filepath.Join(flowsDir, "multiline.yaml"))
+
+	output, err := runRootCommand(t, "list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "📄 multiline")
+
+	_, err = runRootCommand(t, "convert", "multiline", "--to", config.FormatJSON, "-o", "copy.json")
+	require.NoError(t, err)
+
+	_, err = runRootCommand(t, "convert", "copy.json", "--to", config.FormatYAML, "-o", "copy.json")
+	require.ErrorIs(t, err, commands.ErrOutputFormat)
+
+	_, err = runRootCommand(t, "convert", "copy.json", "-o", "copy.yaml", "--in-place")
+	require.ErrorIs(t, err, commands.ErrConflictingOutput)
+
+	require.NoError(t, os.WriteFile("copy.yaml", nil, 0o600))
+
+	_, err = runRootCommand(t, "convert", "copy.json", "--in-place")
+	require.ErrorIs(t, err, commands.ErrOutputExists)
+}
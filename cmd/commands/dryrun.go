@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// dryRunReport is the plan of execute --dry-run in JSON form. Steps are in
+// the order they were planned and have status skipped, or failed for the
+// step the plan stopped at.
+type dryRunReport struct {
+	FlowID  string                `json:"flowId"`
+	Status  types.ExecutionStatus `json:"status"`
+	Steps   []types.StepResult    `json:"steps"`
+	LoopsTo string                `json:"loopsTo,omitempty"`
+	Error   *types.ExecutionError `json:"error,omitempty"`
+}
+
+// dryRunFlow implements execute --dry-run.
+func dryRunFlow(cmd *cobra.Command, state *GlobalState, flow *types.FlowDefinition, variables map[string]any) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("failed to read format flag: %w", err)
+	}
+
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("%w %q: use text or json", ErrUnknownFormat, format)
+	}
+
+	outputValues, err := cmd.Flags().GetStringArray("output")
+	if err != nil {
+		return fmt.Errorf("failed to read output flag: %w", err)
+	}
+
+	outputs, err := parseOutputValues(outputValues)
+	if err != nil {
+		return err
+	}
+
+	flowEngine, err := newFlowEngine(state)
+	if err != nil {
+		return err
+	}
+
+	execCtx, runErr := flowEngine.DryRun(cmd.Context(), flow, variables, engine.DryRunSettings{
+		Prompt:   promptSettings(state),
+		Tool:     engine.ToolSettings{StrictTemplates: state.appConfig.Flow.StrictTemplates},
+		GitHub:   gitHubSettings(state),
+		Approval: engine.ApprovalSettings{StrictTemplates: state.appConfig.Flow.StrictTemplates},
+		Outputs:  outputs,
+	})
+	if execCtx == nil {
+		return fmt.Errorf("failed to plan flow: %w", runErr)
+	}
+
+	loopsTo, _ := execCtx.Metadata[engine.LoopsToKey].(string)
+
+	if format == FormatJSON {
+		data, err := json.MarshalIndent(dryRunReport{
+			FlowID:  execCtx.FlowID,
+			Status:  execCtx.Status,
+			Steps:   orderedResults(execCtx),
+			LoopsTo: loopsTo,
+			Error:   execCtx.Error,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode dry run plan: %w", err)
+		}
+
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		if err != nil {
+			return fmt.Errorf("failed to print dry run plan: %w", err)
+		}
+	} else {
+		printDryRunPlan(cmd, flow, execCtx, loopsTo)
+	}
+
+	if runErr != nil {
+		return &ExitError{Code: ExitCodeFailed, Err: fmt.Errorf("dry run of flow %s failed: %w", flow.ID, runErr)}
+	}
+
+	return nil
+}
+
+// printDryRunPlan prints the steps a dry run walked and what each would do.
+func printDryRunPlan(
+	cmd *cobra.Command,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	loopsTo string,
+) {
+	cmd.Printf("🧪 Dry run of flow %s (%s): nothing is sent to models, MCP servers or GitHub\n\n", flow.ID, flow.Name)
+
+	results := orderedResults(execCtx)
+
+	for index, result := range results {
+		stepType := flow.Steps[result.StepID].Type
+		cmd.Printf("%d. %s %s (%s)\n", index+1, stepStatusIcon(result.Status), result.StepID, stepType)
+
+		if result.Error != nil {
+			cmd.Printf("   %s: %s\n", result.Error.Code, result.Error.Message)
+
+			continue
+		}
+
+		printStepPlan(cmd, stepType, result)
+	}
+
+	if loopsTo != "" {
+		cmd.Printf("\n🔁 The flow loops back to %s, which is already planned\n", loopsTo)
+	}
+
+	cmd.Printf("\n🏁 Planned %d step(s) of flow %s; nothing was executed\n", len(results), flow.ID)
+}
+
+// printStepPlan prints what a planned step would do from its result metadata.
+func printStepPlan(cmd *cobra.Command, stepType types.StepType, result types.StepResult) {
+	plan := result.Metadata
+
+	switch stepType {
+	case types.StepTypePrompt:
+		cmd.Printf("   model: %v\n", plan["model"])
+
+		if tools, ok := plan["tools"].([]string); ok {
+			cmd.Printf("   tools: %s\n", strings.Join(tools, ", "))
+		}
+
+		if server, ok := plan["mcpServer"].(string); ok {
+			cmd.Printf("   MCP server: %s\n", server)
+		}
+
+		if system, ok := plan["system"].(string); ok {
+			cmd.Printf("   system:\n%s", indentLines(system))
+		}
+
+		if prompt, ok := plan["prompt"].(string); ok {
+			cmd.Printf("   prompt:\n%s", indentLines(prompt))
+		}
+	case types.StepTypeTool:
+		cmd.Printf("   tool: %v on %v %s\n", plan["tool"], plan["server"], compactJSON(plan["arguments"]))
+	case types.StepTypeGitHub:
+		cmd.Printf("   github: %v %s\n", plan["operation"], compactJSON(plan["arguments"]))
+	case types.StepTypeApproval:
+		cmd.Printf("   question: %v\n", plan["question"])
+	case types.StepTypeCondition:
+		if output, ok := result.Output.(map[string]any); ok {
+			cmd.Printf("   → %v\n", output["next"])
+		}
+	case types.StepTypeEnd, types.StepTypeParallel:
+	default:
+		if note, ok := plan["note"].(string); ok {
+			cmd.Printf("   %s\n", note)
+		}
+	}
+}
+
+// indentLines indents every line of text for printing under a step.
+func indentLines(text string) string {
+	var out strings.Builder
+
+	for line := range strings.SplitSeq(strings.TrimRight(text, "\n"), "\n") {
+		out.WriteString("     " + line + "\n")
+	}
+
+	return out.String()
+}
+
+// compactJSON formats a value as single-line JSON, falling back to Go syntax.
+func compactJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
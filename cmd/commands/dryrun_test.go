@@ -0,0 +1,101 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+)
+
+const dryRunFlowJSON = `{
+  "id": "triage",
+  "name": "Triage",
+  "version": "1.0",
+  "initialStep": "issue",
+  "steps": {
+    "issue": {"type": "github", "github": {"operation": "get_issue", "arguments": {"number": 7}}, "next": "summarize"},
+    "summarize": {
+      "type": "prompt",
+      "model": "openai/gpt-4o",
+      "prompt": {"template": "Summarize {{.steps.issue.output.title}}"},
+      "next": "done"
+    },
+    "done": {"type": "end"}
+  }
+}`
+
+func TestExecuteCommand_DryRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("OPENROUTER_API_KEY", "")
+
+	flowPath := filepath.Join(t.TempDir(), "triage.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(dryRunFlowJSON), 0o600))
+
+	output, err := runRootCommand(t, "execute", flowPath, "--dry-run", "--output", `issue={"title":"Crash on start"}`)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "🧪 Dry run of flow triage (Triage)")
+	assert.Contains(t, output, "1. ⏭️ issue (github)\n   github: get_issue {\"number\":7,")
+	assert.Contains(t, output, "   model: openai/gpt-4o\n")
+	assert.Contains(t, output, "     Summarize Crash on start\n")
+	assert.Contains(t, output, "🏁 Planned 3 step(s) of flow triage; nothing was executed")
+}
+
+func TestExecuteCommand_DryRunJSON(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "triage.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(dryRunFlowJSON), 0o600))
+
+	output, errOutput, err := runRootCommandStreams(t, "execute", flowPath, "--dry-run", "--format", "json",
+		"--output", `issue={"title":"Crash on start"}`)
+	require.NoError(t, err)
+	assert.Empty(t, errOutput, "the plan goes to standard output")
+
+	var report struct {
+		Status string `json:"status"`
+		Steps  []struct {
+			StepID   string         `json:"stepId"`
+			Status   string         `json:"status"`
+			Metadata map[string]any `json:"metadata"`
+		} `json:"steps"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output[strings.Index(output, "{"):]), &report))
+
+	assert.Equal(t, "completed", report.Status)
+	require.Len(t, report.Steps, 3)
+	assert.Equal(t, "issue", report.Steps[0].StepID)
+	assert.Equal(t, "skipped", report.Steps[0].Status)
+	assert.Equal(t, "Summarize Crash on start", report.Steps[1].Metadata["prompt"])
+}
+
+func TestExecuteCommand_DryRunFailure(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "triage.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(dryRunFlowJSON), 0o600))
+
+	output, err := runRootCommand(t, "execute", flowPath, "--dry-run")
+
+	var exitErr *commands.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, commands.ExitCodeFailed, exitErr.Code)
+	assert.Contains(t, output, "2. ❌ summarize (prompt)\n   TEMPLATE_ERROR:")
+}
+
+func TestExecuteCommand_DryRunFlagsRequireDryRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "triage.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(dryRunFlowJSON), 0o600))
+
+	_, err := runRootCommand(t, "execute", flowPath, "--output", "issue={}")
+	require.ErrorIs(t, err, commands.ErrDryRunOnly)
+}
@@ -0,0 +1,416 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/internal/config"
+	"github.com/ondatra-ai/flow-test-go/pkg/checkpoint"
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/telemetry"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var (
+	// ErrInvalidContextValue is returned when a --context value is not in key=value form.
+	ErrInvalidContextValue = errors.New("context values must be in key=value form")
+
+	// ErrAwaitingApproval is reported when a run paused at an approval step.
+	ErrAwaitingApproval = errors.New("is waiting for approval")
+
+	// ErrDryRunOnly is returned when a flag that only applies to dry runs is used without --dry-run.
+	ErrDryRunOnly = errors.New("only applies with --dry-run")
+)
+
+// Exit codes reported for the final status of an executed flow.
+const (
+	ExitCodeFailed   = 1
+	ExitCodeCanceled = 2
+	ExitCodePaused   = 3
+)
+
+// percent converts the share of a budget that was spent to a percentage.
+const percent = 100
+
+// CreateExecuteCommand creates and returns the execute command.
+func CreateExecuteCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseExecuteCommand()
+	cmd.Flags().StringArray("context", []string{}, "initial context value in key=value form (repeatable); "+
+		"conditions read only the variables the flow declares")
+	cmd.Flags().Bool("dry-run", false, "walk the flow and print what every step would do, without calling anything")
+	cmd.Flags().StringArray("output", []string{},
+		"with --dry-run, assumed output of a step in stepID=value form, JSON or text (repeatable)")
+	cmd.Flags().String("format", FormatText, "with --dry-run, plan format: text or json")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return executeFlow(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseExecuteCommand creates the base command structure for execute.
+func createBaseExecuteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "execute <flow-id|path>",
+		Short: "Execute a flow",
+		Long: `Execute a flow from its initial step until it ends or fails.
+
+The flow can be given either as an ID from the .flows/flows directory
+or as a path to a flow JSON file. The exit code reflects the final
+execution status: 0 completed, 1 failed, 2 canceled, 3 paused at an
+approval step (continue it with the approve command).
+
+With --dry-run the flow is walked from its initial step without side
+effects: prompts, tool arguments and GitHub operations are rendered and
+printed but no model, MCP server or GitHub API is called. Steps are
+assumed to produce the --output values given for them, or a stub, and
+condition steps branch on those. The walk stops where it would loop.
+
+Examples:
+  flow-test-go execute my-flow
+  flow-test-go execute ./flows/my-flow.json --context repo=myrepo
+  flow-test-go execute my-flow --dry-run --output review='"LGTM"' --format json`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ExactArgs(1),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// executeFlow implements the execute command logic.
+func executeFlow(cmd *cobra.Command, args []string, state *GlobalState) error {
+	flow, err := loadFlowArgument(state, args[0])
+	if err != nil {
+		return err
+	}
+
+	contextValues, err := cmd.Flags().GetStringArray("context")
+	if err != nil {
+		return fmt.Errorf("failed to read context flag: %w", err)
+	}
+
+	variables, err := parseContextValues(contextValues)
+	if err != nil {
+		return err
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("failed to read dry-run flag: %w", err)
+	}
+
+	if dryRun {
+		return dryRunFlow(cmd, state, flow, variables)
+	}
+
+	for _, name := range []string{"output", "format"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s %w", name, ErrDryRunOnly)
+		}
+	}
+
+	err = validateExecutionConfig(state, flow)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("🚀 Executing flow %s (%s)\n", flow.ID, flow.Name)
+
+	defer func() { _ = state.servers.Close() }()
+
+	flowEngine, err := newFlowEngine(state)
+	if err != nil {
+		return err
+	}
+
+	execCtx, runErr := flowEngine.Run(cmd.Context(), flow, variables)
+	if execCtx == nil {
+		return fmt.Errorf("failed to execute flow: %w", runErr)
+	}
+
+	recordRun(cmd, state, flow, execCtx)
+
+	printExecutionSummary(cmd, execCtx, flowEngine.RunBudget(flow))
+	printResumeHint(cmd, state, flow, execCtx)
+
+	return exitErrorForStatus(execCtx, runErr)
+}
+
+// validateExecutionConfig checks that the configuration can run every step type the flow uses.
+func validateExecutionConfig(state *GlobalState, flow *types.FlowDefinition) error {
+	if flowUsesStepType(flow, types.StepTypeApproval) && state.appConfig.Flow.CheckpointDir == "" {
+		return fmt.Errorf("approval steps cannot continue without checkpoints: %w", ErrCheckpointingDisabled)
+	}
+
+	if flowUsesStepType(flow, types.StepTypeGitHub) {
+		err := state.configMgr.ValidateForGitHub(state.appConfig)
+		if err == nil && flowUsesConfiguredRepository(flow) {
+			err = state.configMgr.ValidateGitHubRepository(state.appConfig)
+		}
+
+		if err != nil {
+			return fmt.Errorf("configuration is not ready for execution: %w", err)
+		}
+	}
+
+	if !flowUsesStepType(flow, types.StepTypePrompt) {
+		return nil
+	}
+
+	err := state.configMgr.ValidateForExecution(state.appConfig)
+	if err != nil {
+		return fmt.Errorf("configuration is not ready for execution: %w", err)
+	}
+
+	return nil
+}
+
+// newFlowEngine creates an engine with the flow-level timeout, retry and
+// budget defaults from the configuration, checkpointing to
+// flow.checkpointDir when set and logging to the logger of state.
+func newFlowEngine(state *GlobalState) (*engine.Engine, error) {
+	flowEngine := engine.NewEngine(state.registry)
+	flowEngine.SetLogger(state.logger)
+	flowEngine.SetTracer(state.tracing.Tracer(telemetry.TracerName))
+
+	flowConfig := state.appConfig.Flow
+
+	if flowConfig.DefaultTimeout != "" {
+		timeout, err := time.ParseDuration(flowConfig.DefaultTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flow.defaultTimeout %q: %w", flowConfig.DefaultTimeout, err)
+		}
+
+		flowEngine.SetDefaultTimeout(timeout)
+	}
+
+	flowEngine.SetDefaultRetries(flowConfig.MaxRetries)
+
+	err := flowConfig.Budget.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid flow.budget: %w", err)
+	}
+
+	flowEngine.SetBudget(flowConfig.Budget)
+
+	if flowConfig.CheckpointDir != "" {
+		flowEngine.SetCheckpointer(checkpoint.NewStore(flowConfig.CheckpointDir))
+	}
+
+	return flowEngine, nil
+}
+
+// loadFlowArgument loads a flow either by ID or from a file path.
+func loadFlowArgument(state *GlobalState, arg string) (*types.FlowDefinition, error) {
+	if config.IsFlowFile(arg) || strings.ContainsAny(arg, "/\\") {
+		flow, err := state.configMgr.LoadFlowFile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load flow: %w", err)
+		}
+
+		return flow, nil
+	}
+
+	flow, err := state.configMgr.LoadFlow(arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load flow: %w", err)
+	}
+
+	return flow, nil
+}
+
+// parseContextValues converts key=value pairs into initial context variables.
+func parseContextValues(values []string) (map[string]any, error) {
+	variables := make(map[string]any, len(values))
+
+	for _, value := range values {
+		key, val, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidContextValue, value)
+		}
+
+		variables[key] = val
+	}
+
+	return variables, nil
+}
+
+// flowUsesStepType reports whether any step of flow has the given type.
+func flowUsesStepType(flow *types.FlowDefinition, stepType types.StepType) bool {
+	for _, step := range flow.Steps {
+		if step.Type == stepType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flowUsesConfiguredRepository reports whether a github step of flow takes
+// its owner or repository from the configuration.
+func flowUsesConfiguredRepository(flow *types.FlowDefinition) bool {
+	for _, step := range flow.Steps {
+		if step.Type != types.StepTypeGitHub || step.GitHub == nil ||
+			step.GitHub.Operation == types.GitHubResolveReviewThread {
+			continue
+		}
+
+		_, hasOwner := step.GitHub.Arguments["owner"]
+		_, hasRepository := step.GitHub.Arguments["repository"]
+
+		if !hasOwner || !hasRepository {
+			return true
+		}
+	}
+
+	return false
+}
+
+// printExecutionSummary prints every step result in execution order, the
+// final status and what the run spent against its budget.
+func printExecutionSummary(cmd *cobra.Command, execCtx *types.ExecutionContext, budget types.BudgetConfig) {
+	for _, result := range orderedResults(execCtx) {
+		cmd.Printf("%s %s (%s, %v)\n", stepStatusIcon(result.Status), result.StepID, result.Status, result.Duration)
+
+		if attempts := engine.Attempts(result); attempts > 1 {
+			cmd.Printf("   attempts: %d\n", attempts)
+		}
+
+		if result.TokensUsed > 0 {
+			cmd.Printf("   tokens: %d, cost: $%.6f\n", result.TokensUsed, result.Cost)
+		}
+
+		if result.Error != nil {
+			cmd.Printf("   %s: %s\n", result.Error.Code, result.Error.Message)
+		}
+	}
+
+	cmd.Printf("\n🏁 Flow %s %s (session %s)\n", execCtx.FlowID, execCtx.Status, execCtx.SessionID)
+
+	tokens, cost := engine.RunUsage(execCtx)
+	cmd.Printf("💰 Tokens: %s, cost: %s\n", formatSpent(float64(tokens), float64(budget.MaxTokens), "%.0f"),
+		formatSpent(cost, budget.MaxCost, "$%.6f"))
+}
+
+// formatSpent formats an amount spent with format, followed by the limit
+// and the share of it that was spent when there is a limit.
+func formatSpent(spent, limit float64, format string) string {
+	text := fmt.Sprintf(format, spent)
+	if limit <= 0 {
+		return text
+	}
+
+	return text + fmt.Sprintf(" of "+format+" (%.0f%%)", limit, spent/limit*percent)
+}
+
+// orderedResults returns the step results of a run in execution order.
+func orderedResults(execCtx *types.ExecutionContext) []types.StepResult {
+	results := make([]types.StepResult, 0, len(execCtx.StepResults))
+	for _, result := range execCtx.StepResults {
+		results = append(results, result)
+	}
+
+	slices.SortFunc(results, func(a, b types.StepResult) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+
+	return results
+}
+
+// printResumeHint tells how to continue a run that did not complete when it
+// was checkpointed: a paused run shows the pending question and how to answer it.
+func printResumeHint(
+	cmd *cobra.Command,
+	state *GlobalState,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+) {
+	if execCtx.Status == types.StatusCompleted || state.appConfig.Flow.CheckpointDir == "" {
+		return
+	}
+
+	if question, waiting := engine.PendingQuestion(flow, execCtx); waiting {
+		cmd.Printf("⏸️  Step %s is waiting for approval: %s\n", execCtx.CurrentStep, question)
+		cmd.Printf("💾 Answer with: flow-test-go approve %s --decision yes|no [--comment ...]\n", execCtx.SessionID)
+
+		return
+	}
+
+	cmd.Printf("💾 Resume from step %s with: flow-test-go resume %s\n", execCtx.CurrentStep, execCtx.SessionID)
+}
+
+// stepStatusIcon returns the icon printed next to a step result.
+func stepStatusIcon(status types.StepStatus) string {
+	switch status {
+	case types.StepStatusCompleted:
+		return "✅"
+	case types.StepStatusFailed:
+		return "❌"
+	case types.StepStatusSkipped:
+		return "⏭️"
+	case types.StepStatusCanceled:
+		return "🛑"
+	case types.StepStatusPending, types.StepStatusRunning:
+		return "⏳"
+	default:
+		return "•"
+	}
+}
+
+// exitErrorForStatus maps the final execution status to a process exit code.
+func exitErrorForStatus(execCtx *types.ExecutionContext, runErr error) error {
+	switch execCtx.Status {
+	case types.StatusCompleted:
+		return nil
+	case types.StatusCanceled:
+		return &ExitError{Code: ExitCodeCanceled, Err: fmt.Errorf("flow %s was canceled: %w", execCtx.FlowID, runErr)}
+	case types.StatusPaused:
+		return &ExitError{Code: ExitCodePaused, Err: fmt.Errorf("flow %s %w at step %s",
+			execCtx.FlowID, ErrAwaitingApproval, execCtx.CurrentStep)}
+	case types.StatusPending, types.StatusRunning, types.StatusFailed:
+		return &ExitError{Code: ExitCodeFailed, Err: fmt.Errorf("flow %s failed: %w", execCtx.FlowID, runErr)}
+	default:
+		return &ExitError{Code: ExitCodeFailed, Err: fmt.Errorf("flow %s failed: %w", execCtx.FlowID, runErr)}
+	}
+}
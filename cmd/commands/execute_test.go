@@ -0,0 +1,298 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+	"github.com/ondatra-ai/flow-test-go/internal/config"
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+)
+
+const conditionalFlowJSON = `{
+  "id": "conditional",
+  "name": "Conditional",
+  "initialStep": "check",
+  "steps": {
+    "check": {
+      "type": "condition",
+      "conditions": [{"expression": "%s", "next": "done"}]
+    },
+    "done": {"type": "end"}
+  }
+}`
+
+func TestCreateExecuteCommand(t *testing.T) {
+	state := commands.NewGlobalState()
+	cmd := commands.CreateExecuteCommand(state)
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, "execute <flow-id|path>", cmd.Use)
+	assert.Equal(t, "Execute a flow", cmd.Short)
+	assert.Contains(t, cmd.Long, "flow-test-go execute my-flow")
+	assert.NotNil(t, cmd.RunE)
+	assert.NotNil(t, cmd.Flags().Lookup("context"))
+}
+
+func TestExecuteCommand_RequiresFlowArgument(t *testing.T) {
+	state := commands.NewGlobalState()
+	cmd := commands.CreateExecuteCommand(state)
+
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+}
+
+func TestExecuteCommand_RunsFlowFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "conditional.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(formatFlow("true")), 0o600))
+
+	output, err := runRootCommand(t, "execute", flowPath, "--context", "repo=myrepo")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "check")
+	assert.Contains(t, output, "completed")
+}
+
+func TestExecuteCommand_FailedFlowExitCode(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "conditional.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(formatFlow("steps.done.output.count > 3")), 0o600))
+
+	output, err := runRootCommand(t, "execute", flowPath)
+	require.Error(t, err)
+
+	var exitErr *commands.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, commands.ExitCodeFailed, exitErr.Code)
+	assert.Contains(t, output, "INVALID_EXPRESSION")
+}
+
+func TestExecuteCommand_BudgetExceeded(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writeFlowFile(t, t.TempDir(), "spending", `{
+  "id": "spending",
+  "name": "Spending",
+  "initialStep": "spend",
+  "budget": {"maxTokens": 500},
+  "steps": {
+    "spend": {"type": "spend", "next": "done"},
+    "done": {"type": "end"}
+  }
+}`)
+
+	state := commands.NewGlobalState()
+	require.NoError(t, state.Registry().Register("spend",
+		engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+			return &engine.StepOutcome{Next: req.Step.Next, TokensUsed: 600, Cost: 0.012}, nil
+		})))
+
+	cmd := commands.CreateRootCommand(state)
+
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+	cmd.SetArgs([]string{"execute", flowPath})
+
+	err := cmd.Execute()
+
+	var exitErr *commands.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, commands.ExitCodeFailed, exitErr.Code)
+	assert.Contains(t, output.String(), "BUDGET_EXCEEDED: run spent 600 tokens, over its budget of 500")
+	assert.Contains(t, output.String(), "💰 Tokens: 600 of 500 (120%), cost: $0.012000\n")
+}
+
+func TestExecuteCommand_InvalidContextValue(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "conditional.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(formatFlow("true")), 0o600))
+
+	_, err := runRootCommand(t, "execute", flowPath, "--context", "novalue")
+	require.ErrorIs(t, err, commands.ErrInvalidContextValue)
+}
+
+func TestExecuteCommand_ConditionsReadDeclaredVariablesOnly(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "conditional.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(formatFlow(`vars.env == \"prod\"`)), 0o600))
+
+	_, err := runRootCommand(t, "execute", flowPath, "--context", "env=prod")
+	require.Error(t, err, "a context value does not declare a variable")
+	assert.Contains(t, err.Error(), `unknown variable "env"`)
+
+	flowPath = writeFlowFile(t, t.TempDir(), "declared", `{
+  "id": "declared",
+  "name": "Declared",
+  "initialStep": "check",
+  "variables": {"env": "dev"},
+  "steps": {
+    "check": {"type": "condition", "conditions": [{"expression": "vars.env == \"prod\"", "next": "done"}]},
+    "done": {"type": "end"}
+  }
+}`)
+
+	output, err := runRootCommand(t, "execute", flowPath, "--context", "env=prod")
+	require.NoError(t, err)
+	assert.Contains(t, output, "done (completed")
+}
+
+func formatFlow(expression string) string {
+	return fmt.Sprintf(conditionalFlowJSON, expression)
+}
+
+// runRootCommand executes the full command tree with args and returns its combined output.
+func runRootCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := commands.CreateRootCommand(commands.NewGlobalState())
+
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return output.String(), err
+}
+
+// runRootCommandStreams executes the full command tree with args, writing to
+// the standard output and standard error of the process as the binary does,
+// and returns what it wrote to each.
+func runRootCommandStreams(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	stdout := redirect(t, &os.Stdout)
+	stderr := redirect(t, &os.Stderr)
+
+	cmd := commands.CreateRootCommand(commands.NewGlobalState())
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return stdout(), stderr(), err
+}
+
+// redirect replaces *file with a pipe until the returned function is called,
+// which restores it and returns what was written to the pipe.
+func redirect(t *testing.T, file **os.File) func() string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+
+	original := *file
+	*file = writer
+
+	written := make(chan string)
+
+	go func() {
+		data, _ := io.ReadAll(reader)
+		written <- string(data)
+	}()
+
+	return func() string {
+		*file = original
+
+		require.NoError(t, writer.Close())
+
+		return <-written
+	}
+}
+
+func TestExecuteCommand_RunsEmbeddedTool(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "tooling.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(`{
+  "id": "tooling",
+  "name": "Tooling",
+  "initialStep": "greet",
+  "steps": {
+    "greet": {
+      "type": "tool",
+      "tool": {"name": "greet", "arguments": {"name": "{{.vars.user}}"}},
+      "next": "done"
+    },
+    "done": {"type": "end"}
+  }
+}`), 0o600))
+
+	state := commands.NewGlobalState()
+	require.NoError(t, state.Tools().Register(tools.Func{
+		Name:        "greet",
+		Description: "Greet a user",
+		Schema:      nil,
+		Fn: func(_ context.Context, arguments map[string]any) (any, error) {
+			return fmt.Sprintf("hello %v", arguments["name"]), nil
+		},
+	}))
+
+	cmd := commands.CreateRootCommand(state)
+
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+	cmd.SetArgs([]string{"execute", flowPath, "--context", "user=ada"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, output.String(), "greet (completed")
+}
+
+func TestExecuteCommand_GitHubStepRequiresToken(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "")
+
+	flowPath := filepath.Join(t.TempDir(), "triage.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(`{
+  "id": "triage",
+  "name": "Triage",
+  "initialStep": "issue",
+  "steps": {
+    "issue": {"type": "github", "github": {"operation": "get_issue", "arguments": {"number": 1}}, "next": "done"},
+    "done": {"type": "end"}
+  }
+}`), 0o600))
+
+	_, err := runRootCommand(t, "execute", flowPath)
+	require.ErrorIs(t, err, config.ErrGitHubTokenRequired)
+}
+
+func TestExecuteCommand_GitHubStepRequiresRepository(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	flowPath := filepath.Join(t.TempDir(), "triage.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(`{
+  "id": "triage",
+  "name": "Triage",
+  "initialStep": "issue",
+  "steps": {
+    "issue": {"type": "github", "github": {"operation": "get_issue", "arguments": {"number": 1}}, "next": "done"},
+    "done": {"type": "end"}
+  }
+}`), 0o600))
+
+	_, err := runRootCommand(t, "execute", flowPath)
+	require.ErrorIs(t, err, config.ErrGitHubRepositoryRequired)
+}
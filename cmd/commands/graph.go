@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/checkpoint"
+	"github.com/ondatra-ai/flow-test-go/pkg/graph"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// ErrRunFlowMismatch is returned when overlaying a run of a different flow.
+var ErrRunFlowMismatch = errors.New("run belongs to a different flow")
+
+// CreateGraphCommand creates and returns the graph command.
+func CreateGraphCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseGraphCommand()
+	cmd.Flags().String("format", graph.FormatMermaid, "diagram format: mermaid, dot or plantuml")
+	cmd.Flags().String("run", "", "overlay a run: session ID of a checkpoint, or a checkpoint or execution context file")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return renderGraph(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseGraphCommand creates the base command structure for graph.
+func createBaseGraphCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "graph <flow-id|path>",
+		Short: "Export the step graph of a flow as a diagram",
+		Long: `Print the steps of a flow and the transitions between them as a
+Mermaid, Graphviz DOT or PlantUML diagram. Condition edges are labelled
+with their expression; the initial step and end steps are highlighted.
+
+With --run the diagram also shows a past run: steps are colored by the
+status they ended with and the transitions the run took are emphasized.
+
+Examples:
+  flow-test-go graph my-flow
+  flow-test-go graph my-flow --format dot | dot -Tsvg > my-flow.svg
+  flow-test-go graph my-flow --run 20250110T093000-1a2b3c4d5e6f7a8b`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ExactArgs(1),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// renderGraph implements the graph command logic.
+func renderGraph(cmd *cobra.Command, args []string, state *GlobalState) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("failed to read format flag: %w", err)
+	}
+
+	run, err := cmd.Flags().GetString("run")
+	if err != nil {
+		return fmt.Errorf("failed to read run flag: %w", err)
+	}
+
+	flow, err := loadFlowArgument(state, args[0])
+	if err != nil {
+		return err
+	}
+
+	var overlay *graph.Overlay
+
+	if run != "" {
+		execCtx, err := loadRun(state, run)
+		if err != nil {
+			return err
+		}
+
+		if execCtx.FlowID != "" && execCtx.FlowID != flow.ID {
+			return fmt.Errorf("%w: run %s is of flow %s", ErrRunFlowMismatch, execCtx.SessionID, execCtx.FlowID)
+		}
+
+		overlay = graph.RunOverlay(execCtx)
+	}
+
+	err = graph.Render(cmd.OutOrStdout(), flow, format, overlay)
+	if err != nil {
+		return fmt.Errorf("failed to render graph: %w", err)
+	}
+
+	return nil
+}
+
+// loadRun reads the run to overlay: the checkpoint of a session, or a file
+// holding a checkpoint or an execution context.
+func loadRun(state *GlobalState, run string) (*types.ExecutionContext, error) {
+	if filepath.Ext(run) != ".json" && !strings.ContainsAny(run, "/\\") {
+		saved, err := loadCheckpoint(state, run)
+		if err != nil {
+			return nil, err
+		}
+
+		return saved.Execution, nil
+	}
+
+	data, err := os.ReadFile(run) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run file %s: %w", run, err)
+	}
+
+	var saved checkpoint.Checkpoint
+
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse run file %s: %w", run, err)
+	}
+
+	if saved.Execution != nil {
+		return saved.Execution, nil
+	}
+
+	var execCtx types.ExecutionContext
+
+	err = json.Unmarshal(data, &execCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse run file %s: %w", run, err)
+	}
+
+	return &execCtx, nil
+}
@@ -0,0 +1,78 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package commands_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+	"github.com/ondatra-ai/flow-test-go/pkg/graph"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const graphFlowJSON = `{
+  "id": "graphed",
+  "name": "Graphed",
+  "initialStep": "ask",
+  "steps": {
+    "ask": {"type": "prompt", "prompt": {"template": "Hi"}, "next": "done"},
+    "done": {"type": "end"}
+  }
+}`
+
+func TestGraphCommand_Mermaid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writeFlowFile(t, filepath.Join(".flows", "flows"), "graphed", graphFlowJSON)
+
+	output, err := runRootCommand(t, "graph", "graphed")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "flowchart TD\n")
+	assert.Contains(t, output, `s0["ask<br/>prompt"]`)
+	assert.Contains(t, output, "s0 --> s1\n")
+	assert.Contains(t, output, "class s0 initial\n")
+	assert.Contains(t, output, "class s1 terminal\n")
+}
+
+func TestGraphCommand_RunOverlay(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writeFlowFile(t, ".", "graphed", graphFlowJSON)
+
+	run, err := json.Marshal(types.ExecutionContext{
+		FlowID:    "graphed",
+		SessionID: "session-1",
+		StepResults: map[string]types.StepResult{
+			"ask": {StepID: "ask", Status: types.StepStatusCompleted},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("run.json", run, 0o600))
+
+	output, err := runRootCommand(t, "graph", flowPath, "--format", graph.FormatDOT, "--run", "run.json")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, `fillcolor="#d4edda"`)
+
+	run, err = json.Marshal(types.ExecutionContext{FlowID: "other", SessionID: "session-2"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("other.json", run, 0o600))
+
+	_, err = runRootCommand(t, "graph", flowPath, "--run", "other.json")
+	require.ErrorIs(t, err, commands.ErrRunFlowMismatch)
+}
+
+func TestGraphCommand_UnknownFormat(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writeFlowFile(t, ".", "graphed", graphFlowJSON)
+
+	_, err := runRootCommand(t, "graph", flowPath, "--format", "svg")
+	require.ErrorIs(t, err, graph.ErrUnknownFormat)
+}
@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/internal/config"
+)
+
+// CreateMigrateCommand creates and returns the migrate command.
+func CreateMigrateCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseMigrateCommand()
+	cmd.Flags().Bool("dry-run", false, "print the changes without writing the flow files")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return migrateFlows(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseMigrateCommand creates the base command structure for migrate.
+func createBaseMigrateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate [flow-id|path...]",
+		Short: "Upgrade flow files to the current format version",
+		Long: `Rewrite flow files in an older format version in the current one.
+Flows are migrated in memory whenever they are loaded, so older flows keep
+working; migrate makes the upgrade permanent. Without arguments every flow
+in the flows directory is migrated.
+
+A diff of every file that changes is printed before it is rewritten; with
+--dry-run only the diffs are printed. Files keep their JSON or YAML format,
+but as with convert, comments and formatting are not carried over.
+
+Examples:
+  flow-test-go migrate --dry-run
+  flow-test-go migrate my-flow
+  flow-test-go migrate ./flows/review.yaml`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ArbitraryArgs,
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// migrateFlows implements the migrate command logic.
+func migrateFlows(cmd *cobra.Command, args []string, state *GlobalState) error {
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("failed to read dry-run flag: %w", err)
+	}
+
+	if len(args) == 0 {
+		args, err = state.configMgr.ListFlows()
+		if err != nil {
+			return fmt.Errorf("failed to list flows: %w", err)
+		}
+	}
+
+	migrated := 0
+
+	for _, arg := range args {
+		flowPath, err := flowArgumentPath(state, arg)
+		if err != nil {
+			return fmt.Errorf("failed to migrate flow: %w", err)
+		}
+
+		migration, err := state.configMgr.MigrateFlowFile(flowPath)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", flowPath, err)
+		}
+
+		if len(migration.Applied) == 0 {
+			cmd.PrintErrf("✅ %s is at format version %s\n", flowPath, config.CurrentFlowVersion)
+
+			continue
+		}
+
+		err = printMigration(cmd, migration)
+		if err != nil {
+			return err
+		}
+
+		migrated++
+
+		if dryRun {
+			continue
+		}
+
+		const filePerms = 0o600
+
+		err = os.WriteFile(flowPath, migration.Migrated, filePerms)
+		if err != nil {
+			return fmt.Errorf("failed to write flow file: %w", err)
+		}
+
+		cmd.PrintErrf("✅ Migrated %s to format version %s\n", flowPath, config.CurrentFlowVersion)
+	}
+
+	if dryRun && migrated > 0 {
+		cmd.PrintErrf("%d flow(s) would be migrated; run without --dry-run to write them\n", migrated)
+	}
+
+	return nil
+}
+
+// printMigration prints the migrations applied to a flow file and a unified
+// diff of the file.
+func printMigration(cmd *cobra.Command, migration *config.FlowMigration) error {
+	from := migration.From
+	if from == "" {
+		from = "unversioned"
+	}
+
+	cmd.PrintErrf("%s: %s → %s\n", migration.Path, from, config.CurrentFlowVersion)
+
+	for _, applied := range migration.Applied {
+		cmd.PrintErrf("  - %s\n", applied)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(withFinalNewline(migration.Original)),
+		FromFile: migration.Path,
+		FromDate: "",
+		B:        difflib.SplitLines(withFinalNewline(migration.Migrated)),
+		ToFile:   migration.Path + " (migrated)",
+		ToDate:   "",
+		Eol:      "\n",
+		Context:  3, //nolint:mnd // the customary number of context lines
+	})
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", migration.Path, err)
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), diff)
+	if err != nil {
+		return fmt.Errorf("failed to write diff: %w", err)
+	}
+
+	return nil
+}
+
+// withFinalNewline returns a file's content ending with a newline, so the
+// last line diffs like every other.
+func withFinalNewline(data []byte) string {
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	return content
+}
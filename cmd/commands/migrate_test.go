@@ -0,0 +1,51 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyFlowJSON = `{
+  "id": "legacy",
+  "name": "Legacy",
+  "initialStep": "ask",
+  "steps": {
+    "ask": {"type": "prompt", "prompt": "Hello", "next": "done"},
+    "done": {"type": "end"}
+  }
+}`
+
+func TestMigrateCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writeFlowFile(t, filepath.Join(".flows", "flows"), "legacy", legacyFlowJSON)
+
+	output, err := runRootCommand(t, "migrate", "--dry-run")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "legacy.json: unversioned → 1.0\n")
+	assert.Contains(t, output, "  - prompt steps given as a string become prompt objects\n")
+	assert.Contains(t, output, `-    "ask": {"type": "prompt", "prompt": "Hello", "next": "done"},`)
+	assert.Contains(t, output, "+  \"version\": \"1.0\",\n")
+	assert.Contains(t, output, "1 flow(s) would be migrated")
+
+	data, err := os.ReadFile(flowPath)
+	require.NoError(t, err)
+	assert.Equal(t, legacyFlowJSON, string(data))
+
+	output, err = runRootCommand(t, "migrate", "legacy")
+	require.NoError(t, err)
+	assert.Contains(t, output, "✅ Migrated .flows/flows/legacy.json to format version 1.0")
+
+	data, err = os.ReadFile(flowPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\"template\": \"Hello\"")
+
+	output, err = runRootCommand(t, "migrate", flowPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, "✅ .flows/flows/legacy.json is at format version 1.0")
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var (
+	// ErrUnknownStep is returned when a command references a step the flow does not define.
+	ErrUnknownStep = errors.New("step not found in flow")
+
+	// ErrNoPrompt is returned when rendering a step without a prompt configuration.
+	ErrNoPrompt = errors.New("step has no prompt configuration")
+
+	// ErrInvalidOutputValue is returned when an --output value is not in stepID=value form.
+	ErrInvalidOutputValue = errors.New("output values must be in stepID=value form")
+)
+
+// renderArgCount is the number of positional arguments of render: flow and step.
+const renderArgCount = 2
+
+// CreateRenderCommand creates and returns the render command.
+func CreateRenderCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseRenderCommand()
+	cmd.Flags().StringArray("context", []string{}, "context value in key=value form (repeatable)")
+	cmd.Flags().StringArray("output", []string{}, "prior step output in stepID=value form, JSON or text (repeatable)")
+	cmd.Flags().Bool("strict", true, "fail on references to missing keys (defaults to flow.strictTemplates)")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return renderPrompt(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseRenderCommand creates the base command structure for render.
+func createBaseRenderCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "render <flow-id|path> <step-id>",
+		Short: "Render the prompt of a step without calling the model",
+		Long: `Render the system message and prompt template of a prompt step and
+print the result. Prior step outputs can be supplied with --output so
+templates referencing steps.<id>.output can be checked without running
+the flow or spending tokens.
+
+Examples:
+  flow-test-go render my-flow summarize --context repo=myrepo
+  flow-test-go render my-flow summarize --output fetch='{"title":"Bug"}'`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ExactArgs(renderArgCount),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// renderPrompt implements the render command logic.
+func renderPrompt(cmd *cobra.Command, args []string, state *GlobalState) error {
+	flow, err := loadFlowArgument(state, args[0])
+	if err != nil {
+		return err
+	}
+
+	stepID := args[1]
+
+	step, exists := flow.Steps[stepID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownStep, stepID)
+	}
+
+	if step.Prompt == nil {
+		return fmt.Errorf("%w: %s", ErrNoPrompt, stepID)
+	}
+
+	execCtx, err := buildRenderContext(cmd, flow)
+	if err != nil {
+		return err
+	}
+
+	strict := state.appConfig.Flow.StrictTemplates
+	if cmd.Flags().Changed("strict") {
+		strict, err = cmd.Flags().GetBool("strict")
+		if err != nil {
+			return fmt.Errorf("failed to read strict flag: %w", err)
+		}
+	}
+
+	rendered, err := engine.RenderPrompt(flow, execCtx, stepID, step, strict)
+	if err != nil {
+		return fmt.Errorf("failed to render step %s: %w", stepID, err)
+	}
+
+	if rendered.System != "" {
+		cmd.Println("# System")
+		cmd.Println(rendered.System)
+		cmd.Println()
+	}
+
+	cmd.Println("# Prompt")
+	cmd.Println(rendered.User)
+
+	return nil
+}
+
+// buildRenderContext creates an execution context holding the --context
+// variables and the --output step results.
+func buildRenderContext(cmd *cobra.Command, flow *types.FlowDefinition) (*types.ExecutionContext, error) {
+	contextValues, err := cmd.Flags().GetStringArray("context")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read context flag: %w", err)
+	}
+
+	variables, err := parseContextValues(contextValues)
+	if err != nil {
+		return nil, err
+	}
+
+	outputValues, err := cmd.Flags().GetStringArray("output")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output flag: %w", err)
+	}
+
+	outputs, err := parseOutputValues(outputValues)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx := engine.NewExecutionContext(flow, variables)
+
+	for stepID, output := range outputs {
+		execCtx.StepResults[stepID] = types.StepResult{
+			StepID:     stepID,
+			Status:     types.StepStatusCompleted,
+			Output:     output,
+			Error:      nil,
+			StartTime:  execCtx.StartTime,
+			EndTime:    execCtx.StartTime,
+			Duration:   0,
+			TokensUsed: 0,
+			Cost:       0,
+			Metadata:   nil,
+		}
+	}
+
+	return execCtx, nil
+}
+
+// parseOutputValues converts stepID=value pairs into step outputs.
+func parseOutputValues(values []string) (map[string]any, error) {
+	outputs := make(map[string]any, len(values))
+
+	for _, value := range values {
+		stepID, raw, found := strings.Cut(value, "=")
+		if !found || stepID == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidOutputValue, value)
+		}
+
+		outputs[stepID] = parseOutputValue(raw)
+	}
+
+	return outputs, nil
+}
+
+// parseOutputValue decodes raw as JSON, falling back to the plain string.
+func parseOutputValue(raw string) any {
+	var decoded any
+
+	err := json.Unmarshal([]byte(raw), &decoded)
+	if err != nil {
+		return raw
+	}
+
+	return decoded
+}
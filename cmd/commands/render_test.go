@@ -0,0 +1,83 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+)
+
+const promptFlowJSON = `{
+  "id": "prompting",
+  "name": "Prompting",
+  "initialStep": "fetch",
+  "variables": {"repo": "flow-test-go"},
+  "steps": {
+    "fetch": {"type": "prompt", "prompt": {"template": "Fetch the issue"}, "next": "summarize"},
+    "summarize": {
+      "type": "prompt",
+      "prompt": {
+        "system": "You review {{.vars.repo}}.",
+        "template": "Summarize issue {{.steps.fetch.output.title}} for {{.context.audience}}",
+        "context": {"audience": "{{.vars.team}} team"}
+      },
+      "next": "done"
+    },
+    "done": {"type": "end"}
+  }
+}`
+
+func writePromptFlow(t *testing.T) string {
+	t.Helper()
+
+	flowPath := filepath.Join(t.TempDir(), "prompting.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(promptFlowJSON), 0o600))
+
+	return flowPath
+}
+
+func TestRenderCommand_RendersPrompt(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writePromptFlow(t)
+
+	output, err := runRootCommand(t, "render", flowPath, "summarize",
+		"--context", "team=platform", "--output", `fetch={"title":"Crash on start"}`)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "# System\nYou review flow-test-go.")
+	assert.Contains(t, output, "# Prompt\nSummarize issue Crash on start for platform team")
+}
+
+func TestRenderCommand_StrictMissingKey(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writePromptFlow(t)
+
+	_, err := runRootCommand(t, "render", flowPath, "summarize", "--context", "team=platform")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fetch")
+
+	output, err := runRootCommand(t, "render", flowPath, "summarize", "--context", "team=platform", "--strict=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Summarize issue <no value> for platform team")
+}
+
+func TestRenderCommand_Errors(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writePromptFlow(t)
+
+	_, err := runRootCommand(t, "render", flowPath, "missing")
+	require.ErrorIs(t, err, commands.ErrUnknownStep)
+
+	_, err = runRootCommand(t, "render", flowPath, "done")
+	require.ErrorIs(t, err, commands.ErrNoPrompt)
+
+	_, err = runRootCommand(t, "render", flowPath, "summarize", "--output", "novalue")
+	require.ErrorIs(t, err, commands.ErrInvalidOutputValue)
+}
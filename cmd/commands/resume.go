@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/checkpoint"
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// ErrCheckpointingDisabled is returned when resuming while flow.checkpointDir is empty.
+var ErrCheckpointingDisabled = errors.New("checkpointing is disabled (flow.checkpointDir is empty)")
+
+// CreateResumeCommand creates and returns the resume command.
+func CreateResumeCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseResumeCommand()
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return resumeFlow(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseResumeCommand creates the base command structure for resume.
+func createBaseResumeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume <session-id>",
+		Short: "Resume an interrupted flow run",
+		Long: `Resume a flow run from its last checkpoint.
+
+Every run is checkpointed to the flow.checkpointDir directory after each
+step. Resuming continues from the step the run stopped at: a failed or
+interrupted step runs again, and steps that already finished keep their
+results. A run paused at an approval step is continued with the approve
+command instead. The exit code is the same as for execute.
+
+Examples:
+  flow-test-go resume 20250110T093000-1a2b3c4d5e6f7a8b`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ExactArgs(1),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// resumeFlow implements the resume command logic.
+func resumeFlow(cmd *cobra.Command, args []string, state *GlobalState) error {
+	saved, err := loadCheckpoint(state, args[0])
+	if err != nil {
+		return err
+	}
+
+	return continueFlow(cmd, state, saved.Flow, saved.Execution)
+}
+
+// continueFlow resumes a checkpointed run and reports its outcome like execute does.
+func continueFlow(
+	cmd *cobra.Command,
+	state *GlobalState,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+) error {
+	err := flow.Validate(types.WithStepTypes(state.registry))
+	if err != nil {
+		return fmt.Errorf("checkpointed flow is no longer valid: %w", err)
+	}
+
+	err = validateExecutionConfig(state, flow)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("🔁 Resuming flow %s (%s) at step %s\n", flow.ID, flow.Name, execCtx.CurrentStep)
+
+	defer func() { _ = state.servers.Close() }()
+
+	flowEngine, err := newFlowEngine(state)
+	if err != nil {
+		return err
+	}
+
+	runErr := flowEngine.Resume(cmd.Context(), flow, execCtx)
+	if errors.Is(runErr, engine.ErrRunCompleted) {
+		return fmt.Errorf("failed to resume flow: %w", runErr)
+	}
+
+	recordRun(cmd, state, flow, execCtx)
+
+	printExecutionSummary(cmd, execCtx, flowEngine.RunBudget(flow))
+	printResumeHint(cmd, state, flow, execCtx)
+
+	return exitErrorForStatus(execCtx, runErr)
+}
+
+// loadCheckpoint reads the checkpoint of a session from the configured checkpoint directory.
+func loadCheckpoint(state *GlobalState, sessionID string) (*checkpoint.Checkpoint, error) {
+	dir := state.appConfig.Flow.CheckpointDir
+	if dir == "" {
+		return nil, ErrCheckpointingDisabled
+	}
+
+	saved, err := checkpoint.NewStore(dir).Load(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	return saved, nil
+}
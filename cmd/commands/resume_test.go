@@ -0,0 +1,157 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+	"github.com/ondatra-ai/flow-test-go/pkg/checkpoint"
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var errServiceDown = errors.New("service down")
+
+const deployFlowJSON = `{
+  "id": "deploy",
+  "name": "Deploy",
+  "initialStep": "deploy",
+  "steps": {
+    "deploy": {"type": "tool", "tool": {"name": "deploy"}, "next": "done"},
+    "done": {"type": "end"}
+  }
+}`
+
+func TestResumeCommand_ContinuesFailedRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "deploy.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(deployFlowJSON), 0o600))
+
+	calls := 0
+	deploy := tools.Func{
+		Name:        "deploy",
+		Description: "Deploy the service",
+		Schema:      nil,
+		Fn: func(_ context.Context, _ map[string]any) (any, error) {
+			calls++
+			if calls == 1 {
+				return nil, errServiceDown
+			}
+
+			return "deployed", nil
+		},
+	}
+
+	output, err := runWithTool(t, deploy, "execute", flowPath)
+	require.Error(t, err)
+	assert.Contains(t, output, "Resume from step deploy with: flow-test-go resume ")
+
+	entries, err := os.ReadDir(filepath.Join(".flows", "checkpoints"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	sessionID := strings.TrimSuffix(entries[0].Name(), ".json")
+
+	output, err = runWithTool(t, deploy, "resume", sessionID)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Resuming flow deploy (Deploy) at step deploy")
+	assert.Contains(t, output, "deploy (completed")
+	assert.NotContains(t, output, "Resume from step")
+	assert.Equal(t, 2, calls)
+
+	_, err = runWithTool(t, deploy, "resume", sessionID)
+	require.ErrorContains(t, err, "already completed")
+}
+
+func TestResumeCommand_ReportsAttemptsOfCheckpointedSteps(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "release.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(`{
+  "id": "release",
+  "name": "Release",
+  "initialStep": "deploy",
+  "steps": {
+    "deploy": {"type": "flaky", "retry": {"maxAttempts": 3, "delay": 1000000}, "next": "notify"},
+    "notify": {"type": "flaky", "next": "done"},
+    "done": {"type": "end"}
+  }
+}`), 0o600))
+
+	// Every step fails its first attempt: deploy is retried, notify fails the run.
+	calls := map[string]int{}
+	flaky := engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+		calls[req.StepID]++
+		if calls[req.StepID] == 1 {
+			return nil, &types.ExecutionError{Code: "RATE_LIMITED", Message: "slow down", Recoverable: req.Step.Retry != nil}
+		}
+
+		return &engine.StepOutcome{Output: "done", Next: req.Step.Next}, nil
+	})
+
+	run := func(args ...string) (string, error) {
+		state := commands.NewGlobalState()
+		require.NoError(t, state.Registry().Register("flaky", flaky))
+
+		cmd := commands.CreateRootCommand(state)
+
+		var output bytes.Buffer
+		cmd.SetOut(&output)
+		cmd.SetErr(&output)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		return output.String(), err
+	}
+
+	output, err := run("execute", flowPath)
+	require.Error(t, err)
+	assert.Contains(t, output, "deploy (completed")
+	assert.Contains(t, output, "   attempts: 2\n")
+
+	entries, err := os.ReadDir(filepath.Join(".flows", "checkpoints"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	output, err = run("resume", strings.TrimSuffix(entries[0].Name(), ".json"))
+	require.NoError(t, err)
+	assert.Contains(t, output, "notify (completed")
+	assert.Contains(t, output, "   attempts: 2\n", "the checkpointed deploy step keeps its attempts")
+}
+
+func TestResumeCommand_UnknownSession(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := runRootCommand(t, "resume", "20250110T093000-missing")
+	require.ErrorIs(t, err, checkpoint.ErrNotFound)
+}
+
+// runWithTool executes the full command tree with an embedded tool registered.
+func runWithTool(t *testing.T, tool tools.Func, args ...string) (string, error) {
+	t.Helper()
+
+	state := commands.NewGlobalState()
+	require.NoError(t, state.Tools().Register(tool))
+
+	cmd := commands.CreateRootCommand(state)
+
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return output.String(), err
+}
@@ -1,29 +1,127 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/ondatra-ai/flow-test-go/internal/config"
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/github"
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/mcp"
+	"github.com/ondatra-ai/flow-test-go/pkg/telemetry"
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
 )
 
+// ExitError carries the process exit code for a command that failed.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface for ExitError.
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ErrVerboseAndQuiet is returned when --verbose and --quiet are both set.
+var ErrVerboseAndQuiet = errors.New("--verbose and --quiet cannot be used together")
+
+// megabyte converts logging.maxSize to bytes.
+const megabyte = 1 << 20
+
 // GlobalState holds the global application state.
 type GlobalState struct {
 	configMgr *config.Manager
 	appConfig *config.Config
-	initMutex sync.Mutex
+	registry  *engine.Registry
+	tools     *tools.Registry
+	servers   *mcp.Supervisor
+	logger    *slog.Logger
+	logFile   io.Closer
+	tracing   trace.TracerProvider
+	// flushTraces exports the pending spans of tracing; nil until tracing is initialized.
+	flushTraces telemetry.Shutdown
+	initMutex   sync.Mutex
 }
 
 // NewGlobalState creates a new GlobalState instance.
 func NewGlobalState() *GlobalState {
 	return &GlobalState{
-		configMgr: nil,
-		appConfig: nil,
-		initMutex: sync.Mutex{},
+		configMgr:   nil,
+		appConfig:   nil,
+		registry:    engine.NewDefaultRegistry(),
+		tools:       tools.NewRegistry(),
+		servers:     mcp.NewSupervisor(),
+		logger:      logging.Discard(),
+		logFile:     nil,
+		tracing:     noop.NewTracerProvider(),
+		flushTraces: nil,
+		initMutex:   sync.Mutex{},
+	}
+}
+
+// Logger returns the logger configured by the logging settings and the
+// --verbose and --quiet flags. It discards every record until a command runs.
+func (s *GlobalState) Logger() *slog.Logger {
+	return s.logger
+}
+
+// TracerProvider returns the tracer provider configured by the tracing
+// settings. It records nothing unless traces are exported somewhere.
+func (s *GlobalState) TracerProvider() trace.TracerProvider {
+	return s.tracing
+}
+
+// Close exports the pending spans and closes the log file of the state, if any.
+func (s *GlobalState) Close() error {
+	var errs []error
+
+	if s.flushTraces != nil {
+		errs = append(errs, s.flushTraces(context.Background()))
+		s.flushTraces = nil
+	}
+
+	if s.logFile != nil {
+		err := s.logFile.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close log file: %w", err))
+		}
+
+		s.logFile = nil
 	}
+
+	return errors.Join(errs...)
+}
+
+// Registry returns the step executor registry used to validate and execute flows.
+// Additional step types can be registered on it before the command runs.
+func (s *GlobalState) Registry() *engine.Registry {
+	return s.registry
+}
+
+// Tools returns the embedded tools available to tool steps.
+// Additional tools can be registered on it before the command runs.
+func (s *GlobalState) Tools() *tools.Registry {
+	return s.tools
 }
 
 // createBaseCommand creates a base cobra.Command with common settings.
@@ -46,8 +144,18 @@ It supports:
 			DisableDescriptions: false,
 			HiddenDefaultCmd:    false,
 		},
-		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
-			return initializeConfig(state)
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			err := initializeConfig(state)
+			if err != nil {
+				return err
+			}
+
+			err = initializeLogging(cmd, state)
+			if err != nil {
+				return err
+			}
+
+			return initializeTracing(cmd, state)
 		},
 		Aliases:                []string{},
 		SuggestFor:             []string{},
@@ -102,37 +210,224 @@ func initializeConfig(state *GlobalState) error {
 		return fmt.Errorf("failed to initialize config manager: %w", err)
 	}
 
+	state.configMgr.SetStepTypes(state.registry)
+
 	// Load configuration
 	state.appConfig, err = state.configMgr.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	return registerConfiguredExecutors(state)
+}
+
+// initializeLogging creates the logger from the logging settings. --verbose
+// logs everything from debug level to the console and --quiet only errors.
+func initializeLogging(cmd *cobra.Command, state *GlobalState) error {
+	state.initMutex.Lock()
+	defer state.initMutex.Unlock()
+
+	if state.logFile != nil {
+		return nil
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	if verbose && quiet {
+		return ErrVerboseAndQuiet
+	}
+
+	settings := state.appConfig.Logging
+	level, console := settings.Level, settings.Console
+
+	switch {
+	case verbose:
+		level, console = "debug", true
+	case quiet:
+		level = "error"
+	}
+
+	options := logging.Options{
+		Level:      level,
+		Format:     settings.Format,
+		Console:    nil,
+		File:       settings.File,
+		MaxSize:    int64(settings.MaxSize) * megabyte,
+		MaxBackups: settings.MaxBackups,
+	}
+
+	if console {
+		options.Console = cmd.ErrOrStderr()
+	}
+
+	logger, logFile, err := logging.New(options)
+	if err != nil {
+		return fmt.Errorf("invalid logging configuration: %w", err)
+	}
+
+	state.logger, state.logFile = logger, logFile
+
+	return nil
+}
+
+// initializeTracing creates the tracer provider from the tracing settings.
+func initializeTracing(cmd *cobra.Command, state *GlobalState) error {
+	state.initMutex.Lock()
+	defer state.initMutex.Unlock()
+
+	if state.flushTraces != nil {
+		return nil
+	}
+
+	settings := state.appConfig.Tracing
+
+	provider, shutdown, err := telemetry.NewTracerProvider(cmd.Context(), telemetry.Options{
+		ServiceName:    settings.ServiceName,
+		ServiceVersion: cmd.Root().Version,
+		Endpoint:       settings.Endpoint,
+		Headers:        settings.Headers,
+		File:           settings.File,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid tracing configuration: %w", err)
+	}
+
+	state.tracing, state.flushTraces = provider, shutdown
+
 	return nil
 }
 
+// registerConfiguredExecutors registers the step executors that depend on the loaded configuration.
+func registerConfiguredExecutors(state *GlobalState) error {
+	servers := mcp.NewPool(state.servers, state.configMgr.LoadMCPServers)
+
+	if !state.registry.Supports(types.StepTypePrompt) {
+		llmConfig := state.appConfig.LLM
+		provider := llm.NewOpenRouterClient(llmConfig.APIKey, llmConfig.BaseURL)
+
+		executor := engine.NewPromptExecutor(provider, promptSettings(state))
+		executor.SetTools(state.tools, servers)
+
+		err := state.registry.Register(types.StepTypePrompt, executor)
+		if err != nil {
+			return fmt.Errorf("failed to register prompt executor: %w", err)
+		}
+	}
+
+	if !state.registry.Supports(types.StepTypeTool) {
+		err := state.registry.Register(types.StepTypeTool, engine.NewToolExecutor(state.tools, servers, engine.ToolSettings{
+			StrictTemplates: state.appConfig.Flow.StrictTemplates,
+		}))
+		if err != nil {
+			return fmt.Errorf("failed to register tool executor: %w", err)
+		}
+	}
+
+	if !state.registry.Supports(types.StepTypeGitHub) {
+		githubConfig := state.appConfig.GitHub
+
+		err := state.registry.Register(types.StepTypeGitHub, engine.NewGitHubExecutor(
+			github.NewClient(githubConfig.Token, githubConfig.BaseURL), gitHubSettings(state)))
+		if err != nil {
+			return fmt.Errorf("failed to register github executor: %w", err)
+		}
+	}
+
+	if !state.registry.Supports(types.StepTypeParallel) {
+		err := state.registry.Register(types.StepTypeParallel, engine.NewParallelExecutor(engine.ParallelSettings{
+			Concurrent: state.appConfig.Flow.EnableParallel,
+		}))
+		if err != nil {
+			return fmt.Errorf("failed to register parallel executor: %w", err)
+		}
+	}
+
+	if !state.registry.Supports(types.StepTypeApproval) {
+		err := state.registry.Register(types.StepTypeApproval, engine.NewApprovalExecutor(engine.ApprovalSettings{
+			StrictTemplates: state.appConfig.Flow.StrictTemplates,
+		}))
+		if err != nil {
+			return fmt.Errorf("failed to register approval executor: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// promptSettings returns the prompt step settings from the loaded configuration.
+func promptSettings(state *GlobalState) engine.PromptSettings {
+	llmConfig := state.appConfig.LLM
+
+	return engine.PromptSettings{
+		DefaultModel:      llmConfig.DefaultModel,
+		ModelOverrides:    llmConfig.ModelOverrides,
+		MaxTokens:         llmConfig.MaxTokens,
+		Temperature:       llmConfig.Temperature,
+		StrictTemplates:   state.appConfig.Flow.StrictTemplates,
+		MaxToolIterations: llmConfig.MaxToolIterations,
+	}
+}
+
+// gitHubSettings returns the github step settings from the loaded configuration.
+func gitHubSettings(state *GlobalState) engine.GitHubSettings {
+	return engine.GitHubSettings{
+		Owner:           state.appConfig.GitHub.Owner,
+		Repository:      state.appConfig.GitHub.Repository,
+		StrictTemplates: state.appConfig.Flow.StrictTemplates,
+	}
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute(state *GlobalState) {
 	// Create and setup the root command with subcommands
-	rootCmd := createRootCommand(state)
+	rootCmd := CreateRootCommand(state)
+
+	// Ctrl-C and SIGTERM cancel the running flow, which stops as canceled
+	// and keeps its checkpoint so it can be resumed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	err := rootCmd.ExecuteContext(ctx)
+
+	stop()
+
+	_ = state.Close()
 
-	err := rootCmd.Execute()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+
 		os.Exit(1)
 	}
 }
 
-// createRootCommand creates and configures the root command.
-func createRootCommand(state *GlobalState) *cobra.Command {
+// CreateRootCommand creates the root command with all subcommands attached.
+func CreateRootCommand(state *GlobalState) *cobra.Command {
 	rootCmd := createBaseCommand(state)
 
 	// Disable help command
 	rootCmd.SetHelpCommand(createDisabledHelpCommand())
 
+	rootCmd.PersistentFlags().Bool("verbose", false, "log debug records to the console")
+	rootCmd.PersistentFlags().Bool("quiet", false, "log only errors")
+
 	// Add subcommands
 	rootCmd.AddCommand(CreateListCommand(state))
+	rootCmd.AddCommand(CreateExecuteCommand(state))
+	rootCmd.AddCommand(CreateResumeCommand(state))
+	rootCmd.AddCommand(CreateApproveCommand(state))
+	rootCmd.AddCommand(CreateRenderCommand(state))
+	rootCmd.AddCommand(CreateValidateCommand(state))
+	rootCmd.AddCommand(CreateGraphCommand(state))
+	rootCmd.AddCommand(CreateConvertCommand(state))
+	rootCmd.AddCommand(CreateMigrateCommand(state))
+	rootCmd.AddCommand(CreateSchemaCommand(state))
+	rootCmd.AddCommand(CreateRunsCommand(state))
 
 	return rootCmd
 }
@@ -2,6 +2,8 @@ package commands_test
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -79,3 +81,75 @@ func TestListCommand_Flags(t *testing.T) {
 	assert.NotEmpty(t, cmd.Use)
 	assert.NotEmpty(t, cmd.Short)
 }
+
+func TestRootCommand_VerboseLogging(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writeFlowFile(t, t.TempDir(), "conditional", formatFlow("true"))
+
+	output, err := runRootCommand(t, "execute", flowPath)
+	require.NoError(t, err)
+	assert.NotContains(t, output, "level=DEBUG")
+
+	output, err = runRootCommand(t, "execute", flowPath, "--verbose")
+	require.NoError(t, err)
+	assert.Contains(t, output, `level=DEBUG msg="step started" flowId=conditional sessionId=`)
+	assert.Contains(t, output, "stepId=check type=condition")
+
+	_, err = runRootCommand(t, "execute", flowPath, "--verbose", "--quiet")
+	require.ErrorIs(t, err, commands.ErrVerboseAndQuiet)
+}
+
+func TestRootCommand_LogFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	require.NoError(t, os.MkdirAll(".flows", 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(".flows", "config.yaml"), []byte(
+		"logging:\n  level: debug\n  format: json\n  console: false\n  file: logs/flow.log\n"), 0o600))
+
+	flowPath := writeFlowFile(t, t.TempDir(), "conditional", formatFlow("true"))
+
+	state := commands.NewGlobalState()
+	cmd := commands.CreateRootCommand(state)
+
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+	cmd.SetArgs([]string{"execute", flowPath})
+
+	require.NoError(t, cmd.Execute())
+	require.NoError(t, state.Close())
+
+	assert.NotContains(t, output.String(), "step started")
+
+	data, err := os.ReadFile(filepath.Join("logs", "flow.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"step started","flowId":"conditional"`)
+}
+
+func TestRootCommand_TraceFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	require.NoError(t, os.MkdirAll(".flows", 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(".flows", "config.yaml"), []byte(
+		"tracing:\n  file: traces/spans.jsonl\n"), 0o600))
+
+	flowPath := writeFlowFile(t, t.TempDir(), "conditional", formatFlow("true"))
+
+	state := commands.NewGlobalState()
+	cmd := commands.CreateRootCommand(state)
+
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+	cmd.SetArgs([]string{"execute", flowPath})
+
+	require.NoError(t, cmd.Execute())
+	require.NoError(t, state.Close())
+
+	data, err := os.ReadFile(filepath.Join("traces", "spans.jsonl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"step check"`)
+	assert.Contains(t, string(data), `"Name":"flow conditional"`)
+	assert.Contains(t, string(data), `"Value":"flow-test-go"`)
+}
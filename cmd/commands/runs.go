@@ -0,0 +1,490 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/history"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var (
+	// ErrHistoryDisabled is returned by the runs commands while flow.runsDir is empty.
+	ErrHistoryDisabled = errors.New("run history is disabled (flow.runsDir is empty)")
+
+	// ErrUnknownStatus is returned when --status names a run status that does not exist.
+	ErrUnknownStatus = errors.New("unknown run status")
+
+	// ErrInvalidTime is returned when --since or --until is neither a date, a time nor a duration.
+	ErrInvalidTime = errors.New("invalid time")
+)
+
+// runStatuses are the statuses runs list can filter by.
+//
+//nolint:gochecknoglobals // read-only list of statuses
+var runStatuses = []types.ExecutionStatus{
+	types.StatusCompleted, types.StatusFailed, types.StatusCanceled,
+	types.StatusPaused, types.StatusRunning, types.StatusPending,
+}
+
+// CreateRunsCommand creates and returns the runs command with its list and show subcommands.
+func CreateRunsCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseRunsCommand()
+	cmd.AddCommand(createRunsListCommand(state))
+	cmd.AddCommand(createRunsShowCommand(state))
+
+	return cmd
+}
+
+// createRunsListCommand creates the runs list command.
+func createRunsListCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseRunsListCommand()
+	cmd.Flags().String("flow", "", "only runs of this flow ID")
+	cmd.Flags().String("status", "", "only runs with this status: completed, failed, canceled, paused or running")
+	cmd.Flags().String("since", "", "only runs started at or after this date, time or duration ago")
+	cmd.Flags().String("until", "", "only runs started before this date, time or duration ago")
+	cmd.Flags().Int("limit", 0, "list at most this many runs (0 lists all)")
+	cmd.Flags().String("format", FormatText, "output format: text or json")
+	cmd.RunE = func(cobraCmd *cobra.Command, _ []string) error {
+		return listRuns(cobraCmd, state)
+	}
+
+	return cmd
+}
+
+// createRunsShowCommand creates the runs show command.
+func createRunsShowCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseRunsShowCommand()
+	cmd.Flags().Bool("outputs", false, "print the output of every step")
+	cmd.Flags().String("format", FormatText, "output format: text or json")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return showRun(cobraCmd, state, args[0])
+	}
+
+	return cmd
+}
+
+// createBaseRunsCommand creates the base command structure for runs.
+func createBaseRunsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "runs",
+		Short: "List and inspect past flow runs",
+		Long: `List and inspect past flow runs.
+
+Every run started by execute, and every run continued by resume or approve,
+is recorded in the flow.runsDir directory (.flows/runs by default) when it
+ends: its status, timing, tokens and cost, the flow as it was executed and
+the result of every step.
+
+Examples:
+  flow-test-go runs list --flow review --status failed
+  flow-test-go runs list --since 2025-01-07 --until 2025-01-08
+  flow-test-go runs show 20250110T093000-1a2b3c4d5e6f7a8b --outputs`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   nil,
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// createBaseRunsListCommand creates the base command structure for runs list.
+func createBaseRunsListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List recorded runs, most recent first",
+		Long: `List recorded runs, most recent first.
+
+--since and --until take a date (2025-01-07), a time (2025-01-07T15:04:05Z)
+or a duration before now (72h).`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.NoArgs,
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// createBaseRunsShowCommand creates the base command structure for runs show.
+func createBaseRunsShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <session-id>",
+		Short: "Show a recorded run step by step",
+		Long: `Show a recorded run step by step: status, timing, tokens and cost of the
+run and of every step, and with --outputs what every step produced.`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.ExactArgs(1),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// listRuns implements the runs list command logic.
+func listRuns(cmd *cobra.Command, state *GlobalState) error {
+	store, err := historyStore(state)
+	if err != nil {
+		return err
+	}
+
+	filter, err := runFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	format, _ := cmd.Flags().GetString("format")
+
+	err = checkRunsFormat(format)
+	if err != nil {
+		return err
+	}
+
+	runs, err := store.List(filter)
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+
+	if format == FormatJSON {
+		summaries := make([]history.Run, 0, len(runs))
+		for _, run := range runs {
+			summary := *run
+			summary.Flow, summary.Execution = nil, nil
+			summaries = append(summaries, summary)
+		}
+
+		return printRunsJSON(cmd, summaries)
+	}
+
+	if len(runs) == 0 {
+		cmd.Printf("📁 No runs found in %s\n", store.Dir())
+
+		return nil
+	}
+
+	cmd.Printf("📋 Found %d run(s):\n\n", len(runs))
+
+	table := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+	_, _ = fmt.Fprintln(table, "   SESSION\tFLOW\tSTATUS\tSTARTED\tDURATION\tSTEPS\tTOKENS\tCOST")
+
+	for _, run := range runs {
+		_, _ = fmt.Fprintf(table, "%s %s\t%s\t%s\t%s\t%v\t%d\t%d\t$%.6f\n",
+			runStatusIcon(run.Status), run.SessionID, run.FlowID, run.Status,
+			run.StartTime.Local().Format(time.DateTime), run.Duration.Round(time.Millisecond),
+			run.Steps, run.TokensUsed, run.Cost)
+	}
+
+	err = table.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to print runs: %w", err)
+	}
+
+	return nil
+}
+
+// showRun implements the runs show command logic.
+func showRun(cmd *cobra.Command, state *GlobalState, sessionID string) error {
+	store, err := historyStore(state)
+	if err != nil {
+		return err
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+
+	err = checkRunsFormat(format)
+	if err != nil {
+		return err
+	}
+
+	run, err := store.Load(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to show run: %w", err)
+	}
+
+	if format == FormatJSON {
+		return printRunsJSON(cmd, run)
+	}
+
+	outputs, _ := cmd.Flags().GetBool("outputs")
+
+	cmd.Printf("%s Run %s of flow %s (%s): %s\n", runStatusIcon(run.Status), run.SessionID, run.FlowID,
+		run.FlowName, run.Status)
+	cmd.Printf("   started %s, ended %s, took %v\n", run.StartTime.Local().Format(time.DateTime),
+		run.EndTime.Local().Format(time.DateTime), run.Duration.Round(time.Millisecond))
+	cmd.Printf("   %d step(s), tokens: %d, cost: $%.6f\n", run.Steps, run.TokensUsed, run.Cost)
+
+	if run.Execution.Error != nil {
+		cmd.Printf("   %s: %s\n", run.Execution.Error.Code, run.Execution.Error.Message)
+	}
+
+	cmd.Println()
+
+	for index, result := range orderedResults(run.Execution) {
+		cmd.Printf("%d. %s %s (%s, %s, %v)\n", index+1, stepStatusIcon(result.Status), result.StepID,
+			run.Flow.Steps[result.StepID].Type, result.Status, result.Duration)
+
+		if result.TokensUsed > 0 {
+			cmd.Printf("   tokens: %d, cost: $%.6f\n", result.TokensUsed, result.Cost)
+		}
+
+		if result.Error != nil {
+			cmd.Printf("   %s: %s\n", result.Error.Code, result.Error.Message)
+		}
+
+		if outputs && result.Output != nil {
+			cmd.Printf("   output:\n%s", indentLines(formatOutput(result.Output)))
+		}
+	}
+
+	return nil
+}
+
+// recordRun records a run that ended in the run history. A run that cannot
+// be recorded is reported without failing the command.
+func recordRun(cmd *cobra.Command, state *GlobalState, flow *types.FlowDefinition, execCtx *types.ExecutionContext) {
+	dir := state.appConfig.Flow.RunsDir
+	if dir == "" {
+		return
+	}
+
+	err := history.NewStore(dir).Record(flow, execCtx)
+	if err != nil {
+		cmd.PrintErrf("⚠️  Run %s was not recorded: %v\n", execCtx.SessionID, err)
+	}
+}
+
+// historyStore returns the run store of the configured runs directory.
+func historyStore(state *GlobalState) (*history.Store, error) {
+	dir := state.appConfig.Flow.RunsDir
+	if dir == "" {
+		return nil, ErrHistoryDisabled
+	}
+
+	return history.NewStore(dir), nil
+}
+
+// runFilter builds the filter of runs list from its flags.
+func runFilter(cmd *cobra.Command) (history.Filter, error) {
+	flowID, _ := cmd.Flags().GetString("flow")
+	status, _ := cmd.Flags().GetString("status")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+
+	filter := history.Filter{
+		FlowID: flowID,
+		Status: types.ExecutionStatus(status),
+		Since:  time.Time{},
+		Until:  time.Time{},
+	}
+
+	if status != "" && !slices.Contains(runStatuses, filter.Status) {
+		return history.Filter{}, fmt.Errorf("%w %q", ErrUnknownStatus, status)
+	}
+
+	var err error
+
+	filter.Since, err = parseTimeFlag("since", since)
+	if err != nil {
+		return history.Filter{}, err
+	}
+
+	filter.Until, err = parseTimeFlag("until", until)
+	if err != nil {
+		return history.Filter{}, err
+	}
+
+	return filter, nil
+}
+
+// parseTimeFlag parses a date in local time, an RFC 3339 time or a
+// duration before now. An empty value is the zero time.
+func parseTimeFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return date, nil
+	}
+
+	if moment, err := time.Parse(time.RFC3339, value); err == nil {
+		return moment, nil
+	}
+
+	if ago, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-ago), nil
+	}
+
+	return time.Time{}, fmt.Errorf("--%s: %w %q: use a date, an RFC 3339 time or a duration", name, ErrInvalidTime, value)
+}
+
+// checkRunsFormat checks the --format of the runs commands.
+func checkRunsFormat(format string) error {
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("%w %q: use text or json", ErrUnknownFormat, format)
+	}
+
+	return nil
+}
+
+// printRunsJSON prints value as indented JSON.
+func printRunsJSON(cmd *cobra.Command, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode runs: %w", err)
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to print runs: %w", err)
+	}
+
+	return nil
+}
+
+// formatOutput formats a step output for printing: text as is, other values as indented JSON.
+func formatOutput(output any) string {
+	if text, ok := output.(string); ok {
+		return text
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Sprint(output)
+	}
+
+	return string(data)
+}
+
+// runStatusIcon returns the icon printed next to a run.
+func runStatusIcon(status types.ExecutionStatus) string {
+	switch status {
+	case types.StatusCompleted:
+		return "✅"
+	case types.StatusFailed:
+		return "❌"
+	case types.StatusCanceled:
+		return "🛑"
+	case types.StatusPaused:
+		return "⏸️"
+	case types.StatusPending, types.StatusRunning:
+		return "⏳"
+	default:
+		return "•"
+	}
+}
@@ -0,0 +1,90 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+)
+
+var runSessionPattern = regexp.MustCompile(`session (\S+)\)`)
+
+func TestRunsCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := filepath.Join(t.TempDir(), "conditional.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(formatFlow("true")), 0o600))
+
+	failingPath := filepath.Join(t.TempDir(), "failing.json")
+	require.NoError(t, os.WriteFile(failingPath,
+		[]byte(strings.Replace(formatFlow("steps.done.output.count > 3"), `"conditional"`, `"failing"`, 1)), 0o600))
+
+	output, err := runRootCommand(t, "execute", flowPath)
+	require.NoError(t, err)
+
+	completed := runSessionPattern.FindStringSubmatch(output)[1]
+
+	output, _ = runRootCommand(t, "execute", failingPath)
+	failed := runSessionPattern.FindStringSubmatch(output)[1]
+
+	output, err = runRootCommand(t, "runs", "list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "📋 Found 2 run(s):")
+	assert.Less(t, strings.Index(output, failed), strings.Index(output, completed))
+
+	output, errOutput, err := runRootCommandStreams(t, "runs", "list", "--status", "failed", "--format", "json")
+	require.NoError(t, err)
+	assert.Empty(t, errOutput, "the runs go to standard output")
+
+	var runs []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &runs))
+	require.Len(t, runs, 1)
+	assert.Equal(t, failed, runs[0]["sessionId"])
+	assert.Equal(t, "failing", runs[0]["flowId"])
+	assert.NotContains(t, runs[0], "execution")
+
+	output, err = runRootCommand(t, "runs", "list", "--flow", "conditional", "--since", "1h")
+	require.NoError(t, err)
+	assert.Contains(t, output, completed)
+	assert.NotContains(t, output, failed)
+
+	output, err = runRootCommand(t, "runs", "show", failed)
+	require.NoError(t, err)
+	assert.Contains(t, output, "❌ Run "+failed+" of flow failing (Conditional): failed")
+	assert.Contains(t, output, "1. ❌ check (condition, failed,")
+	assert.Contains(t, output, "INVALID_EXPRESSION")
+
+	output, errOutput, err = runRootCommandStreams(t, "runs", "show", failed, "--format", "json")
+	require.NoError(t, err)
+	assert.Empty(t, errOutput, "the run goes to standard output")
+
+	var run map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &run))
+	assert.Equal(t, failed, run["sessionId"])
+
+	output, err = runRootCommand(t, "runs", "show", completed, "--outputs")
+	require.NoError(t, err)
+	assert.Contains(t, output, "2. ✅ done (end, completed,")
+	assert.Contains(t, output, "   output:\n     {\n       \"expression\": \"true\",")
+}
+
+func TestRunsCommand_InvalidFilters(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := runRootCommand(t, "runs", "list", "--status", "broken")
+	require.ErrorIs(t, err, commands.ErrUnknownStatus)
+
+	_, err = runRootCommand(t, "runs", "list", "--until", "last tuesday")
+	require.ErrorIs(t, err, commands.ErrInvalidTime)
+
+	output, err := runRootCommand(t, "runs", "list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "📁 No runs found in .flows/runs")
+}
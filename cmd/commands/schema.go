@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/schema"
+)
+
+// ErrUnknownSchema is returned when the schema command is asked for a schema that does not exist.
+var ErrUnknownSchema = errors.New("unknown schema")
+
+// Schemas printed by the schema command.
+const (
+	schemaFlow      = "flow"
+	schemaMCPServer = "mcp-server"
+)
+
+// CreateSchemaCommand creates and returns the schema command.
+func CreateSchemaCommand(_ *GlobalState) *cobra.Command {
+	cmd := createBaseSchemaCommand()
+	cmd.RunE = printSchema
+
+	return cmd
+}
+
+// createBaseSchemaCommand creates the base command structure for schema.
+func createBaseSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema [flow|mcp-server]",
+		Short: "Print the JSON Schema of flow or MCP server files",
+		Long: `Print the JSON Schema that flow files (the default) or MCP server files
+are checked against. Point the $schema field of a file at the published
+schema to get completion and validation in editors:
+
+  "$schema": "` + schema.FlowID + `"
+
+Flows and MCP servers with fields the schema does not define fail to load.
+
+Examples:
+  flow-test-go schema
+  flow-test-go schema mcp-server > mcp-server.schema.json`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{schemaFlow, schemaMCPServer},
+		ValidArgsFunction:      nil,
+		Args:                   cobra.MaximumNArgs(1),
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// printSchema implements the schema command logic.
+func printSchema(cmd *cobra.Command, args []string) error {
+	name := schemaFlow
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	var document *schema.Schema
+
+	switch name {
+	case schemaFlow:
+		document = schema.Flow()
+	case schemaMCPServer:
+		document = schema.MCPServer()
+	default:
+		return fmt.Errorf("%w %q: use %s or %s", ErrUnknownSchema, name, schemaFlow, schemaMCPServer)
+	}
+
+	data, err := schema.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to print schema: %w", err)
+	}
+
+	_, err = cmd.OutOrStdout().Write(data)
+	if err != nil {
+		return fmt.Errorf("failed to print schema: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+	"github.com/ondatra-ai/flow-test-go/pkg/schema"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	for _, tt := range []struct {
+		args []string
+		id   string
+	}{
+		{args: []string{"schema"}, id: schema.FlowID},
+		{args: []string{"schema", "mcp-server"}, id: schema.MCPServerID},
+	} {
+		output, err := runRootCommand(t, tt.args...)
+		require.NoError(t, err)
+
+		var document map[string]any
+
+		require.NoError(t, json.Unmarshal([]byte(output), &document))
+		assert.Equal(t, tt.id, document["$id"])
+	}
+
+	_, err := runRootCommand(t, "schema", "server")
+	require.ErrorIs(t, err, commands.ErrUnknownSchema)
+}
@@ -0,0 +1,490 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ondatra-ai/flow-test-go/internal/config"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var (
+	// ErrUnknownFormat is returned when --format names an output format validate does not support.
+	ErrUnknownFormat = errors.New("unknown output format")
+
+	// ErrInvalidFlows is reported when validate finds errors in at least one flow.
+	ErrInvalidFlows = errors.New("flow validation failed")
+)
+
+// Output formats of the validate command.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// Diagnostic codes reported by validate for the tools, servers and models a flow uses.
+const (
+	CodeUnknownTool      = "UNKNOWN_TOOL"
+	CodeUnknownMCPServer = "UNKNOWN_MCP_SERVER"
+	CodeMissingModel     = "MISSING_MODEL"
+	CodeInvalidModel     = "INVALID_MODEL"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// modelPattern matches OpenRouter model IDs such as openai/gpt-4o or meta-llama/llama-3-70b:free.
+var modelPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*/[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+// flowReport holds the diagnostics of one validated flow file.
+type flowReport struct {
+	Flow        string             `json:"flow"`
+	Path        string             `json:"path"`
+	Diagnostics []types.Diagnostic `json:"diagnostics"`
+}
+
+// validationReport is the result of validate in JSON form.
+type validationReport struct {
+	Flows    []flowReport `json:"flows"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+}
+
+// CreateValidateCommand creates and returns the validate command.
+func CreateValidateCommand(state *GlobalState) *cobra.Command {
+	cmd := createBaseValidateCommand()
+	cmd.Flags().String("format", FormatText, "output format: text, json or sarif")
+	cmd.RunE = func(cobraCmd *cobra.Command, args []string) error {
+		return validateFlows(cobraCmd, args, state)
+	}
+
+	return cmd
+}
+
+// createBaseValidateCommand creates the base command structure for validate.
+func createBaseValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [flow-id|path...]",
+		Short: "Report every problem in one or more flows",
+		Long: `Check flows without running them and report every problem found, not
+just the first: schema problems such as unknown fields, broken step
+references and condition expressions, embedded tools and MCP servers that
+are not available, and prompt steps without a valid model.
+
+Without arguments every flow in .flows/flows is validated. The exit code
+is 1 when any flow has an error; warnings alone do not fail the command.
+
+Examples:
+  flow-test-go validate
+  flow-test-go validate my-flow ./flows/review.json
+  flow-test-go validate --format sarif > flows.sarif`,
+		Aliases:                []string{},
+		SuggestFor:             []string{},
+		GroupID:                "",
+		Example:                "",
+		ValidArgs:              []string{},
+		ValidArgsFunction:      nil,
+		Args:                   nil,
+		ArgAliases:             []string{},
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            map[string]string{},
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   nil,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:   false,
+			DisableNoDescFlag:   false,
+			DisableDescriptions: false,
+			HiddenDefaultCmd:    false,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               true,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+// validateFlows implements the validate command logic.
+func validateFlows(cmd *cobra.Command, args []string, state *GlobalState) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("failed to read format flag: %w", err)
+	}
+
+	if format != FormatText && format != FormatJSON && format != FormatSARIF {
+		return fmt.Errorf("%w %q: use text, json or sarif", ErrUnknownFormat, format)
+	}
+
+	if len(args) == 0 {
+		args, err = state.configMgr.ListFlows()
+		if err != nil {
+			return fmt.Errorf("failed to list flows: %w", err)
+		}
+	}
+
+	servers, err := state.configMgr.LoadMCPServers()
+	if err != nil {
+		return fmt.Errorf("failed to load MCP servers: %w", err)
+	}
+
+	report := validationReport{Flows: make([]flowReport, 0, len(args)), Errors: 0, Warnings: 0}
+
+	for _, arg := range args {
+		flowPath, err := flowArgumentPath(state, arg)
+		if err != nil {
+			return fmt.Errorf("failed to validate flow: %w", err)
+		}
+
+		_, found, err := state.configMgr.InspectFlowFile(flowPath, func(flow *types.FlowDefinition) []types.Diagnostic {
+			return checkFlowResources(state, flow, servers)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to validate flow: %w", err)
+		}
+
+		for _, diagnostic := range found {
+			if diagnostic.Severity == types.SeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+
+		report.Flows = append(report.Flows, flowReport{Flow: arg, Path: flowPath, Diagnostics: found})
+	}
+
+	err = printValidationReport(cmd, format, report)
+	if err != nil {
+		return err
+	}
+
+	if report.Errors > 0 {
+		return &ExitError{
+			Code: ExitCodeFailed,
+			Err:  fmt.Errorf("%w: %d error(s) in %d flow(s)", ErrInvalidFlows, report.Errors, len(report.Flows)),
+		}
+	}
+
+	return nil
+}
+
+// flowArgumentPath returns the file of a flow given by ID or by path, as
+// loadFlowArgument resolves it.
+func flowArgumentPath(state *GlobalState, arg string) (string, error) {
+	if config.IsFlowFile(arg) || strings.ContainsAny(arg, "/\\") {
+		return arg, nil
+	}
+
+	flowPath, err := state.configMgr.FlowPath(arg)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve flow %s: %w", arg, err)
+	}
+
+	return flowPath, nil
+}
+
+// checkFlowResources reports embedded tools, MCP servers and models the
+// steps of a flow use but that are not available. Tools of MCP servers are
+// not checked because that would start the servers.
+func checkFlowResources(
+	state *GlobalState,
+	flow *types.FlowDefinition,
+	servers map[string]*types.MCPServerConfig,
+) []types.Diagnostic {
+	var found []types.Diagnostic
+
+	stepIDs := make([]string, 0, len(flow.Steps))
+	for stepID := range flow.Steps {
+		stepIDs = append(stepIDs, stepID)
+	}
+
+	slices.Sort(stepIDs)
+
+	for _, stepID := range stepIDs {
+		step := flow.Steps[stepID]
+		if step.Type != types.StepTypePrompt && step.Type != types.StepTypeTool {
+			continue
+		}
+
+		if step.MCPServer != "" {
+			if _, exists := servers[step.MCPServer]; !exists {
+				found = append(found, resourceDiagnostic(CodeUnknownMCPServer, stepID, "mcpServer",
+					fmt.Sprintf("MCP server %q is not configured in .flows/servers", step.MCPServer)))
+			}
+		} else {
+			found = append(found, checkEmbeddedTools(state, stepID, step)...)
+		}
+
+		if step.Type == types.StepTypePrompt {
+			found = append(found, checkModel(state, stepID, step)...)
+		}
+	}
+
+	return found
+}
+
+// checkEmbeddedTools reports tools a step without an MCP server uses that are not embedded tools.
+func checkEmbeddedTools(state *GlobalState, stepID string, step types.Step) []types.Diagnostic {
+	var found []types.Diagnostic
+
+	if step.Tool != nil && step.Tool.Name != "" {
+		if _, exists := state.tools.Lookup(step.Tool.Name); !exists {
+			found = append(found, resourceDiagnostic(CodeUnknownTool, stepID, "tool.name",
+				fmt.Sprintf("tool %q is not an embedded tool; set mcpServer to call a server tool", step.Tool.Name)))
+		}
+	}
+
+	for index, name := range step.Tools {
+		if _, exists := state.tools.Lookup(name); !exists {
+			found = append(found, resourceDiagnostic(CodeUnknownTool, stepID, fmt.Sprintf("tools[%d]", index),
+				fmt.Sprintf("tool %q is not an embedded tool; set mcpServer to use a server tool", name)))
+		}
+	}
+
+	return found
+}
+
+// checkModel reports a prompt step without a model or with a model that is not an OpenRouter model ID.
+func checkModel(state *GlobalState, stepID string, step types.Step) []types.Diagnostic {
+	model := promptSettings(state).ResolveModel(step.Model)
+
+	if model == "" {
+		return []types.Diagnostic{resourceDiagnostic(CodeMissingModel, stepID, "model",
+			"prompt step has no model and llm.defaultModel is not set")}
+	}
+
+	if !modelPattern.MatchString(model) {
+		diagnostic := resourceDiagnostic(CodeInvalidModel, stepID, "model",
+			fmt.Sprintf("model %q is not an OpenRouter model ID in provider/name form", model))
+		diagnostic.Severity = types.SeverityWarning
+
+		return []types.Diagnostic{diagnostic}
+	}
+
+	return nil
+}
+
+// resourceDiagnostic returns an error diagnostic for field of a step.
+func resourceDiagnostic(code, stepID, field, message string) types.Diagnostic {
+	return types.Diagnostic{
+		Code:     code,
+		Message:  message,
+		StepID:   stepID,
+		Path:     types.StepPath(stepID, field),
+		Severity: types.SeverityError,
+		Details:  map[string]any{"stepId": stepID},
+		Line:     0,
+		Column:   0,
+	}
+}
+
+// printValidationReport prints the report in the requested format.
+func printValidationReport(cmd *cobra.Command, format string, report validationReport) error {
+	var document any
+
+	switch format {
+	case FormatJSON:
+		document = report
+	case FormatSARIF:
+		document = newSARIFLog(report)
+	default:
+		printValidationText(cmd, report)
+
+		return nil
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode validation report: %w", err)
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to print validation report: %w", err)
+	}
+
+	return nil
+}
+
+// printValidationText prints the report for people.
+func printValidationText(cmd *cobra.Command, report validationReport) {
+	if len(report.Flows) == 0 {
+		cmd.Println("📁 No flows found in .flows/flows directory")
+
+		return
+	}
+
+	for _, flow := range report.Flows {
+		if len(flow.Diagnostics) == 0 {
+			cmd.Printf("✅ %s\n", flow.Flow)
+
+			continue
+		}
+
+		icon := "⚠️ "
+		if slices.ContainsFunc(flow.Diagnostics, func(d types.Diagnostic) bool {
+			return d.Severity == types.SeverityError
+		}) {
+			icon = "❌"
+		}
+
+		cmd.Printf("%s %s (%s)\n", icon, flow.Flow, flow.Path)
+
+		for _, diagnostic := range flow.Diagnostics {
+			location := diagnostic.Path
+			if diagnostic.Line > 0 {
+				location += fmt.Sprintf(" (line %d, column %d)", diagnostic.Line, diagnostic.Column)
+			}
+
+			cmd.Printf("   %-7s %s %s: %s\n",
+				diagnostic.Severity, diagnostic.Code, location, diagnostic.Message)
+		}
+	}
+
+	cmd.Printf("\n📋 Validated %d flow(s): %d error(s), %d warning(s)\n",
+		len(report.Flows), report.Errors, report.Warnings)
+}
+
+// sarifLog is the root of a SARIF 2.1.0 log.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifRun is a single run of the tool in a SARIF log.
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+// sarifTool describes the tool that produced a SARIF run.
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+// sarifDriver names the tool and the rules its results refer to.
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+// sarifRule is a diagnostic code.
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+// sarifResult is a diagnostic in a SARIF log.
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+// sarifMessage is the text of a SARIF result.
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifLocation points at the flow file and the JSON path within it.
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+// sarifPhysicalLocation is the file a SARIF result was found in.
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+// sarifRegion is where in the file a SARIF result was found.
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// sarifArtifactLocation is the URI of a file.
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifLogicalLocation is the JSON path of a SARIF result.
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// newSARIFLog converts a validation report into a SARIF log for code scanning tools.
+func newSARIFLog(report validationReport) sarifLog {
+	results := make([]sarifResult, 0, report.Errors+report.Warnings)
+	rules := []sarifRule{}
+
+	for _, flow := range report.Flows {
+		for _, diagnostic := range flow.Diagnostics {
+			if !slices.Contains(rules, sarifRule{ID: diagnostic.Code}) {
+				rules = append(rules, sarifRule{ID: diagnostic.Code})
+			}
+
+			results = append(results, sarifResult{
+				RuleID:  diagnostic.Code,
+				Level:   string(diagnostic.Severity),
+				Message: sarifMessage{Text: diagnostic.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(flow.Path)},
+						Region:           newSARIFRegion(diagnostic),
+					},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: diagnostic.Path}},
+				}},
+			})
+		}
+	}
+
+	slices.SortFunc(rules, func(a, b sarifRule) int { return strings.Compare(a.ID, b.ID) })
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "flow-test-go", Rules: rules}},
+			Results: results,
+		}},
+	}
+}
+
+// newSARIFRegion returns the region of a diagnostic, or nil when its position is not known.
+func newSARIFRegion(diagnostic types.Diagnostic) *sarifRegion {
+	if diagnostic.Line == 0 {
+		return nil
+	}
+
+	return &sarifRegion{StartLine: diagnostic.Line, StartColumn: diagnostic.Column}
+}
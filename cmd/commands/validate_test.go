@@ -0,0 +1,173 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/cmd/commands"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const brokenFlowJSON = `{
+  "id": "broken",
+  "name": "Broken",
+  "initialStep": "ask",
+  "steps": {
+    "ask": {
+      "type": "prompt",
+      "prompt": {"template": "Hi"},
+      "model": "gpt4",
+      "tools": ["search"],
+      "next": "missing"
+    },
+    "call": {"type": "tool", "tool": {"name": "lookup"}, "mcpServer": "github", "nxt": "done"},
+    "done": {"type": "end"}
+  }
+}`
+
+const warningFlowJSON = `{
+  "id": "warned",
+  "name": "Warned",
+  "initialStep": "ask",
+  "steps": {
+    "ask": {"type": "prompt", "prompt": {"template": "Hi"}, "model": "gpt4", "next": "done"},
+    "done": {"type": "end"},
+    "orphan": {"type": "end"}
+  }
+}`
+
+func writeFlowFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+
+	flowPath := filepath.Join(dir, name+".json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(content), 0o600))
+
+	return flowPath
+}
+
+func TestValidateCommand_ReportsEveryProblem(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writeFlowFile(t, filepath.Join(".flows", "flows"), "broken", brokenFlowJSON)
+	writeFlowFile(t, filepath.Join(".flows", "flows"), "valid",
+		`{"id": "valid", "name": "Valid", "initialStep": "done", "steps": {"done": {"type": "end"}}}`)
+
+	output, err := runRootCommand(t, "validate")
+	require.ErrorIs(t, err, commands.ErrInvalidFlows)
+
+	var exitErr *commands.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, commands.ExitCodeFailed, exitErr.Code)
+
+	assert.Contains(t, output, "❌ broken (.flows/flows/broken.json)")
+	assert.Contains(t, output,
+		`UNKNOWN_FIELD $.steps.call.nxt (line 13, column 81): unknown field "nxt", did you mean "next"?`)
+	assert.Contains(t, output, "INVALID_REFERENCE $.steps.ask.next")
+	assert.Contains(t, output, "UNKNOWN_MCP_SERVER $.steps.call.mcpServer")
+	assert.Contains(t, output, "UNKNOWN_TOOL $.steps.ask.tools[0]")
+	assert.Contains(t, output, "INVALID_MODEL $.steps.ask.model")
+	assert.Contains(t, output, "UNREACHABLE_STEP $.steps.call")
+	assert.Contains(t, output, "✅ valid")
+	assert.Contains(t, output, "Validated 2 flow(s): 4 error(s), 4 warning(s)")
+}
+
+func TestValidateCommand_JSON(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writeFlowFile(t, t.TempDir(), "warned", warningFlowJSON)
+
+	output, errOutput, err := runRootCommandStreams(t, "validate", flowPath, "--format", "json")
+	require.NoError(t, err)
+	assert.Empty(t, errOutput, "the report goes to standard output")
+
+	var report struct {
+		Flows []struct {
+			Flow        string             `json:"flow"`
+			Diagnostics []types.Diagnostic `json:"diagnostics"`
+		} `json:"flows"`
+		Errors   int `json:"errors"`
+		Warnings int `json:"warnings"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(output), &report))
+	assert.Equal(t, 0, report.Errors)
+	assert.Equal(t, 2, report.Warnings)
+	require.Len(t, report.Flows, 1)
+	assert.Equal(t, flowPath, report.Flows[0].Flow)
+	assert.Equal(t, "$.steps.orphan", report.Flows[0].Diagnostics[0].Path)
+	assert.Equal(t, types.SeverityWarning, report.Flows[0].Diagnostics[0].Severity)
+	assert.Equal(t, 8, report.Flows[0].Diagnostics[0].Line)
+	assert.Equal(t, commands.CodeInvalidModel, report.Flows[0].Diagnostics[1].Code)
+	assert.Equal(t, "ask", report.Flows[0].Diagnostics[1].StepID)
+}
+
+func TestValidateCommand_SARIF(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	flowPath := writeFlowFile(t, t.TempDir(), "warned", warningFlowJSON)
+
+	output, errOutput, err := runRootCommandStreams(t, "validate", flowPath, "--format", "sarif")
+	require.NoError(t, err)
+	assert.Empty(t, errOutput, "the report goes to standard output")
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(output), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "flow-test-go", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, commands.CodeInvalidModel, run.Tool.Driver.Rules[0].ID)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Equal(t, filepath.ToSlash(flowPath), run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "$.steps.ask.model", run.Results[1].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(t, 6, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, 61, run.Results[1].Locations[0].PhysicalLocation.Region.StartColumn)
+}
+
+func TestValidateCommand_UnknownFormat(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := runRootCommand(t, "validate", "--format", "xml")
+	require.ErrorIs(t, err, commands.ErrUnknownFormat)
+}
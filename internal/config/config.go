@@ -7,18 +7,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/spf13/viper"
 
+	"github.com/ondatra-ai/flow-test-go/pkg/graph"
 	"github.com/ondatra-ai/flow-test-go/pkg/types"
 )
 
+// Placeholder GitHub repository of the default configuration, which github
+// steps must not run against.
+const (
+	placeholderGitHubOwner      = "your-github-username"
+	placeholderGitHubRepository = "your-github-repo"
+)
+
 var (
 	// ErrOpenRouterAPIKeyRequired is returned when OpenRouter API key is missing.
 	ErrOpenRouterAPIKeyRequired = errors.New("OpenRouter API key is required for execution " +
 		"(set OPENROUTER_API_KEY env var or llm.apiKey in config)")
 
+	// ErrGitHubTokenRequired is returned when a flow uses github steps without a GitHub token.
+	ErrGitHubTokenRequired = errors.New("GitHub token is required for github steps " +
+		"(set GITHUB_TOKEN env var or github.token in config)")
+
+	// ErrGitHubRepositoryRequired is returned when github steps without owner and
+	// repository arguments would run against an unset or placeholder repository.
+	ErrGitHubRepositoryRequired = errors.New("GitHub owner and repository are required for github steps " +
+		"(set github.owner and github.repository in config)")
+
 	// ErrInvalidFlowID is returned when a flow ID contains path separators.
 	ErrInvalidFlowID = errors.New("invalid flow ID: must not contain path separators")
 
@@ -37,17 +56,20 @@ type Config struct {
 
 	// LLM settings
 	LLM struct {
-		Provider       string            `mapstructure:"provider"`
-		APIKey         string            `mapstructure:"apiKey"`
-		DefaultModel   string            `mapstructure:"defaultModel"`
-		ModelOverrides map[string]string `mapstructure:"modelOverrides"`
-		MaxTokens      int               `mapstructure:"maxTokens"`
-		Temperature    float64           `mapstructure:"temperature"`
+		Provider          string            `mapstructure:"provider"`
+		APIKey            string            `mapstructure:"apiKey"`
+		BaseURL           string            `mapstructure:"baseURL"`
+		DefaultModel      string            `mapstructure:"defaultModel"`
+		ModelOverrides    map[string]string `mapstructure:"modelOverrides"`
+		MaxTokens         int               `mapstructure:"maxTokens"`
+		Temperature       float64           `mapstructure:"temperature"`
+		MaxToolIterations int               `mapstructure:"maxToolIterations"`
 	} `mapstructure:"llm"`
 
 	// GitHub settings
 	GitHub struct {
 		Token         string `mapstructure:"token"`
+		BaseURL       string `mapstructure:"baseURL"`
 		Owner         string `mapstructure:"owner"`
 		Repository    string `mapstructure:"repository"`
 		DefaultBranch string `mapstructure:"defaultBranch"`
@@ -55,20 +77,33 @@ type Config struct {
 
 	// Flow settings
 	Flow struct {
-		Directory      string `mapstructure:"directory"`
-		DefaultTimeout string `mapstructure:"defaultTimeout"`
-		CheckpointDir  string `mapstructure:"checkpointDir"`
-		MaxRetries     int    `mapstructure:"maxRetries"`
-		EnableParallel bool   `mapstructure:"enableParallel"`
+		Directory       string             `mapstructure:"directory"`
+		DefaultTimeout  string             `mapstructure:"defaultTimeout"`
+		CheckpointDir   string             `mapstructure:"checkpointDir"`
+		RunsDir         string             `mapstructure:"runsDir"`
+		MaxRetries      int                `mapstructure:"maxRetries"`
+		EnableParallel  bool               `mapstructure:"enableParallel"`
+		StrictTemplates bool               `mapstructure:"strictTemplates"`
+		Budget          types.BudgetConfig `mapstructure:"budget"` // run budget limits flows leave unset
 	} `mapstructure:"flow"`
 
 	// Logging settings
 	Logging struct {
-		Level   string `mapstructure:"level"`
-		Format  string `mapstructure:"format"`
-		File    string `mapstructure:"file"`
-		Console bool   `mapstructure:"console"`
+		Level      string `mapstructure:"level"`
+		Format     string `mapstructure:"format"`
+		File       string `mapstructure:"file"`
+		Console    bool   `mapstructure:"console"`
+		MaxSize    int    `mapstructure:"maxSize"`    // megabytes before the log file is rotated
+		MaxBackups int    `mapstructure:"maxBackups"` // rotated log files kept
 	} `mapstructure:"logging"`
+
+	// Tracing settings
+	Tracing struct {
+		Endpoint    string            `mapstructure:"endpoint"` // OTLP/HTTP URL traces are sent to
+		Headers     map[string]string `mapstructure:"headers"`
+		File        string            `mapstructure:"file"` // JSON lines file spans are appended to
+		ServiceName string            `mapstructure:"serviceName"`
+	} `mapstructure:"tracing"`
 }
 
 // Manager handles configuration loading and management.
@@ -77,6 +112,7 @@ type Manager struct {
 	configDir  string
 	flowsDir   string
 	serversDir string
+	stepTypes  types.StepTypeRegistry
 }
 
 // NewManager creates a new configuration manager.
@@ -107,6 +143,7 @@ func NewManager() (*Manager, error) {
 		configDir:  configDir,
 		flowsDir:   filepath.Join(configDir, "flows"),
 		serversDir: filepath.Join(configDir, "servers"),
+		stepTypes:  nil,
 	}, nil
 }
 
@@ -159,6 +196,12 @@ func (cm *Manager) LoadConfig() (*Config, error) {
 		}
 	}
 
+	if config.Tracing.Endpoint == "" {
+		if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+			config.Tracing.Endpoint = endpoint
+		}
+	}
+
 	// Validate required settings
 	err = cm.validateConfig(config)
 	if err != nil {
@@ -172,34 +215,65 @@ func (cm *Manager) LoadConfig() (*Config, error) {
 
 // LoadFlow loads a flow definition by ID.
 func (cm *Manager) LoadFlow(flowID string) (*types.FlowDefinition, error) {
-	// Validate flowID contains no path separators
-	if strings.ContainsAny(flowID, "/\\") || strings.Contains(flowID, "..") {
-		return nil, ErrInvalidFlowID
+	flowPath, err := cm.FlowPath(flowID)
+	if err != nil {
+		return nil, err
 	}
 
-	flowPath := filepath.Join(cm.flowsDir, flowID+".json")
+	return cm.LoadFlowFile(flowPath)
+}
+
+// LoadFlowFile loads a flow definition from an explicit file path.
+// The format, JSON or YAML, follows from the file extension.
+func (cm *Manager) LoadFlowFile(flowPath string) (*types.FlowDefinition, error) {
+	format, err := FlowFormat(flowPath)
+	if err != nil {
+		return nil, err
+	}
 
 	data, err := os.ReadFile(flowPath) // #nosec G304
 	if err != nil {
 		return nil, fmt.Errorf("failed to read flow file %s: %w", flowPath, err)
 	}
 
-	var flow types.FlowDefinition
+	flow, doc, found := decodeFlowDocument(data, format)
 
-	err = json.Unmarshal(data, &flow)
+	err = types.FirstError(found)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse flow definition: %w", err)
 	}
 
-	err = flow.Validate()
+	err = cm.validateFlow(flow, doc)
 	if err != nil {
-		return nil, fmt.Errorf("flow validation failed: %w", err)
+		return nil, err
 	}
 
-	return &flow, nil
+	return flow, nil
 }
 
-// ListFlows returns a list of available flow IDs.
+// validateFlow validates a flow definition and rejects step graphs with
+// errors such as loops that never end. The error is located in doc, the
+// document the flow was read from, when it is not nil.
+func (cm *Manager) validateFlow(flow *types.FlowDefinition, doc *document) error {
+	found := flow.Diagnose(types.WithStepTypes(cm.stepTypes))
+	if types.FirstError(found) == nil {
+		found = graph.Analyze(flow)
+	}
+
+	if doc != nil {
+		doc.locate(found)
+	}
+
+	err := types.FirstError(found)
+	if err != nil {
+		return fmt.Errorf("flow validation failed: %w", err)
+	}
+
+	return nil
+}
+
+// ListFlows returns a list of available flow IDs, from JSON and YAML flow
+// files alike. Two files defining the same flow ID are an error.
 func (cm *Manager) ListFlows() ([]string, error) {
 	files, err := os.ReadDir(cm.flowsDir)
 	if err != nil {
@@ -208,13 +282,25 @@ func (cm *Manager) ListFlows() ([]string, error) {
 
 	var flows []string
 
+	fileNames := make(map[string]string)
+
 	for _, file := range files {
-		if !file.IsDir() && filepath.Ext(file.Name()) == ".json" {
-			flowID := file.Name()[:len(file.Name())-5] // Remove .json extension
-			flows = append(flows, flowID)
+		if file.IsDir() || !IsFlowFile(file.Name()) {
+			continue
+		}
+
+		flowID := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		if existing, duplicate := fileNames[flowID]; duplicate {
+			return nil, fmt.Errorf("%w: %s is defined by %s and %s",
+				ErrDuplicateFlowID, flowID, existing, file.Name())
 		}
+
+		fileNames[flowID] = file.Name()
+		flows = append(flows, flowID)
 	}
 
+	slices.Sort(flows)
+
 	return flows, nil
 }
 
@@ -239,9 +325,7 @@ func (cm *Manager) LoadMCPServers() (map[string]*types.MCPServerConfig, error) {
 			return nil, fmt.Errorf("failed to read server config %s: %w", serverPath, err)
 		}
 
-		var serverConfig types.MCPServerConfig
-
-		err = json.Unmarshal(data, &serverConfig)
+		serverConfig, err := decodeMCPServer(data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse server config %s: %w", serverPath, err)
 		}
@@ -251,30 +335,63 @@ func (cm *Manager) LoadMCPServers() (map[string]*types.MCPServerConfig, error) {
 			return nil, fmt.Errorf("server config validation failed for %s: %w", serverConfig.Name, err)
 		}
 
-		servers[serverConfig.Name] = &serverConfig
+		servers[serverConfig.Name] = serverConfig
 	}
 
 	return servers, nil
 }
 
-// SaveFlow saves a flow definition.
+// decodeMCPServer parses an MCP server file, rejecting fields the server
+// schema does not define with the line and column they are at.
+func decodeMCPServer(data []byte) (*types.MCPServerConfig, error) {
+	doc, err := parseDocument(data, FormatJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var serverConfig types.MCPServerConfig
+
+	err = json.Unmarshal(data, &serverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server config: %w", err)
+	}
+
+	err = types.FirstError(doc.unknownFields(reflect.TypeFor[types.MCPServerConfig]()))
+	if err != nil {
+		return nil, fmt.Errorf("invalid server config: %w", err)
+	}
+
+	return &serverConfig, nil
+}
+
+// SaveFlow saves a flow definition in the flows directory. A flow that is
+// already stored keeps its format; new flows are saved as JSON.
 func (cm *Manager) SaveFlow(flow *types.FlowDefinition) error {
-	// Validate flow ID contains no path separators
-	if strings.ContainsAny(flow.ID, "/\\") || strings.Contains(flow.ID, "..") {
-		return ErrInvalidFlowID
+	flowPath, err := cm.FlowPath(flow.ID)
+	if err != nil {
+		return err
 	}
 
-	err := flow.Validate()
+	return cm.SaveFlowFile(flow, flowPath)
+}
+
+// SaveFlowFile saves a flow definition to an explicit file path, in the
+// format the file extension names.
+func (cm *Manager) SaveFlowFile(flow *types.FlowDefinition, flowPath string) error {
+	format, err := FlowFormat(flowPath)
 	if err != nil {
-		return fmt.Errorf("flow validation failed: %w", err)
+		return err
 	}
 
-	data, err := json.MarshalIndent(flow, "", "  ")
+	err = cm.validateFlow(flow, nil)
 	if err != nil {
-		return fmt.Errorf("failed to marshal flow: %w", err)
+		return err
 	}
 
-	flowPath := filepath.Join(cm.flowsDir, flow.ID+".json")
+	data, err := EncodeFlow(flow, format)
+	if err != nil {
+		return err
+	}
 
 	const filePerms = 0o600
 
@@ -315,6 +432,12 @@ func (cm *Manager) SaveMCPServer(server *types.MCPServerConfig) error {
 	return nil
 }
 
+// SetStepTypes sets the registry used to validate step types of loaded and saved flows.
+// When unset, only the built-in step types are accepted.
+func (cm *Manager) SetStepTypes(registry types.StepTypeRegistry) {
+	cm.stepTypes = registry
+}
+
 // GetConfig returns the current configuration.
 func (cm *Manager) GetConfig() *Config {
 	return cm.config
@@ -330,6 +453,27 @@ func (cm *Manager) ValidateForExecution(config *Config) error {
 	return nil
 }
 
+// ValidateForGitHub validates that the configuration can run github steps.
+func (cm *Manager) ValidateForGitHub(config *Config) error {
+	if config.GitHub.Token == "" {
+		return ErrGitHubTokenRequired
+	}
+
+	return nil
+}
+
+// ValidateGitHubRepository validates that the configuration names the
+// repository of github steps without owner and repository arguments.
+func (cm *Manager) ValidateGitHubRepository(config *Config) error {
+	owner, repository := config.GitHub.Owner, config.GitHub.Repository
+
+	if owner == "" || repository == "" || owner == placeholderGitHubOwner || repository == placeholderGitHubRepository {
+		return fmt.Errorf("%w, got %q/%q", ErrGitHubRepositoryRequired, owner, repository)
+	}
+
+	return nil
+}
+
 // createDefaultConfig creates a default configuration structure.
 func (cm *Manager) createDefaultConfig() *Config {
 	return &Config{
@@ -338,6 +482,7 @@ func (cm *Manager) createDefaultConfig() *Config {
 		GitHub:  cm.createDefaultGitHubConfig(),
 		Flow:    cm.createDefaultFlowConfig(),
 		Logging: cm.createDefaultLoggingConfig(),
+		Tracing: cm.createDefaultTracingConfig(),
 	}
 }
 
@@ -358,43 +503,52 @@ func (cm *Manager) createDefaultAppConfig() struct {
 }
 
 func (cm *Manager) createDefaultLLMConfig() struct {
-	Provider       string            `mapstructure:"provider"`
-	APIKey         string            `mapstructure:"apiKey"`
-	DefaultModel   string            `mapstructure:"defaultModel"`
-	ModelOverrides map[string]string `mapstructure:"modelOverrides"`
-	MaxTokens      int               `mapstructure:"maxTokens"`
-	Temperature    float64           `mapstructure:"temperature"`
+	Provider          string            `mapstructure:"provider"`
+	APIKey            string            `mapstructure:"apiKey"`
+	BaseURL           string            `mapstructure:"baseURL"`
+	DefaultModel      string            `mapstructure:"defaultModel"`
+	ModelOverrides    map[string]string `mapstructure:"modelOverrides"`
+	MaxTokens         int               `mapstructure:"maxTokens"`
+	Temperature       float64           `mapstructure:"temperature"`
+	MaxToolIterations int               `mapstructure:"maxToolIterations"`
 } {
 	return struct {
-		Provider       string            `mapstructure:"provider"`
-		APIKey         string            `mapstructure:"apiKey"`
-		DefaultModel   string            `mapstructure:"defaultModel"`
-		ModelOverrides map[string]string `mapstructure:"modelOverrides"`
-		MaxTokens      int               `mapstructure:"maxTokens"`
-		Temperature    float64           `mapstructure:"temperature"`
+		Provider          string            `mapstructure:"provider"`
+		APIKey            string            `mapstructure:"apiKey"`
+		BaseURL           string            `mapstructure:"baseURL"`
+		DefaultModel      string            `mapstructure:"defaultModel"`
+		ModelOverrides    map[string]string `mapstructure:"modelOverrides"`
+		MaxTokens         int               `mapstructure:"maxTokens"`
+		Temperature       float64           `mapstructure:"temperature"`
+		MaxToolIterations int               `mapstructure:"maxToolIterations"`
 	}{
-		Provider:       "",
-		APIKey:         "",
-		DefaultModel:   "",
-		ModelOverrides: nil,
-		MaxTokens:      0,
-		Temperature:    0.0,
+		Provider:          "",
+		APIKey:            "",
+		BaseURL:           "",
+		DefaultModel:      "",
+		ModelOverrides:    nil,
+		MaxTokens:         0,
+		Temperature:       0.0,
+		MaxToolIterations: 0,
 	}
 }
 
 func (cm *Manager) createDefaultGitHubConfig() struct {
 	Token         string `mapstructure:"token"`
+	BaseURL       string `mapstructure:"baseURL"`
 	Owner         string `mapstructure:"owner"`
 	Repository    string `mapstructure:"repository"`
 	DefaultBranch string `mapstructure:"defaultBranch"`
 } {
 	return struct {
 		Token         string `mapstructure:"token"`
+		BaseURL       string `mapstructure:"baseURL"`
 		Owner         string `mapstructure:"owner"`
 		Repository    string `mapstructure:"repository"`
 		DefaultBranch string `mapstructure:"defaultBranch"`
 	}{
 		Token:         "",
+		BaseURL:       "",
 		Owner:         "",
 		Repository:    "",
 		DefaultBranch: "",
@@ -402,43 +556,77 @@ func (cm *Manager) createDefaultGitHubConfig() struct {
 }
 
 func (cm *Manager) createDefaultFlowConfig() struct {
-	Directory      string `mapstructure:"directory"`
-	DefaultTimeout string `mapstructure:"defaultTimeout"`
-	CheckpointDir  string `mapstructure:"checkpointDir"`
-	MaxRetries     int    `mapstructure:"maxRetries"`
-	EnableParallel bool   `mapstructure:"enableParallel"`
+	Directory       string             `mapstructure:"directory"`
+	DefaultTimeout  string             `mapstructure:"defaultTimeout"`
+	CheckpointDir   string             `mapstructure:"checkpointDir"`
+	RunsDir         string             `mapstructure:"runsDir"`
+	MaxRetries      int                `mapstructure:"maxRetries"`
+	EnableParallel  bool               `mapstructure:"enableParallel"`
+	StrictTemplates bool               `mapstructure:"strictTemplates"`
+	Budget          types.BudgetConfig `mapstructure:"budget"`
 } {
 	return struct {
-		Directory      string `mapstructure:"directory"`
-		DefaultTimeout string `mapstructure:"defaultTimeout"`
-		CheckpointDir  string `mapstructure:"checkpointDir"`
-		MaxRetries     int    `mapstructure:"maxRetries"`
-		EnableParallel bool   `mapstructure:"enableParallel"`
+		Directory       string             `mapstructure:"directory"`
+		DefaultTimeout  string             `mapstructure:"defaultTimeout"`
+		CheckpointDir   string             `mapstructure:"checkpointDir"`
+		RunsDir         string             `mapstructure:"runsDir"`
+		MaxRetries      int                `mapstructure:"maxRetries"`
+		EnableParallel  bool               `mapstructure:"enableParallel"`
+		StrictTemplates bool               `mapstructure:"strictTemplates"`
+		Budget          types.BudgetConfig `mapstructure:"budget"`
 	}{
-		Directory:      "",
-		DefaultTimeout: "",
-		CheckpointDir:  "",
-		MaxRetries:     0,
-		EnableParallel: false,
+		Directory:       "",
+		DefaultTimeout:  "",
+		CheckpointDir:   "",
+		RunsDir:         "",
+		MaxRetries:      0,
+		EnableParallel:  false,
+		StrictTemplates: false,
+		Budget:          types.BudgetConfig{MaxTokens: 0, MaxCost: 0, WarnAt: 0},
 	}
 }
 
 func (cm *Manager) createDefaultLoggingConfig() struct {
-	Level   string `mapstructure:"level"`
-	Format  string `mapstructure:"format"`
-	File    string `mapstructure:"file"`
-	Console bool   `mapstructure:"console"`
+	Level      string `mapstructure:"level"`
+	Format     string `mapstructure:"format"`
+	File       string `mapstructure:"file"`
+	Console    bool   `mapstructure:"console"`
+	MaxSize    int    `mapstructure:"maxSize"`
+	MaxBackups int    `mapstructure:"maxBackups"`
+} {
+	return struct {
+		Level      string `mapstructure:"level"`
+		Format     string `mapstructure:"format"`
+		File       string `mapstructure:"file"`
+		Console    bool   `mapstructure:"console"`
+		MaxSize    int    `mapstructure:"maxSize"`
+		MaxBackups int    `mapstructure:"maxBackups"`
+	}{
+		Level:      "",
+		Format:     "",
+		File:       "",
+		Console:    false,
+		MaxSize:    0,
+		MaxBackups: 0,
+	}
+}
+
+func (cm *Manager) createDefaultTracingConfig() struct {
+	Endpoint    string            `mapstructure:"endpoint"`
+	Headers     map[string]string `mapstructure:"headers"`
+	File        string            `mapstructure:"file"`
+	ServiceName string            `mapstructure:"serviceName"`
 } {
 	return struct {
-		Level   string `mapstructure:"level"`
-		Format  string `mapstructure:"format"`
-		File    string `mapstructure:"file"`
-		Console bool   `mapstructure:"console"`
+		Endpoint    string            `mapstructure:"endpoint"`
+		Headers     map[string]string `mapstructure:"headers"`
+		File        string            `mapstructure:"file"`
+		ServiceName string            `mapstructure:"serviceName"`
 	}{
-		Level:   "",
-		Format:  "",
-		File:    "",
-		Console: false,
+		Endpoint:    "",
+		Headers:     map[string]string{},
+		File:        "",
+		ServiceName: "",
 	}
 }
 
@@ -451,35 +639,52 @@ func (cm *Manager) setDefaults() {
 
 	// LLM defaults
 	viper.SetDefault("llm.provider", "openrouter")
+	viper.SetDefault("llm.baseURL", "https://openrouter.ai/api/v1")
 	viper.SetDefault("llm.defaultModel", "openai/gpt-4-turbo")
 
 	const (
-		defaultMaxTokens   = 4096
-		defaultTemperature = 0.7
+		defaultMaxTokens         = 4096
+		defaultTemperature       = 0.7
+		defaultMaxToolIterations = 10
 	)
 
 	viper.SetDefault("llm.maxTokens", defaultMaxTokens)
 	viper.SetDefault("llm.temperature", defaultTemperature)
+	viper.SetDefault("llm.maxToolIterations", defaultMaxToolIterations)
 
 	// GitHub defaults
-	viper.SetDefault("github.owner", "your-github-username")
-	viper.SetDefault("github.repository", "your-github-repo")
+	viper.SetDefault("github.baseURL", "https://api.github.com")
+	viper.SetDefault("github.owner", placeholderGitHubOwner)
+	viper.SetDefault("github.repository", placeholderGitHubRepository)
 	viper.SetDefault("github.defaultBranch", "main")
 
 	// Flow defaults
 	viper.SetDefault("flow.directory", ".flows")
 	viper.SetDefault("flow.defaultTimeout", "5m")
 	viper.SetDefault("flow.checkpointDir", ".flows/checkpoints")
+	viper.SetDefault("flow.runsDir", ".flows/runs")
 
 	const defaultMaxRetries = 3
 
 	viper.SetDefault("flow.maxRetries", defaultMaxRetries)
 	viper.SetDefault("flow.enableParallel", true)
+	viper.SetDefault("flow.strictTemplates", true)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "text")
 	viper.SetDefault("logging.console", true)
+
+	const (
+		defaultLogMaxSize    = 10
+		defaultLogMaxBackups = 3
+	)
+
+	viper.SetDefault("logging.maxSize", defaultLogMaxSize)
+	viper.SetDefault("logging.maxBackups", defaultLogMaxBackups)
+
+	// Tracing defaults
+	viper.SetDefault("tracing.serviceName", "flow-test-go")
 }
 
 // validateConfig validates the basic structure of the configuration.
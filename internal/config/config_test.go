@@ -3,8 +3,10 @@ package config_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ondatra-ai/flow-test-go/internal/config"
+	"github.com/ondatra-ai/flow-test-go/pkg/graph"
 	"github.com/ondatra-ai/flow-test-go/pkg/types"
 )
 
@@ -103,10 +106,15 @@ func TestManager_SaveFlow(t *testing.T) {
 				Model:      "",
 				Tools:      []string{},
 				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
 				Next:       "step2",
 				Conditions: []types.ConditionConfig{},
 				Timeout:    nil,
 				Retry:      nil,
+				MaxVisits:  0,
 				Metadata:   make(map[string]any),
 			},
 			"step2": {
@@ -115,10 +123,15 @@ func TestManager_SaveFlow(t *testing.T) {
 				Model:      "",
 				Tools:      []string{},
 				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
 				Next:       "",
 				Conditions: []types.ConditionConfig{},
 				Timeout:    nil,
 				Retry:      nil,
+				MaxVisits:  0,
 				Metadata:   make(map[string]any),
 			},
 		},
@@ -167,10 +180,15 @@ func TestManager_LoadFlow(t *testing.T) {
 				Model:      "",
 				Tools:      []string{},
 				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
 				Next:       "step2",
 				Conditions: []types.ConditionConfig{},
 				Timeout:    nil,
 				Retry:      nil,
+				MaxVisits:  0,
 				Metadata:   make(map[string]any),
 			},
 			"step2": {
@@ -179,10 +197,15 @@ func TestManager_LoadFlow(t *testing.T) {
 				Model:      "",
 				Tools:      []string{},
 				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
 				Next:       "",
 				Conditions: []types.ConditionConfig{},
 				Timeout:    nil,
 				Retry:      nil,
+				MaxVisits:  0,
 				Metadata:   make(map[string]any),
 			},
 		},
@@ -219,6 +242,366 @@ func TestManager_LoadFlow_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to read flow file")
 }
 
+func TestManager_LoadFlowFile(t *testing.T) {
+	// Create a temporary directory for testing
+	tmpDir := t.TempDir()
+	// Change to temp directory
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowPath := filepath.Join(tmpDir, "standalone.json")
+	flowJSON := `{"id": "standalone", "name": "Standalone", "initialStep": "done", "steps": {"done": {"type": "end"}}}`
+	require.NoError(t, os.WriteFile(flowPath, []byte(flowJSON), 0o600))
+
+	flow, err := manager.LoadFlowFile(flowPath)
+	require.NoError(t, err)
+	assert.Equal(t, "standalone", flow.ID)
+	assert.Equal(t, "done", flow.InitialStep)
+
+	_, err = manager.LoadFlowFile(filepath.Join(tmpDir, "missing.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read flow file")
+
+	loopJSON := `{"id": "loop", "name": "Loop", "initialStep": "a", "steps": {
+		"a": {"type": "condition", "conditions": [{"expression": "true", "next": "b"}]},
+		"b": {"type": "condition", "conditions": [{"expression": "true", "next": "a"}]}}}`
+	require.NoError(t, os.WriteFile(flowPath, []byte(loopJSON), 0o600))
+
+	_, err = manager.LoadFlowFile(flowPath)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, graph.CodeUnconditionalCycle, execErr.Code)
+}
+
+func TestManager_InspectFlowFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowPath := filepath.Join(tmpDir, "typos.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(`{
+  "id": "typos",
+  "name": "Typos",
+  "steps": {
+    "start": {"type": "end", "nxt": "done", "retry": {"maxAttempts": 2, "dealy": 5}},
+    "done": {"type": "end", "next": "start"}
+  },
+  "initalStep": "start"
+}`), 0o600))
+
+	flow, found, err := manager.InspectFlowFile(flowPath)
+	require.NoError(t, err)
+	require.NotNil(t, flow)
+
+	locations := make([]string, 0, len(found))
+	for _, diagnostic := range found {
+		assert.Equal(t, types.SeverityError, diagnostic.Severity)
+		assert.Equal(t, config.CodeUnknownField, diagnostic.Code)
+
+		locations = append(locations, fmt.Sprintf("%s %d:%d", diagnostic.Path, diagnostic.Line, diagnostic.Column))
+	}
+
+	assert.Equal(t, []string{
+		"$.steps.start.nxt 5:30",
+		"$.steps.start.retry.dealy 5:73",
+		"$.initalStep 8:3",
+	}, locations)
+	assert.Equal(t, `unknown field "nxt", did you mean "next"?`, found[0].Message)
+	assert.Equal(t, `unknown field "initalStep", did you mean "initialStep"?`, found[2].Message)
+
+	require.NoError(t, os.WriteFile(flowPath, []byte(`{"id": "typos", "steps": []}`), 0o600))
+
+	flow, found, err = manager.InspectFlowFile(flowPath)
+	require.NoError(t, err)
+	assert.Nil(t, flow)
+	require.Len(t, found, 1)
+	assert.Equal(t, config.CodeInvalidType, found[0].Code)
+	assert.Equal(t, "$.steps", found[0].Path)
+
+	require.NoError(t, os.WriteFile(flowPath, []byte(`{"id": `), 0o600))
+
+	_, found, err = manager.InspectFlowFile(flowPath)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, config.CodeInvalidJSON, found[0].Code)
+
+	_, _, err = manager.InspectFlowFile(filepath.Join(tmpDir, "missing.json"))
+	require.Error(t, err)
+}
+
+const reviewFlowYAML = `id: review
+name: Review
+initialStep: ask
+steps:
+  ask:
+    type: prompt
+    prompt:
+      template: |
+        Review this change:
+        {{ .context.diff }}
+    next: done
+  done:
+    type: end
+`
+
+func TestManager_LoadFlow_YAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowsDir := filepath.Join(".flows", "flows")
+	require.NoError(t, os.WriteFile(filepath.Join(flowsDir, "review.yaml"), []byte(reviewFlowYAML), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(flowsDir, "other.yml"),
+		[]byte("id: other\nname: Other\ninitialStep: done\nsteps:\n  done:\n    type: end\n"), 0o600))
+
+	flows, err := manager.ListFlows()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other", "review"}, flows)
+
+	flow, err := manager.LoadFlow("review")
+	require.NoError(t, err)
+	assert.Equal(t, "Review this change:\n{{ .context.diff }}\n", flow.Steps["ask"].Prompt.Template)
+	assert.Equal(t, "done", flow.Steps["ask"].Next)
+
+	_, err = manager.LoadFlow("other")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(flowsDir, "review.json"),
+		[]byte(`{"id": "review", "name": "Review", "initialStep": "done", "steps": {"done": {"type": "end"}}}`), 0o600))
+
+	_, err = manager.ListFlows()
+	require.ErrorIs(t, err, config.ErrDuplicateFlowID)
+
+	_, err = manager.LoadFlow("review")
+	require.ErrorIs(t, err, config.ErrDuplicateFlowID)
+
+	_, err = manager.LoadFlowFile(filepath.Join(tmpDir, "review.txt"))
+	require.ErrorIs(t, err, config.ErrUnknownFlowFormat)
+}
+
+func TestManager_SaveFlow_KeepsFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowPath := filepath.Join(".flows", "flows", "review.yaml")
+	require.NoError(t, os.WriteFile(flowPath, []byte(reviewFlowYAML), 0o600))
+
+	flow, err := manager.LoadFlow("review")
+	require.NoError(t, err)
+
+	flow.Name = "Reviewed"
+	require.NoError(t, manager.SaveFlow(flow))
+	assert.NoFileExists(t, filepath.Join(".flows", "flows", "review.json"))
+
+	data, err := os.ReadFile(flowPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "name: Reviewed\n")
+	assert.Contains(t, string(data), "      template: |\n        Review this change:\n")
+
+	saved, err := manager.LoadFlow("review")
+	require.NoError(t, err)
+	assert.Equal(t, flow, saved)
+}
+
+func TestEncodeFlow_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	flow, err := config.DecodeFlow([]byte(reviewFlowYAML), config.FormatYAML)
+	require.NoError(t, err)
+
+	flow.Variables = map[string]string{"enabled": "true", "count": "3", "date": "2025-01-10"}
+
+	for _, format := range []string{config.FormatJSON, config.FormatYAML} {
+		data, err := config.EncodeFlow(flow, format)
+		require.NoError(t, err)
+
+		decoded, err := config.DecodeFlow(data, format)
+		require.NoError(t, err)
+		assert.Equal(t, flow, decoded, format)
+	}
+
+	_, err = config.DecodeFlow([]byte("id: [unclosed"), config.FormatYAML)
+	require.Error(t, err)
+
+	_, err = config.EncodeFlow(flow, "toml")
+	require.ErrorIs(t, err, config.ErrUnknownFlowFormat)
+}
+
+func TestManager_InspectFlowFile_YAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowPath := filepath.Join(tmpDir, "review.yaml")
+	require.NoError(t, os.WriteFile(flowPath, []byte(reviewFlowYAML+"    nxt: ask\n"), 0o600))
+
+	flow, found, err := manager.InspectFlowFile(flowPath)
+	require.NoError(t, err)
+	require.NotNil(t, flow)
+	require.Len(t, found, 1)
+	assert.Equal(t, config.CodeUnknownField, found[0].Code)
+	assert.Equal(t, "$.steps.done.nxt", found[0].Path)
+
+	require.NoError(t, os.WriteFile(flowPath, []byte("id: review\n  name: Review\n"), 0o600))
+
+	flow, found, err = manager.InspectFlowFile(flowPath)
+	require.NoError(t, err)
+	assert.Nil(t, flow)
+	require.Len(t, found, 1)
+	assert.Equal(t, config.CodeInvalidYAML, found[0].Code)
+}
+
+type stepTypeSet map[types.StepType]bool
+
+func (s stepTypeSet) Supports(stepType types.StepType) bool {
+	return s[stepType]
+}
+
+const legacyFlowJSON = `{
+  "id": "legacy",
+  "name": "Legacy",
+  "initialStep": "ask",
+  "steps": {
+    "ask": {"type": "prompt", "prompt": "Summarize {{.vars.topic}}", "next": "done"},
+    "done": {"type": "end", "nxt": null}
+  }
+}`
+
+func TestManager_LoadFlowFile_Migrates(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowPath := filepath.Join(tmpDir, "legacy.json")
+	require.NoError(t, os.WriteFile(flowPath, []byte(strings.Replace(legacyFlowJSON, `, "nxt": null`, "", 1)), 0o600))
+
+	flow, err := manager.LoadFlowFile(flowPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.CurrentFlowVersion, flow.Version)
+	require.NotNil(t, flow.Steps["ask"].Prompt)
+	assert.Equal(t, "Summarize {{.vars.topic}}", flow.Steps["ask"].Prompt.Template)
+
+	// Problems in a migrated flow are still found where they were written.
+	require.NoError(t, os.WriteFile(flowPath, []byte(legacyFlowJSON), 0o600))
+
+	_, err = manager.LoadFlowFile(flowPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown field "nxt", did you mean "next"? (line 7, column 29)`)
+}
+
+func TestManager_LoadFlowFile_UnsupportedVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{name: "newer", version: `"2.0"`, expected: `"2.0": flow-test-go reads versions 0.0 to 1.0`},
+		{name: "malformed", version: `"v1"`, expected: `"v1": expected major.minor`},
+		{name: "number", version: `1.0`, expected: `1: the version must be a string`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flowPath := filepath.Join(tmpDir, tt.name+".yaml")
+			content := "id: versioned\nname: Versioned\nversion: " + tt.version + "\nsteps:\n  done: {type: end}\n"
+			require.NoError(t, os.WriteFile(flowPath, []byte(content), 0o600))
+
+			_, err := manager.LoadFlowFile(flowPath)
+
+			var execErr *types.ExecutionError
+			require.ErrorAs(t, err, &execErr)
+			assert.Equal(t, config.CodeUnsupportedVersion, execErr.Code)
+			assert.Contains(t, err.Error(), tt.expected)
+			assert.Contains(t, err.Error(), "(line 3, column 1)")
+		})
+	}
+}
+
+func TestManager_MigrateFlowFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowPath := filepath.Join(tmpDir, "legacy.yaml")
+	legacyYAML := "id: legacy\nname: Legacy\ninitialStep: ask\nsteps:\n" +
+		"  ask: {type: prompt, prompt: Hello, next: done}\n  done: {type: end}\n"
+	require.NoError(t, os.WriteFile(flowPath, []byte(legacyYAML), 0o600))
+
+	migration, err := manager.MigrateFlowFile(flowPath)
+	require.NoError(t, err)
+
+	assert.Empty(t, migration.From)
+	assert.Equal(t, []string{
+		"prompt steps given as a string become prompt objects",
+		"format version set to 1.0",
+	}, migration.Applied)
+	assert.Equal(t, legacyYAML, string(migration.Original))
+	assert.Contains(t, string(migration.Migrated), "version: \"1.0\"\n")
+	assert.Contains(t, string(migration.Migrated), "    prompt:\n      template: Hello\n")
+
+	// The file is not written, and a migrated flow needs no more migrations.
+	data, err := os.ReadFile(flowPath)
+	require.NoError(t, err)
+	assert.Equal(t, legacyYAML, string(data))
+
+	require.NoError(t, os.WriteFile(flowPath, migration.Migrated, 0o600))
+
+	migration, err = manager.MigrateFlowFile(flowPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.CurrentFlowVersion, migration.From)
+	assert.Empty(t, migration.Applied)
+	assert.Equal(t, migration.Original, migration.Migrated)
+}
+
+func TestManager_SetStepTypes(t *testing.T) {
+	// Create a temporary directory for testing
+	tmpDir := t.TempDir()
+	// Change to temp directory
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	flowJSON := `{"id": "custom", "name": "Custom", "initialStep": "run", "steps": {"run": {"type": "shell"}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(".flows", "flows", "custom.json"), []byte(flowJSON), 0o600))
+
+	// Built-in step types do not include shell
+	_, err = manager.LoadFlow("custom")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no executor is registered for step type")
+
+	// Registered step types are accepted
+	manager.SetStepTypes(stepTypeSet{"shell": true})
+
+	flow, err := manager.LoadFlow("custom")
+	require.NoError(t, err)
+	assert.Equal(t, types.StepType("shell"), flow.Steps["run"].Type)
+}
+
 func TestManager_ListFlows(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir := t.TempDir()
@@ -250,10 +633,15 @@ func TestManager_ListFlows(t *testing.T) {
 					Model:      "",
 					Tools:      []string{},
 					MCPServer:  "",
+					Tool:       nil,
+					Approval:   nil,
+					Parallel:   nil,
+					GitHub:     nil,
 					Next:       "",
 					Conditions: []types.ConditionConfig{},
 					Timeout:    nil,
 					Retry:      nil,
+					MaxVisits:  0,
 					Metadata:   make(map[string]any),
 				},
 			},
@@ -285,6 +673,7 @@ func TestManager_SaveMCPServer(t *testing.T) {
 
 	// Create a test MCP server config
 	serverConfig := &types.MCPServerConfig{
+		Schema:           "",
 		Name:             "test-server",
 		Command:          "python",
 		Args:             []string{"-m", "test_server"},
@@ -338,6 +727,7 @@ func TestManager_SaveMCPServer_InvalidName(t *testing.T) {
 
 	// Create server config with invalid name
 	serverConfig := &types.MCPServerConfig{
+		Schema:           "",
 		Name:             "test/invalid", // Contains path separator
 		Command:          "python",
 		Args:             []string{},
@@ -368,6 +758,7 @@ func TestManager_SaveMCPServer_ValidationError(t *testing.T) {
 
 	// Create server config that will fail validation (empty name)
 	serverConfig := &types.MCPServerConfig{
+		Schema:           "",
 		Name:             "", // Empty name should fail validation
 		Command:          "python",
 		Args:             []string{},
@@ -422,6 +813,31 @@ func TestManager_ValidateForExecution(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestManager_ValidateGitHubRepository(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	defaults, err := manager.LoadConfig()
+	require.NoError(t, err)
+
+	err = manager.ValidateGitHubRepository(defaults)
+	require.ErrorIs(t, err, config.ErrGitHubRepositoryRequired, "the default repository is a placeholder")
+
+	for _, repository := range [][2]string{{"", "flow-test-go"}, {"ondatra-ai", ""}} {
+		cfg := &config.Config{}
+		cfg.GitHub.Owner, cfg.GitHub.Repository = repository[0], repository[1]
+
+		require.ErrorIs(t, manager.ValidateGitHubRepository(cfg), config.ErrGitHubRepositoryRequired)
+	}
+
+	cfg := &config.Config{}
+	cfg.GitHub.Owner, cfg.GitHub.Repository = "ondatra-ai", "flow-test-go"
+
+	require.NoError(t, manager.ValidateGitHubRepository(cfg))
+}
+
 func TestManager_LoadMCPServers(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir := t.TempDir()
@@ -432,6 +848,7 @@ func TestManager_LoadMCPServers(t *testing.T) {
 
 	// Create test server configs
 	server1 := &types.MCPServerConfig{
+		Schema:           "",
 		Name:             "server1",
 		Command:          "python",
 		Args:             []string{"-m", "server1"},
@@ -451,6 +868,7 @@ func TestManager_LoadMCPServers(t *testing.T) {
 		Metadata:    make(map[string]any),
 	}
 	server2 := &types.MCPServerConfig{
+		Schema:           "",
 		Name:             "server2",
 		Command:          "node",
 		Args:             []string{"server2.js"},
@@ -520,6 +938,89 @@ func TestManager_LoadMCPServers_CorruptedJSON(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to parse server config")
 }
 
+func TestManager_LoadMCPServers_UnknownField(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	serverJSON := `{
+  "$schema": "https://example.com/mcp-server.schema.json",
+  "name": "github",
+  "command": "github-mcp",
+  "transportType": "stdio",
+  "capabilities": {"tools": true, "resoruces": true}
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(".flows", "servers", "github.json"), []byte(serverJSON), 0o600))
+
+	_, err = manager.LoadMCPServers()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown field "resoruces", did you mean "resources"? (line 6, column 35)`)
+}
+
+func TestManager_LoadFlowFile_Strict(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	manager, err := config.NewManager()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{
+			name: "misspelled JSON key",
+			file: "typo.json",
+			content: `{
+  "id": "typo",
+  "name": "Typo",
+  "initialStep": "done",
+  "steps": {"done": {"type": "end", "nxt": "done"}}
+}`,
+			expected: `unknown field "nxt", did you mean "next"? (line 5, column 37)`,
+		},
+		{
+			name:     "misspelled YAML key",
+			file:     "typo.yaml",
+			content:  "id: typo\nname: Typo\nintialStep: done\nsteps:\n  done:\n    type: end\n",
+			expected: `unknown field "intialStep", did you mean "initialStep"? (line 3, column 1)`,
+		},
+		{
+			name:     "JSON syntax error",
+			file:     "broken.json",
+			content:  "{\n  \"id\": \"broken\",\n  \"name\" \"Broken\"\n}",
+			expected: "invalid JSON at line 3, column 11",
+		},
+		{
+			name:     "wrong type",
+			file:     "typed.json",
+			content:  "{\n  \"id\": \"typed\",\n  \"name\": \"Typed\",\n  \"steps\": {\"done\": {\"type\": \"end\", \"maxVisits\": \"2\"}}\n}",
+			expected: "expected int but found string (line 4, column 37)",
+		},
+		{
+			name:     "validation error",
+			file:     "dangling.yaml",
+			content:  "id: dangling\nname: Dangling\ninitialStep: ask\nsteps:\n  ask:\n    type: prompt\n    prompt: {template: Hi}\n    next: nowhere\n",
+			expected: "step references non-existent next step (line 8, column 5)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flowPath := filepath.Join(tmpDir, tt.file)
+			require.NoError(t, os.WriteFile(flowPath, []byte(tt.content), 0o600))
+
+			_, err := manager.LoadFlowFile(flowPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
 func TestManager_GetConfig(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir := t.TempDir()
@@ -576,10 +1077,15 @@ func BenchmarkManager_SaveFlow(b *testing.B) {
 				Model:      "",
 				Tools:      []string{},
 				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
 				Next:       "",
 				Conditions: []types.ConditionConfig{},
 				Timeout:    nil,
 				Retry:      nil,
+				MaxVisits:  0,
 				Metadata:   make(map[string]any),
 			},
 		},
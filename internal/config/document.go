@@ -0,0 +1,442 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// ErrTrailingData is returned for JSON documents with content after the top-level value.
+var ErrTrailingData = errors.New("unexpected data after the top-level value")
+
+// position is where a value or key starts in a document; line and column
+// start at 1 and the column counts characters.
+type position struct {
+	line   int
+	column int
+}
+
+// node is a value of a parsed document with where it starts. Objects keep
+// their fields in document order.
+type node struct {
+	start  position
+	object bool
+	fields []field
+	items  []*node
+}
+
+// field is a member of an object node.
+type field struct {
+	key   string
+	start position
+	value *node
+}
+
+// document is a parsed flow or MCP server file that remembers the position
+// of every value, so problems can be reported by line and column.
+type document struct {
+	root *node
+	// positions maps the JSON path of every value to where it starts; for
+	// object members that is the position of the key.
+	positions map[string]position
+}
+
+// parseDocument parses a JSON or YAML document.
+func parseDocument(data []byte, format string) (*document, error) {
+	var (
+		root *node
+		err  error
+	)
+
+	switch format {
+	case FormatJSON:
+		root, err = parseJSON(data)
+	case FormatYAML:
+		root, err = parseYAML(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFlowFormat, format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &document{root: root, positions: make(map[string]position)}
+	doc.index(root, "$", root.start)
+
+	return doc, nil
+}
+
+// index records the positions of a node and everything in it.
+func (d *document) index(value *node, path string, start position) {
+	d.positions[path] = start
+
+	for _, member := range value.fields {
+		d.index(member.value, types.JSONPath(path, member.key), member.start)
+	}
+
+	for index, item := range value.items {
+		d.index(item, fmt.Sprintf("%s[%d]", path, index), item.start)
+	}
+}
+
+// locate sets the position of diagnostics that have none from their path.
+// A path the document does not contain, such as a missing field, is
+// located at the closest enclosing value that exists.
+func (d *document) locate(diagnostics []types.Diagnostic) {
+	for index := range diagnostics {
+		diagnostic := &diagnostics[index]
+		if diagnostic.Line > 0 || diagnostic.Path == "" {
+			continue
+		}
+
+		path := diagnostic.Path
+
+		for {
+			if start, found := d.positions[path]; found {
+				diagnostic.Line = start.line
+				diagnostic.Column = start.column
+
+				break
+			}
+
+			cut := strings.LastIndexAny(path, ".[")
+			if cut <= 0 {
+				break
+			}
+
+			path = path[:cut]
+		}
+	}
+}
+
+// unknownFields reports every object key in the document that the Go type
+// it decodes into has no field for. These keys would otherwise be dropped
+// silently, so they are errors.
+func (d *document) unknownFields(typ reflect.Type) []types.Diagnostic {
+	return unknownFields(d.root, typ, "$")
+}
+
+// unknownFields walks a node alongside the Go type it decodes into.
+func unknownFields(value *node, typ reflect.Type, path string) []types.Diagnostic {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var found []types.Diagnostic
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if !value.object {
+			return nil
+		}
+
+		fields := jsonFields(typ)
+
+		for _, member := range value.fields {
+			memberPath := types.JSONPath(path, member.key)
+
+			fieldType, known := fields[member.key]
+			if !known {
+				found = append(found, unknownField(member, memberPath, fields))
+
+				continue
+			}
+
+			found = append(found, unknownFields(member.value, fieldType, memberPath)...)
+		}
+	case reflect.Map:
+		if !value.object || typ.Elem().Kind() == reflect.Interface {
+			return nil
+		}
+
+		for _, member := range value.fields {
+			found = append(found, unknownFields(member.value, typ.Elem(), types.JSONPath(path, member.key))...)
+		}
+	case reflect.Slice:
+		for index, item := range value.items {
+			found = append(found, unknownFields(item, typ.Elem(), fmt.Sprintf("%s[%d]", path, index))...)
+		}
+	default:
+	}
+
+	return found
+}
+
+// unknownField reports an object key the schema does not define, suggesting
+// the field it most likely misspells.
+func unknownField(member field, path string, fields map[string]reflect.Type) types.Diagnostic {
+	message := fmt.Sprintf("unknown field %q", member.key)
+	details := map[string]any{"field": member.key}
+
+	if suggestion := closestField(member.key, fields); suggestion != "" {
+		message += fmt.Sprintf(", did you mean %q?", suggestion)
+		details["suggestion"] = suggestion
+	}
+
+	return types.Diagnostic{
+		Code:     CodeUnknownField,
+		Message:  message,
+		StepID:   "",
+		Path:     path,
+		Severity: types.SeverityError,
+		Details:  details,
+		Line:     member.start.line,
+		Column:   member.start.column,
+	}
+}
+
+// maxSuggestionDistance is how many edits a key may be away from a field
+// for the field to be suggested.
+const maxSuggestionDistance = 2
+
+// closestField returns the known field closest to key, or "" when none is close.
+func closestField(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", maxSuggestionDistance+1
+
+	for name := range fields {
+		distance := editDistance(strings.ToLower(key), strings.ToLower(name))
+		if distance < bestDistance || (distance == bestDistance && name < best) {
+			best, bestDistance = name, distance
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for index := range previous {
+		previous[index] = index
+	}
+
+	for i := range source {
+		current[0] = i + 1
+
+		for j := range target {
+			cost := 1
+			if source[i] == target[j] {
+				cost = 0
+			}
+
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+// jsonFields returns the JSON names of the fields of a struct type with their types.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, typ.NumField())
+
+	for index := range typ.NumField() {
+		field := typ.Field(index)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+// parseYAML parses a YAML document into nodes.
+func parseYAML(data []byte) (*node, error) {
+	var root yaml.Node
+
+	err := yaml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	return yamlNode(&root), nil
+}
+
+// yamlNode converts a YAML node; aliases are replaced by the node they refer to.
+func yamlNode(value *yaml.Node) *node {
+	switch value.Kind {
+	case yaml.DocumentNode:
+		if len(value.Content) == 0 {
+			return &node{start: position{line: 1, column: 1}, object: false, fields: nil, items: nil}
+		}
+
+		return yamlNode(value.Content[0])
+	case yaml.AliasNode:
+		return yamlNode(value.Alias)
+	default:
+	}
+
+	converted := &node{
+		start:  position{line: value.Line, column: value.Column},
+		object: value.Kind == yaml.MappingNode,
+		fields: nil,
+		items:  nil,
+	}
+
+	switch value.Kind {
+	case yaml.MappingNode:
+		for index := 0; index+1 < len(value.Content); index += 2 {
+			key := value.Content[index]
+			converted.fields = append(converted.fields, field{
+				key:   key.Value,
+				start: position{line: key.Line, column: key.Column},
+				value: yamlNode(value.Content[index+1]),
+			})
+		}
+	case yaml.SequenceNode:
+		for _, item := range value.Content {
+			converted.items = append(converted.items, yamlNode(item))
+		}
+	default:
+	}
+
+	return converted
+}
+
+// jsonParser reads a JSON document token by token, tracking where tokens start.
+type jsonParser struct {
+	data       []byte
+	decoder    *json.Decoder
+	lineStarts []int
+}
+
+// parseJSON parses a JSON document into nodes.
+func parseJSON(data []byte) (*node, error) {
+	parser := &jsonParser{
+		data:       data,
+		decoder:    json.NewDecoder(bytes.NewReader(data)),
+		lineStarts: []int{0},
+	}
+
+	for offset, char := range data {
+		if char == '\n' {
+			parser.lineStarts = append(parser.lineStarts, offset+1)
+		}
+	}
+
+	root, err := parser.value()
+	if err != nil {
+		return nil, parser.syntaxError(err)
+	}
+
+	_, err = parser.decoder.Token()
+	if !errors.Is(err, io.EOF) {
+		start := parser.position(parser.tokenStart())
+
+		return nil, fmt.Errorf("invalid JSON at line %d, column %d: %w", start.line, start.column, ErrTrailingData)
+	}
+
+	return root, nil
+}
+
+// value parses the next value.
+func (p *jsonParser) value() (*node, error) {
+	start := p.position(p.tokenStart())
+
+	token, err := p.decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	parsed := &node{start: start, object: false, fields: nil, items: nil}
+
+	switch token {
+	case json.Delim('{'):
+		parsed.object = true
+
+		for p.decoder.More() {
+			keyStart := p.position(p.tokenStart())
+
+			key, err := p.decoder.Token()
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON: %w", err)
+			}
+
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+
+			parsed.fields = append(parsed.fields, field{key: fmt.Sprint(key), start: keyStart, value: value})
+		}
+	case json.Delim('['):
+		for p.decoder.More() {
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+
+			parsed.items = append(parsed.items, item)
+		}
+	default:
+		return parsed, nil
+	}
+
+	// Consume the closing delimiter.
+	_, err = p.decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return parsed, nil
+}
+
+// tokenStart returns the offset of the next token, skipping the whitespace
+// and separators the decoder has not consumed yet.
+func (p *jsonParser) tokenStart() int {
+	offset := int(p.decoder.InputOffset())
+	for offset < len(p.data) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+
+	return offset
+}
+
+// position converts a byte offset into a line and column.
+func (p *jsonParser) position(offset int) position {
+	line, atLineStart := slices.BinarySearch(p.lineStarts, offset)
+	if !atLineStart {
+		line--
+	}
+
+	return position{
+		line:   line + 1,
+		column: utf8.RuneCount(p.data[p.lineStarts[line]:offset]) + 1,
+	}
+}
+
+// syntaxError adds the line and column to a JSON syntax error.
+func (p *jsonParser) syntaxError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	start := p.position(min(int(syntaxErr.Offset), len(p.data)))
+
+	return fmt.Errorf("invalid JSON at line %d, column %d: %w", start.line, start.column, syntaxErr)
+}
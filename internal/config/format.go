@@ -0,0 +1,234 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Flow file formats.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+var (
+	// ErrUnknownFlowFormat is returned for flow files without a .json, .yaml or .yml extension.
+	ErrUnknownFlowFormat = errors.New("unknown flow file format: use a .json, .yaml or .yml extension")
+
+	// ErrDuplicateFlowID is returned when more than one flow file defines the same flow ID.
+	ErrDuplicateFlowID = errors.New("flow is defined in more than one file")
+)
+
+// flowExtensions maps the extensions of flow files to their format, in the
+// order flow files are looked up.
+var flowExtensions = []struct {
+	extension string
+	format    string
+}{
+	{extension: ".json", format: FormatJSON},
+	{extension: ".yaml", format: FormatYAML},
+	{extension: ".yml", format: FormatYAML},
+}
+
+// FlowFormat returns the format of a flow file from its extension.
+func FlowFormat(flowPath string) (string, error) {
+	extension := strings.ToLower(filepath.Ext(flowPath))
+
+	for _, candidate := range flowExtensions {
+		if candidate.extension == extension {
+			return candidate.format, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnknownFlowFormat, flowPath)
+}
+
+// IsFlowFile reports whether a path names a flow file rather than a flow ID.
+func IsFlowFile(arg string) bool {
+	_, err := FlowFormat(arg)
+
+	return err == nil
+}
+
+// FormatExtension returns the file extension flows are saved with in format.
+func FormatExtension(format string) (string, error) {
+	switch format {
+	case FormatJSON:
+		return ".json", nil
+	case FormatYAML:
+		return ".yaml", nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFlowFormat, format)
+	}
+}
+
+// DecodeFlow parses a flow document in format. YAML documents are converted
+// to their JSON equivalent first, so both formats decode and validate
+// exactly alike. Fields the flow schema does not define are rejected with
+// the line and column they are at.
+func DecodeFlow(data []byte, format string) (*types.FlowDefinition, error) {
+	flow, _, found := decodeFlowDocument(data, format)
+
+	err := types.FirstError(found)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flow document: %w", err)
+	}
+
+	return flow, nil
+}
+
+// EncodeFlow formats a flow definition as a document in format. Multi-line
+// strings such as prompt templates are written as YAML literal blocks.
+func EncodeFlow(flow *types.FlowDefinition, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(flow, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal flow: %w", err)
+	}
+
+	switch format {
+	case FormatJSON:
+		return data, nil
+	case FormatYAML:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFlowFormat, format)
+	}
+
+	// JSON is YAML: parse it into a node tree, which keeps the field order,
+	// and drop the JSON quoting so the encoder picks YAML styles.
+	var document yaml.Node
+
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert flow to YAML: %w", err)
+	}
+
+	restyle(&document)
+
+	var out bytes.Buffer
+
+	const yamlIndent = 2
+
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(yamlIndent)
+
+	err = encoder.Encode(&document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal flow: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal flow: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// flowJSON returns a flow document as JSON.
+func flowJSON(data []byte, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return data, nil
+	case FormatYAML:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFlowFormat, format)
+	}
+
+	var document yaml.Node
+
+	err := yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	value, err := yamlValue(&document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	data, err = json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	return data, nil
+}
+
+// yamlValue converts a YAML node into the value encoding/json would decode
+// from the equivalent JSON. Timestamps stay strings, as in JSON.
+func yamlValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+
+		return yamlValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.MappingNode:
+		object := make(map[string]any, len(node.Content)/2)
+
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			value, err := yamlValue(node.Content[index+1])
+			if err != nil {
+				return nil, err
+			}
+
+			object[node.Content[index].Value] = value
+		}
+
+		return object, nil
+	case yaml.SequenceNode:
+		list := make([]any, 0, len(node.Content))
+
+		for _, item := range node.Content {
+			value, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, value)
+		}
+
+		return list, nil
+	case yaml.ScalarNode:
+		tag := node.ShortTag()
+		if tag == "!!str" || tag == "!!timestamp" {
+			return node.Value, nil
+		}
+
+		var value any
+
+		err := node.Decode(&value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+
+		return value, nil
+	default:
+		return nil, nil
+	}
+}
+
+// restyle clears the styles of a node tree parsed from JSON and writes
+// multi-line strings as literal blocks.
+func restyle(node *yaml.Node) {
+	node.Style = 0
+
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str" && strings.Contains(node.Value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+
+	for _, child := range node.Content {
+		restyle(child)
+	}
+}
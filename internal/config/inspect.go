@@ -0,0 +1,213 @@
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/graph"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Diagnostic codes reported for flow files that do not match the flow schema.
+const (
+	CodeInvalidJSON  = "INVALID_JSON"
+	CodeInvalidYAML  = "INVALID_YAML"
+	CodeInvalidType  = "INVALID_TYPE"
+	CodeUnknownField = "UNKNOWN_FIELD"
+	// CodeUnsupportedVersion is reported for flows in a format version this
+	// build cannot read or migrate.
+	CodeUnsupportedVersion = "UNSUPPORTED_VERSION"
+)
+
+// FlowPath returns the path of the flow file with the given ID, in
+// whichever format it is stored. A flow that does not exist yet gets a JSON
+// path; a flow stored in more than one format is an error.
+func (cm *Manager) FlowPath(flowID string) (string, error) {
+	// Validate flowID contains no path separators
+	if strings.ContainsAny(flowID, "/\\") || strings.Contains(flowID, "..") {
+		return "", ErrInvalidFlowID
+	}
+
+	var found []string
+
+	for _, candidate := range flowExtensions {
+		flowPath := filepath.Join(cm.flowsDir, flowID+candidate.extension)
+
+		_, err := os.Stat(flowPath)
+		if err == nil {
+			found = append(found, flowPath)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return filepath.Join(cm.flowsDir, flowID+".json"), nil
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s is defined by %s", ErrDuplicateFlowID, flowID, strings.Join(found, " and "))
+	}
+}
+
+// FlowCheck reports problems in a flow beyond those InspectFlowFile finds
+// itself, such as references to resources that are not available.
+type FlowCheck func(flow *types.FlowDefinition) []types.Diagnostic
+
+// InspectFlowFile reads a JSON or YAML flow file and reports every problem
+// in it rather than stopping at the first: schema problems, then everything
+// FlowDefinition.Diagnose, graph.Analyze and checks find, each with the line
+// and column it was found at. The flow is nil when the file is not a flow
+// document at all; the error is only set when the file cannot be read.
+func (cm *Manager) InspectFlowFile(
+	flowPath string, checks ...FlowCheck,
+) (*types.FlowDefinition, []types.Diagnostic, error) {
+	format, err := FlowFormat(flowPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(flowPath) // #nosec G304
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read flow file %s: %w", flowPath, err)
+	}
+
+	flow, doc, found := decodeFlowDocument(data, format)
+	if flow == nil {
+		return nil, found, nil
+	}
+
+	found = append(found, flow.Diagnose(types.WithStepTypes(cm.stepTypes))...)
+	found = append(found, graph.Analyze(flow)...)
+
+	for _, check := range checks {
+		found = append(found, check(flow)...)
+	}
+
+	doc.locate(found)
+
+	return flow, found, nil
+}
+
+// decodeFlowDocument parses a flow document and decodes the flow in it.
+// Documents that are not valid JSON or YAML, values of the wrong type and
+// fields the flow schema does not define are reported as errors; the flow
+// is nil when the document does not decode at all. Documents in an older
+// format version are migrated to the current one first.
+func decodeFlowDocument(data []byte, format string) (*types.FlowDefinition, *document, []types.Diagnostic) {
+	doc, err := parseDocument(data, format)
+	if err != nil {
+		return nil, nil, []types.Diagnostic{syntaxDiagnostic(format, err)}
+	}
+
+	data, err = flowJSON(data, format)
+	if err != nil {
+		return nil, nil, []types.Diagnostic{syntaxDiagnostic(format, err)}
+	}
+
+	data, migrated, err := migrateFlow(data)
+	if err != nil {
+		found := []types.Diagnostic{versionDiagnostic(err)}
+		doc.locate(found)
+
+		return nil, doc, found
+	}
+
+	var flow types.FlowDefinition
+
+	err = json.Unmarshal(data, &flow)
+	if err != nil {
+		found := []types.Diagnostic{decodeDiagnostic(err)}
+		doc.locate(found)
+
+		return nil, doc, found
+	}
+
+	if !migrated {
+		return &flow, doc, doc.unknownFields(reflect.TypeFor[types.FlowDefinition]())
+	}
+
+	// Check the migrated document, which is what decoded, and locate what
+	// is found in the document as it was written, in its order.
+	checked, err := parseDocument(data, FormatJSON)
+	if err != nil {
+		return nil, doc, []types.Diagnostic{syntaxDiagnostic(FormatJSON, err)}
+	}
+
+	found := checked.unknownFields(reflect.TypeFor[types.FlowDefinition]())
+	for index := range found {
+		found[index].Line, found[index].Column = 0, 0
+	}
+
+	doc.locate(found)
+	slices.SortStableFunc(found, func(a, b types.Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+
+	return &flow, doc, found
+}
+
+// versionDiagnostic converts an unsupported format version into a diagnostic.
+func versionDiagnostic(err error) types.Diagnostic {
+	return types.Diagnostic{
+		Code:     CodeUnsupportedVersion,
+		Message:  err.Error(),
+		StepID:   "",
+		Path:     "$.version",
+		Severity: types.SeverityError,
+		Details:  map[string]any{"supported": []string{MinFlowVersion, CurrentFlowVersion}},
+		Line:     0,
+		Column:   0,
+	}
+}
+
+// syntaxDiagnostic converts a parse error into a diagnostic.
+func syntaxDiagnostic(format string, err error) types.Diagnostic {
+	code := CodeInvalidJSON
+	if format == FormatYAML {
+		code = CodeInvalidYAML
+	}
+
+	return types.Diagnostic{
+		Code:     code,
+		Message:  err.Error(),
+		StepID:   "",
+		Path:     "$",
+		Severity: types.SeverityError,
+		Details:  nil,
+		Line:     0,
+		Column:   0,
+	}
+}
+
+// decodeDiagnostic converts a JSON decoding error into a diagnostic.
+func decodeDiagnostic(err error) types.Diagnostic {
+	diagnostic := types.Diagnostic{
+		Code:     CodeInvalidJSON,
+		Message:  fmt.Sprintf("flow file is not valid JSON: %v", err),
+		StepID:   "",
+		Path:     "$",
+		Severity: types.SeverityError,
+		Details:  nil,
+		Line:     0,
+		Column:   0,
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		diagnostic.Code = CodeInvalidType
+		diagnostic.Message = fmt.Sprintf("expected %s but found %s", typeErr.Type, typeErr.Value)
+
+		if typeErr.Field != "" {
+			diagnostic.Path = "$." + typeErr.Field
+		}
+	}
+
+	return diagnostic
+}
@@ -0,0 +1,275 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Flow format versions. Flows are read as CurrentFlowVersion; flows as old
+// as MinFlowVersion are migrated to it in memory when they are loaded.
+// Flows without a version predate versioning and are MinFlowVersion.
+const (
+	MinFlowVersion     = "0.0"
+	CurrentFlowVersion = "1.0"
+)
+
+// errUnsupportedFlowVersion is reported as CodeUnsupportedVersion for flows
+// whose format version is malformed or outside MinFlowVersion to
+// CurrentFlowVersion.
+var errUnsupportedFlowVersion = errors.New("unsupported flow format version")
+
+// flowVersion is a flow format version, major.minor.
+type flowVersion struct {
+	major int
+	minor int
+}
+
+var (
+	minFlowVersion     = mustParseFlowVersion(MinFlowVersion)
+	currentFlowVersion = mustParseFlowVersion(CurrentFlowVersion)
+)
+
+// migration upgrades flow documents to the format version it names.
+type migration struct {
+	version     flowVersion
+	description string
+	apply       func(flow map[string]any)
+}
+
+// migrations upgrade flow documents one format version at a time, oldest
+// first. A change to the flow format that older flows do not load with
+// bumps CurrentFlowVersion and adds the migration to it here.
+var migrations = []migration{
+	{
+		version:     mustParseFlowVersion("1.0"),
+		description: "prompt steps given as a string become prompt objects",
+		apply:       migratePromptStrings,
+	},
+}
+
+// FlowMigration is the migration of a flow file to the current format version.
+type FlowMigration struct {
+	// Path is the flow file.
+	Path string
+	// From is the format version of the file; empty when it has none.
+	From string
+	// Applied describes the migrations that were applied, oldest first. It is
+	// empty when the file is already at the current format version.
+	Applied []string
+	// Original is the content of the file and Migrated its content at the
+	// current format version, in the same format.
+	Original []byte
+	Migrated []byte
+}
+
+// MigrateFlowFile upgrades a flow file to the current format version and
+// returns the migrated document without writing it. The migrated flow must
+// load; as with convert, comments and formatting are not carried over.
+func (cm *Manager) MigrateFlowFile(flowPath string) (*FlowMigration, error) {
+	format, err := FlowFormat(flowPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(flowPath) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flow file %s: %w", flowPath, err)
+	}
+
+	flow, doc, found := decodeFlowDocument(data, format)
+
+	err = types.FirstError(found)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flow definition: %w", err)
+	}
+
+	version, err := documentVersion(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := parseFlowVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FlowMigration{Path: flowPath, From: version, Applied: nil, Original: data, Migrated: data}
+	if !from.less(currentFlowVersion) {
+		return result, nil
+	}
+
+	result.Applied = pendingMigrations(from)
+
+	err = cm.validateFlow(flow, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Migrated, err = EncodeFlow(flow, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// migrateFlow upgrades a flow document in JSON to the current format
+// version and reports whether it had to. Documents that are not objects are
+// returned as they are, for decoding to report.
+func migrateFlow(data []byte) ([]byte, bool, error) {
+	var flow map[string]any
+
+	err := json.Unmarshal(data, &flow)
+	if err != nil || flow == nil {
+		return data, false, nil //nolint:nilerr // decoding reports documents that are not flow objects
+	}
+
+	version, err := flowDocumentVersion(flow)
+	if err != nil {
+		return nil, false, err
+	}
+
+	from, err := parseFlowVersion(version)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !from.less(currentFlowVersion) {
+		return data, false, nil
+	}
+
+	for _, step := range migrations {
+		if from.less(step.version) {
+			step.apply(flow)
+		}
+	}
+
+	flow["version"] = CurrentFlowVersion
+
+	data, err = json.Marshal(flow)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to migrate flow: %w", err)
+	}
+
+	return data, true, nil
+}
+
+// pendingMigrations describes the migrations a flow at version from needs.
+func pendingMigrations(from flowVersion) []string {
+	var pending []string
+
+	for _, step := range migrations {
+		if from.less(step.version) {
+			pending = append(pending, step.description)
+		}
+	}
+
+	return append(pending, "format version set to "+CurrentFlowVersion)
+}
+
+// documentVersion returns the format version a flow document declares.
+func documentVersion(data []byte, format string) (string, error) {
+	data, err := flowJSON(data, format)
+	if err != nil {
+		return "", err
+	}
+
+	var flow map[string]any
+
+	err = json.Unmarshal(data, &flow)
+	if err != nil {
+		return "", fmt.Errorf("invalid flow document: %w", err)
+	}
+
+	return flowDocumentVersion(flow)
+}
+
+// flowDocumentVersion returns the version field of a decoded flow document.
+func flowDocumentVersion(flow map[string]any) (string, error) {
+	switch version := flow["version"].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return version, nil
+	default:
+		return "", fmt.Errorf("%w %v: the version must be a string such as %q",
+			errUnsupportedFlowVersion, version, CurrentFlowVersion)
+	}
+}
+
+// parseFlowVersion parses a flow format version and checks that it is
+// supported. An empty version is MinFlowVersion and a bare major version
+// has minor version 0.
+func parseFlowVersion(version string) (flowVersion, error) {
+	if version == "" {
+		return minFlowVersion, nil
+	}
+
+	majorText, minorText, hasMinor := strings.Cut(version, ".")
+	if !hasMinor {
+		minorText = "0"
+	}
+
+	major, majorErr := strconv.Atoi(majorText)
+	minor, minorErr := strconv.Atoi(minorText)
+
+	parsed := flowVersion{major: major, minor: minor}
+
+	switch {
+	case majorErr != nil || minorErr != nil || major < 0 || minor < 0:
+		return parsed, fmt.Errorf("%w %q: expected major.minor, such as %q",
+			errUnsupportedFlowVersion, version, CurrentFlowVersion)
+	case parsed.less(minFlowVersion) || currentFlowVersion.less(parsed):
+		return parsed, fmt.Errorf("%w %q: flow-test-go reads versions %s to %s",
+			errUnsupportedFlowVersion, version, MinFlowVersion, CurrentFlowVersion)
+	default:
+		return parsed, nil
+	}
+}
+
+// mustParseFlowVersion parses a flow format version known to be valid.
+func mustParseFlowVersion(version string) flowVersion {
+	majorText, minorText, _ := strings.Cut(version, ".")
+
+	major, majorErr := strconv.Atoi(majorText)
+	minor, minorErr := strconv.Atoi(minorText)
+
+	if majorErr != nil || minorErr != nil {
+		panic("invalid flow format version " + version)
+	}
+
+	return flowVersion{major: major, minor: minor}
+}
+
+// less reports whether v is an earlier version than other.
+func (v flowVersion) less(other flowVersion) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+
+	return v.minor < other.minor
+}
+
+// migratePromptStrings turns prompts given as a bare string, as flows did
+// before format versions were recorded, into prompt objects with that
+// template.
+func migratePromptStrings(flow map[string]any) {
+	steps, _ := flow["steps"].(map[string]any)
+
+	for _, value := range steps {
+		step, isObject := value.(map[string]any)
+		if !isObject {
+			continue
+		}
+
+		if template, isString := step["prompt"].(string); isString {
+			step["prompt"] = map[string]any{"template": template}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package checkpoint persists the state of flow runs so that an interrupted
+// run can be resumed from the step it stopped at.
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const (
+	dirPerms      = 0o750
+	fileExtension = ".json"
+)
+
+var (
+	// ErrNotFound is returned when no checkpoint exists for a session.
+	ErrNotFound = errors.New("checkpoint not found")
+
+	// ErrInvalidSessionID is returned for session IDs that are empty or contain path separators.
+	ErrInvalidSessionID = errors.New("invalid session ID")
+)
+
+// Checkpoint is the persisted state of a run: the flow as it was executed
+// and the execution context after the last finished step.
+type Checkpoint struct {
+	Flow      *types.FlowDefinition   `json:"flow"`
+	Execution *types.ExecutionContext `json:"execution"`
+	SavedAt   time.Time               `json:"savedAt"`
+}
+
+// Store keeps one checkpoint file per session in a directory.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store that writes checkpoints to dir.
+// The directory is created on the first save.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory checkpoints are written to.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save atomically replaces the checkpoint of the run's session: the state is
+// written to a temporary file that is renamed over the previous checkpoint,
+// so a crash never leaves a partially written file behind.
+func (s *Store) Save(flow *types.FlowDefinition, execCtx *types.ExecutionContext) error {
+	path, err := s.path(execCtx.SessionID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(Checkpoint{Flow: flow, Execution: execCtx, SavedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	err = os.MkdirAll(s.dir, dirPerms)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, execCtx.SessionID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the checkpoint of a session.
+func (s *Store) Load(sessionID string) (*Checkpoint, error) {
+	path, err := s.path(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, sessionID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", sessionID, err)
+	}
+
+	var checkpoint Checkpoint
+
+	err = json.Unmarshal(data, &checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", sessionID, err)
+	}
+
+	if checkpoint.Flow == nil || checkpoint.Execution == nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: flow and execution are required", sessionID)
+	}
+
+	return &checkpoint, nil
+}
+
+// path returns the checkpoint file of a session.
+func (s *Store) path(sessionID string) (string, error) {
+	if sessionID == "" || strings.ContainsAny(sessionID, "/\\") || strings.Contains(sessionID, "..") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSessionID, sessionID)
+	}
+
+	return filepath.Join(s.dir, sessionID+fileExtension), nil
+}
@@ -0,0 +1,78 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package checkpoint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/checkpoint"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "checkpoints")
+	store := checkpoint.NewStore(dir)
+
+	flow := &types.FlowDefinition{
+		ID:          "review",
+		Name:        "Review",
+		InitialStep: "fetch",
+		Steps: map[string]types.Step{
+			"fetch": {Type: types.StepTypeTool, Tool: &types.ToolConfig{Name: "fetch"}, Next: "done"},
+			"done":  {Type: types.StepTypeEnd},
+		},
+	}
+	execCtx := &types.ExecutionContext{
+		FlowID:      "review",
+		SessionID:   "20250110T093000-abc",
+		CurrentStep: "done",
+		Variables:   map[string]any{"repo": "flow-test-go"},
+		StepResults: map[string]types.StepResult{
+			"fetch": {StepID: "fetch", Status: types.StepStatusCompleted, Output: "3 files", Duration: time.Second},
+		},
+		Status: types.StatusRunning,
+	}
+
+	require.NoError(t, store.Save(flow, execCtx))
+
+	execCtx.CurrentStep = "fetch"
+	require.NoError(t, store.Save(flow, execCtx), "saving again replaces the checkpoint")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files are left behind")
+	assert.Equal(t, "20250110T093000-abc.json", entries[0].Name())
+
+	saved, err := store.Load("20250110T093000-abc")
+	require.NoError(t, err)
+	assert.Equal(t, "fetch", saved.Execution.CurrentStep)
+	assert.Equal(t, "3 files", saved.Execution.StepResults["fetch"].Output)
+	assert.Equal(t, time.Second, saved.Execution.StepResults["fetch"].Duration)
+	assert.Equal(t, "fetch", saved.Flow.Steps["fetch"].Tool.Name)
+	assert.NotZero(t, saved.SavedAt)
+}
+
+func TestStore_LoadErrors(t *testing.T) {
+	t.Parallel()
+
+	store := checkpoint.NewStore(t.TempDir())
+
+	_, err := store.Load("missing")
+	require.ErrorIs(t, err, checkpoint.ErrNotFound)
+
+	_, err = store.Load("../escape")
+	require.ErrorIs(t, err, checkpoint.ErrInvalidSessionID)
+
+	require.ErrorIs(t, store.Save(&types.FlowDefinition{}, &types.ExecutionContext{}), checkpoint.ErrInvalidSessionID)
+
+	require.NoError(t, os.WriteFile(filepath.Join(store.Dir(), "broken.json"), []byte("{"), 0o600))
+	_, err = store.Load("broken")
+	require.Error(t, err)
+}
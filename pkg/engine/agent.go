@@ -0,0 +1,240 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// toolBinding pairs a tool with the caller that runs it.
+type toolBinding struct {
+	tool   types.MCPTool
+	caller ToolCaller
+}
+
+// toolset holds the tools a prompt step exposes to the model.
+type toolset struct {
+	definitions []llm.Tool
+	bindings    map[string]toolBinding
+}
+
+// agentLoop tracks the conversation, usage and tool calls of one prompt step.
+type agentLoop struct {
+	stepID   string
+	toolset  *toolset
+	messages []llm.Message
+	calls    []types.MCPToolCall
+	results  []types.MCPToolResult
+	usage    llm.Usage
+	cost     float64
+}
+
+func newAgentLoop(stepID string, set *toolset, messages []llm.Message) *agentLoop {
+	return &agentLoop{
+		stepID:   stepID,
+		toolset:  set,
+		messages: messages,
+		calls:    nil,
+		results:  nil,
+		usage:    llm.Usage{PromptTokens: 0, CompletionTokens: 0, TotalTokens: 0},
+		cost:     0,
+	}
+}
+
+// record adds the usage and cost of a completion to the step totals.
+func (l *agentLoop) record(resp *llm.Response) {
+	l.usage.PromptTokens += resp.Usage.PromptTokens
+	l.usage.CompletionTokens += resp.Usage.CompletionTokens
+	l.usage.TotalTokens += resp.Usage.TotalTokens
+	l.cost += resp.Cost
+}
+
+// callTools runs every tool call of a completion and appends the assistant
+// message and the tool answers to the conversation. Tool failures, unknown
+// tools and malformed arguments are reported back to the model; only a
+// failure to reach the tool at all fails the step.
+func (l *agentLoop) callTools(ctx context.Context, resp *llm.Response) error {
+	l.messages = append(l.messages, llm.Message{
+		Role:       llm.RoleAssistant,
+		Content:    resp.Content,
+		ToolCalls:  resp.ToolCalls,
+		ToolCallID: "",
+	})
+
+	for _, toolCall := range resp.ToolCalls {
+		result, err := l.callTool(ctx, toolCall)
+		if err != nil {
+			return err
+		}
+
+		l.messages = append(l.messages, llm.Message{
+			Role:       llm.RoleTool,
+			Content:    toolMessageContent(result),
+			ToolCalls:  nil,
+			ToolCallID: toolCall.ID,
+		})
+	}
+
+	return nil
+}
+
+// callTool runs one tool call requested by the model and records it.
+func (l *agentLoop) callTool(ctx context.Context, toolCall llm.ToolCall) (*types.MCPToolResult, error) {
+	callID := toolCall.ID
+	if callID == "" {
+		callID = newCallID(l.stepID)
+	}
+
+	name := toolCall.Function.Name
+	binding, declared := l.toolset.bindings[name]
+
+	call := types.MCPToolCall{
+		ID:         callID,
+		ToolName:   name,
+		ServerName: binding.tool.ServerName,
+		Arguments:  nil,
+		Timestamp:  time.Now(),
+		Metadata:   nil,
+	}
+
+	arguments, err := decodeToolArguments(toolCall.Function.Arguments)
+	call.Arguments = arguments
+
+	var result *types.MCPToolResult
+
+	switch {
+	case !declared:
+		result = failedToolResult(callID, CodeToolNotFound, fmt.Sprintf("tool %s is not available to this step", name))
+	case err != nil:
+		call.Metadata = map[string]any{"rawArguments": toolCall.Function.Arguments}
+		result = failedToolResult(callID, CodeInvalidToolRequest, fmt.Sprintf("invalid arguments for tool %s: %v", name, err))
+	default:
+		spanCtx, span := startToolSpan(ctx, call)
+		result, err = binding.caller.CallTool(spanCtx, call)
+		endToolSpan(span, result, err)
+
+		if err != nil {
+			err = fmt.Errorf("tool %s on %s failed: %w", name, call.ServerName, err)
+
+			// The call stays on record, so the failed step shows what broke it.
+			l.calls = append(l.calls, call)
+			l.results = append(l.results, *failedToolResult(callID, CodeToolUnavailable, err.Error()))
+
+			return nil, err
+		}
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, "model called tool", "tool", name, "server", call.ServerName,
+		"arguments", call.Arguments, "success", result.Success)
+
+	l.calls = append(l.calls, call)
+	l.results = append(l.results, *result)
+
+	return result, nil
+}
+
+// outcome builds the step outcome from the final completion.
+func (l *agentLoop) outcome(next string, resp *llm.Response, model string, iterations int) *StepOutcome {
+	if resp.Model != "" {
+		model = resp.Model
+	}
+
+	metadata := l.metadata(model, iterations)
+	metadata["finishReason"] = resp.FinishReason
+
+	return &StepOutcome{
+		Output:     resp.Content,
+		Next:       next,
+		TokensUsed: l.usage.TotalTokens,
+		Cost:       l.cost,
+		Metadata:   metadata,
+		Paused:     false,
+	}
+}
+
+// partial builds the outcome of a step that failed after iterations
+// completions: the usage and tool calls so far, without an answer.
+func (l *agentLoop) partial(model string, iterations int) *StepOutcome {
+	return &StepOutcome{
+		Output:     nil,
+		Next:       "",
+		TokensUsed: l.usage.TotalTokens,
+		Cost:       l.cost,
+		Metadata:   l.metadata(model, iterations),
+		Paused:     false,
+	}
+}
+
+// metadata returns the step metadata recording the model, usage and tool calls so far.
+func (l *agentLoop) metadata(model string, iterations int) map[string]any {
+	metadata := map[string]any{
+		"model":            model,
+		"promptTokens":     l.usage.PromptTokens,
+		"completionTokens": l.usage.CompletionTokens,
+	}
+
+	if len(l.toolset.definitions) > 0 {
+		metadata["iterations"] = iterations
+		metadata["toolCalls"] = l.calls
+		metadata["toolResults"] = l.results
+	}
+
+	return metadata
+}
+
+// decodeToolArguments parses the JSON object the model passed as tool arguments.
+func decodeToolArguments(raw string) (map[string]any, error) {
+	arguments := map[string]any{}
+	if raw == "" {
+		return arguments, nil
+	}
+
+	err := json.Unmarshal([]byte(raw), &arguments)
+	if err != nil {
+		return nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+	}
+
+	return arguments, nil
+}
+
+// failedToolResult creates the result reported to the model for a call that could not run.
+func failedToolResult(callID, code, message string) *types.MCPToolResult {
+	now := time.Now()
+
+	return &types.MCPToolResult{
+		CallID:    callID,
+		Success:   false,
+		Result:    nil,
+		Error:     newExecutionError(code, message, nil),
+		Duration:  0,
+		Timestamp: now,
+		Metadata:  nil,
+	}
+}
+
+// toolMessageContent renders a tool result as the content of a tool message.
+func toolMessageContent(result *types.MCPToolResult) string {
+	if !result.Success {
+		if result.Error != nil {
+			return "error: " + result.Error.Message
+		}
+
+		return "error: tool call failed"
+	}
+
+	if text, ok := result.Result.(string); ok {
+		return text
+	}
+
+	data, err := json.Marshal(result.Result)
+	if err != nil {
+		return fmt.Sprint(result.Result)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,251 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var errServerGone = errors.New("MCP server exited")
+
+// scriptedProvider answers each request with the next scripted response
+// and repeats the last one when the script runs out.
+type scriptedProvider struct {
+	requests  []*llm.Request
+	responses []*llm.Response
+}
+
+func (s *scriptedProvider) Complete(_ context.Context, req *llm.Request) (*llm.Response, error) {
+	s.requests = append(s.requests, &llm.Request{
+		Model:    req.Model,
+		Messages: append([]llm.Message(nil), req.Messages...),
+		Tools:    req.Tools,
+	})
+
+	index := min(len(s.requests), len(s.responses)) - 1
+
+	return s.responses[index], nil
+}
+
+func toolCallResponse(calls ...llm.ToolCall) *llm.Response {
+	return &llm.Response{
+		ToolCalls:    calls,
+		FinishReason: "tool_calls",
+		Usage:        llm.Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12},
+		Cost:         0.001,
+	}
+}
+
+func functionCall(id, name, arguments string) llm.ToolCall {
+	return llm.ToolCall{
+		ID:       id,
+		Type:     llm.ToolCallTypeFunction,
+		Function: llm.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+func newAgentTools(t *testing.T) *tools.Registry {
+	t.Helper()
+
+	registry := tools.NewRegistry()
+	require.NoError(t, registry.Register(tools.Func{
+		Name:        "add",
+		Description: "Add two numbers",
+		Schema:      map[string]any{"type": "object", "required": []any{"a", "b"}},
+		Fn: func(_ context.Context, arguments map[string]any) (any, error) {
+			a, _ := arguments["a"].(float64)
+			b, _ := arguments["b"].(float64)
+
+			return a + b, nil
+		},
+	}))
+	require.NoError(t, registry.Register(tools.Func{Name: "delete_everything"}))
+
+	return registry
+}
+
+func runAgentFlow(
+	t *testing.T,
+	provider llm.Provider,
+	settings engine.PromptSettings,
+	embedded, servers engine.ToolCatalog,
+	server string,
+) (*types.ExecutionContext, error) {
+	t.Helper()
+
+	flow := newPromptFlow("")
+	step := flow.Steps["ask"]
+	step.Tools = []string{"add"}
+	step.MCPServer = server
+	flow.Steps["ask"] = step
+
+	executor := engine.NewPromptExecutor(provider, settings)
+	executor.SetTools(embedded, servers)
+
+	return newTestEngine(t, stepExecutors{types.StepTypePrompt: executor}).Run(context.Background(), flow, nil)
+}
+
+func TestPromptExecutor_ToolLoop(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{responses: []*llm.Response{
+		toolCallResponse(
+			functionCall("call-1", "add", `{"a": 2, "b": 3}`),
+			functionCall("call-2", "delete_everything", `{}`),
+			functionCall("call-3", "add", `not json`),
+		),
+		{Content: "The sum is 5.", FinishReason: "stop", Usage: llm.Usage{TotalTokens: 20}, Cost: 0.002},
+	}}
+
+	execCtx, err := runAgentFlow(t, provider, engine.PromptSettings{}, newAgentTools(t), nil, "")
+	require.NoError(t, err)
+
+	require.Len(t, provider.requests, 2)
+	require.Len(t, provider.requests[0].Tools, 1, "only declared tools are exposed")
+	assert.Equal(t, "add", provider.requests[0].Tools[0].Name)
+	assert.Equal(t, "object", provider.requests[0].Tools[0].Parameters["type"])
+
+	messages := provider.requests[1].Messages
+	require.Len(t, messages, 6)
+	assert.Equal(t, llm.RoleAssistant, messages[2].Role)
+	assert.Len(t, messages[2].ToolCalls, 3)
+	assert.Equal(t, llm.Message{Role: llm.RoleTool, Content: "5", ToolCallID: "call-1"}, messages[3])
+	assert.Contains(t, messages[4].Content, "not available")
+	assert.Contains(t, messages[5].Content, "invalid arguments")
+
+	result := execCtx.StepResults["ask"]
+	assert.Equal(t, "The sum is 5.", result.Output)
+	assert.Equal(t, 32, result.TokensUsed)
+	assert.InDelta(t, 0.003, result.Cost, 1e-9)
+	assert.Equal(t, 2, result.Metadata["iterations"])
+
+	calls, ok := result.Metadata["toolCalls"].([]types.MCPToolCall)
+	require.True(t, ok)
+	require.Len(t, calls, 3)
+	assert.Equal(t, tools.EmbeddedServer, calls[0].ServerName)
+	assert.Equal(t, map[string]any{"a": 2.0, "b": 3.0}, calls[0].Arguments)
+
+	results, ok := result.Metadata["toolResults"].([]types.MCPToolResult)
+	require.True(t, ok)
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Success)
+	assert.Equal(t, engine.CodeToolNotFound, results[1].Error.Code)
+	assert.Equal(t, engine.CodeInvalidToolRequest, results[2].Error.Code)
+}
+
+func TestPromptExecutor_ToolLoopLimit(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{responses: []*llm.Response{
+		toolCallResponse(functionCall("call", "add", `{"a": 1, "b": 1}`)),
+	}}
+
+	execCtx, err := runAgentFlow(t, provider, engine.PromptSettings{MaxToolIterations: 3}, newAgentTools(t), nil, "")
+	require.Error(t, err)
+
+	assert.Len(t, provider.requests, 3)
+	assert.Equal(t, engine.CodeToolLimitExceeded, execCtx.Error.Code)
+
+	result := execCtx.StepResults["ask"]
+	assert.Equal(t, types.StepStatusFailed, result.Status)
+	assert.Equal(t, 36, result.TokensUsed, "the failed step keeps the usage of every completion")
+	assert.InDelta(t, 0.003, result.Cost, 1e-9)
+	assert.Equal(t, 3, result.Metadata["iterations"])
+
+	calls, ok := result.Metadata["toolCalls"].([]types.MCPToolCall)
+	require.True(t, ok)
+	require.Len(t, calls, 3)
+	assert.Equal(t, "add", calls[2].ToolName)
+
+	results, ok := result.Metadata["toolResults"].([]types.MCPToolResult)
+	require.True(t, ok)
+	require.Len(t, results, 3)
+	assert.True(t, results[2].Success)
+}
+
+func TestPromptExecutor_ToolCallerErrorKeepsRecord(t *testing.T) {
+	t.Parallel()
+
+	servers := &fakeCatalog{
+		fakeCaller: fakeCaller{err: errServerGone},
+		tools:      []types.MCPTool{{Name: "add", ServerName: "calculator"}},
+	}
+
+	provider := &scriptedProvider{responses: []*llm.Response{
+		toolCallResponse(functionCall("call-1", "add", `{"a": 2, "b": 2}`)),
+	}}
+
+	execCtx, err := runAgentFlow(t, provider, engine.PromptSettings{}, newAgentTools(t), servers, "calculator")
+	require.Error(t, err)
+	assert.Contains(t, execCtx.Error.Message, errServerGone.Error())
+
+	result := execCtx.StepResults["ask"]
+	assert.Equal(t, types.StepStatusFailed, result.Status)
+	assert.Equal(t, 12, result.TokensUsed)
+	assert.Equal(t, 1, result.Metadata["iterations"])
+
+	calls, ok := result.Metadata["toolCalls"].([]types.MCPToolCall)
+	require.True(t, ok)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "call-1", calls[0].ID)
+
+	results, ok := result.Metadata["toolResults"].([]types.MCPToolResult)
+	require.True(t, ok)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Success)
+	assert.Equal(t, engine.CodeToolUnavailable, results[0].Error.Code)
+}
+
+func TestPromptExecutor_MCPServerTools(t *testing.T) {
+	t.Parallel()
+
+	servers := &fakeCatalog{
+		fakeCaller: fakeCaller{result: &types.MCPToolResult{Success: true, Result: map[string]any{"sum": 4}}},
+		tools:      []types.MCPTool{{Name: "add", ServerName: "calculator"}},
+	}
+
+	provider := &scriptedProvider{responses: []*llm.Response{
+		toolCallResponse(functionCall("call-1", "add", `{"a": 2, "b": 2}`)),
+		{Content: "4", FinishReason: "stop"},
+	}}
+
+	execCtx, err := runAgentFlow(t, provider, engine.PromptSettings{}, newAgentTools(t), servers, "calculator")
+	require.NoError(t, err)
+
+	require.Len(t, servers.calls, 1, "the server tool shadows the embedded tool of the same name")
+	assert.Equal(t, "calculator", servers.calls[0].ServerName)
+	assert.Equal(t, `{"sum":4}`, provider.requests[1].Messages[3].Content)
+	assert.Equal(t, "4", execCtx.StepResults["ask"].Output)
+}
+
+func TestPromptExecutor_DeclaredToolMissing(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{responses: []*llm.Response{{Content: "unused"}}}
+
+	execCtx, err := runAgentFlow(t, provider, engine.PromptSettings{}, tools.NewRegistry(), nil, "")
+	require.Error(t, err)
+
+	assert.Equal(t, engine.CodeToolNotFound, execCtx.Error.Code)
+	assert.Empty(t, provider.requests)
+}
+
+// fakeCatalog is a fakeCaller that lists a fixed set of tools.
+type fakeCatalog struct {
+	fakeCaller
+
+	tools []types.MCPTool
+}
+
+func (f *fakeCatalog) ListTools(_ context.Context, _ string) ([]types.MCPTool, error) {
+	return f.tools, nil
+}
@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// CodeInvalidApproval is reported for approval steps without a question.
+const CodeInvalidApproval = "INVALID_APPROVAL"
+
+// Decisions accepted by Approve.
+const (
+	DecisionYes = "yes"
+	DecisionNo  = "no"
+)
+
+// approvalsKey is the ExecutionContext.Metadata entry that holds recorded
+// decisions, keyed by step ID, until their approval step consumes them.
+const approvalsKey = "approvals"
+
+var (
+	// ErrNotAwaitingApproval is returned when approving a run that is not paused at an approval step.
+	ErrNotAwaitingApproval = errors.New("run is not waiting for an approval")
+
+	// ErrInvalidDecision is returned for decisions other than DecisionYes and DecisionNo.
+	ErrInvalidDecision = errors.New("decision must be yes or no")
+)
+
+// ApprovalSettings configures how approval steps render their question.
+type ApprovalSettings struct {
+	// StrictTemplates fails question rendering on references to missing keys.
+	StrictTemplates bool
+}
+
+// ApprovalExecutor pauses the run at an approval step until a person records
+// a decision with Approve. When the run is resumed, the step outputs the
+// question, the decision and the comment so that later conditions can
+// branch on them, e.g. steps.review.output.approved.
+type ApprovalExecutor struct {
+	settings ApprovalSettings
+}
+
+// NewApprovalExecutor creates an approval step executor.
+func NewApprovalExecutor(settings ApprovalSettings) *ApprovalExecutor {
+	return &ApprovalExecutor{settings: settings}
+}
+
+// Execute pauses the run with the rendered question as output, or completes
+// the step with the decision recorded for it.
+func (a *ApprovalExecutor) Execute(_ context.Context, req *StepRequest) (*StepOutcome, error) {
+	question, err := renderQuestion(req, a.settings.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, decided := takeDecision(req.Execution, req.StepID)
+	if !decided {
+		return &StepOutcome{
+			Output:     map[string]any{"question": question},
+			Next:       "",
+			TokensUsed: 0,
+			Cost:       0,
+			Metadata:   nil,
+			Paused:     true,
+		}, nil
+	}
+
+	return &StepOutcome{
+		Output: map[string]any{
+			"question":  question,
+			"decision":  decision["decision"],
+			"approved":  decision["decision"] == DecisionYes,
+			"comment":   decision["comment"],
+			"decidedAt": decision["decidedAt"],
+		},
+		Next:       req.Step.Next,
+		TokensUsed: 0,
+		Cost:       0,
+		Metadata:   nil,
+		Paused:     false,
+	}, nil
+}
+
+// Approve records decision and comment for the approval step that the paused
+// run waits on. The run continues from that step when it is resumed.
+func Approve(flow *types.FlowDefinition, execCtx *types.ExecutionContext, decision, comment string) error {
+	if decision != DecisionYes && decision != DecisionNo {
+		return fmt.Errorf("%w: %q", ErrInvalidDecision, decision)
+	}
+
+	stepID := execCtx.CurrentStep
+
+	_, waiting := PendingQuestion(flow, execCtx)
+	if !waiting {
+		return fmt.Errorf("%w: session %s is %s at step %s",
+			ErrNotAwaitingApproval, execCtx.SessionID, execCtx.Status, stepID)
+	}
+
+	if execCtx.Metadata == nil {
+		execCtx.Metadata = make(map[string]any)
+	}
+
+	approvals, ok := execCtx.Metadata[approvalsKey].(map[string]any)
+	if !ok {
+		approvals = make(map[string]any)
+		execCtx.Metadata[approvalsKey] = approvals
+	}
+
+	approvals[stepID] = map[string]any{
+		"decision":  decision,
+		"comment":   comment,
+		"decidedAt": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	return nil
+}
+
+// renderQuestion renders the question of an approval step against the run scope.
+func renderQuestion(req *StepRequest, strict bool) (string, error) {
+	config := req.Step.Approval
+	if config == nil || config.Question == "" {
+		return "", newExecutionError(CodeInvalidApproval, "approval step has no question", nil)
+	}
+
+	return RenderTemplate(req.StepID+".question", config.Question, NewScope(req.Flow, req.Execution), strict)
+}
+
+// PendingQuestion returns the question of the approval step a paused run waits on.
+func PendingQuestion(flow *types.FlowDefinition, execCtx *types.ExecutionContext) (string, bool) {
+	if execCtx.Status != types.StatusPaused || flow.Steps[execCtx.CurrentStep].Type != types.StepTypeApproval {
+		return "", false
+	}
+
+	result, exists := execCtx.StepResults[execCtx.CurrentStep]
+	if !exists || result.Status != types.StepStatusPending {
+		return "", false
+	}
+
+	output, _ := normalize(result.Output).(map[string]any)
+	question, _ := output["question"].(string)
+
+	return question, true
+}
+
+// takeDecision removes and returns the decision recorded for stepID, so an
+// approval step that runs again in a loop asks again.
+func takeDecision(execCtx *types.ExecutionContext, stepID string) (map[string]any, bool) {
+	approvals, ok := execCtx.Metadata[approvalsKey].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	decision, ok := approvals[stepID].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	delete(approvals, stepID)
+
+	if len(approvals) == 0 {
+		delete(execCtx.Metadata, approvalsKey)
+	}
+
+	return decision, true
+}
@@ -0,0 +1,148 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+func newApprovalFlow() *types.FlowDefinition {
+	return newTestFlow("publish", "review", map[string]types.Step{
+		"review": {
+			Type:     types.StepTypeApproval,
+			Approval: &types.ApprovalConfig{Question: "Publish {{.vars.version}}?"},
+			Next:     "route",
+		},
+		"route": {
+			Type: types.StepTypeCondition,
+			Conditions: []types.ConditionConfig{
+				{Expression: "steps.review.output.approved", Next: "published"},
+				{Expression: "steps.review.output.decision == \"no\"", Next: "rejected"},
+			},
+		},
+		"published": {Type: types.StepTypeEnd},
+		"rejected":  {Type: types.StepTypeEnd},
+	})
+}
+
+// approvalExecutors runs approval steps with settings.
+func approvalExecutors(settings engine.ApprovalSettings) stepExecutors {
+	return stepExecutors{types.StepTypeApproval: engine.NewApprovalExecutor(settings)}
+}
+
+func TestEngine_Run_PausesAtApproval(t *testing.T) {
+	t.Parallel()
+
+	checkpointer := &recordingCheckpointer{}
+	flow := newApprovalFlow()
+	flowEngine := newTestEngine(t, approvalExecutors(engine.ApprovalSettings{}))
+	flowEngine.SetCheckpointer(checkpointer)
+
+	execCtx, err := flowEngine.Run(context.Background(), flow, map[string]any{"version": "v1.2.0"})
+	require.NoError(t, err)
+
+	assert.Equal(t, types.StatusPaused, execCtx.Status)
+	assert.Equal(t, "review", execCtx.CurrentStep)
+	assert.Equal(t, types.StepStatusPending, execCtx.StepResults["review"].Status)
+	assert.Equal(t, []string{"review:paused"}, checkpointer.saved)
+
+	question, waiting := engine.PendingQuestion(flow, execCtx)
+	assert.True(t, waiting)
+	assert.Equal(t, "Publish v1.2.0?", question)
+}
+
+func TestEngine_Resume_AfterApproval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		decision string
+		end      string
+		approved bool
+	}{
+		{name: "approved", decision: engine.DecisionYes, end: "published", approved: true},
+		{name: "rejected", decision: engine.DecisionNo, end: "rejected", approved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flow := newApprovalFlow()
+			flowEngine := newTestEngine(t, approvalExecutors(engine.ApprovalSettings{}))
+
+			execCtx, err := flowEngine.Run(context.Background(), flow, map[string]any{"version": "v1.2.0"})
+			require.NoError(t, err)
+
+			require.NoError(t, engine.Approve(flow, execCtx, tt.decision, "looks good"))
+			require.NoError(t, flowEngine.Resume(context.Background(), flow, execCtx))
+
+			assert.Equal(t, types.StatusCompleted, execCtx.Status)
+			assert.Equal(t, tt.end, execCtx.CurrentStep)
+
+			output, ok := execCtx.StepResults["review"].Output.(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, tt.decision, output["decision"])
+			assert.Equal(t, tt.approved, output["approved"])
+			assert.Equal(t, "looks good", output["comment"])
+			assert.Equal(t, "Publish v1.2.0?", output["question"])
+			assert.NotContains(t, execCtx.Metadata, "approvals", "the decision is consumed by the step")
+		})
+	}
+}
+
+func TestApprove_Errors(t *testing.T) {
+	t.Parallel()
+
+	flow := newApprovalFlow()
+
+	execCtx, err := newTestEngine(t, approvalExecutors(engine.ApprovalSettings{})).Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, engine.Approve(flow, execCtx, "maybe", ""), engine.ErrInvalidDecision)
+
+	execCtx.Status = types.StatusFailed
+	require.ErrorIs(t, engine.Approve(flow, execCtx, engine.DecisionYes, ""), engine.ErrNotAwaitingApproval)
+}
+
+func TestEngine_Resume_WithoutDecisionPausesAgain(t *testing.T) {
+	t.Parallel()
+
+	flow := newApprovalFlow()
+	flowEngine := newTestEngine(t, approvalExecutors(engine.ApprovalSettings{}))
+
+	execCtx, err := flowEngine.Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, flowEngine.Resume(context.Background(), flow, execCtx))
+
+	assert.Equal(t, types.StatusPaused, execCtx.Status)
+	assert.Equal(t, "review", execCtx.CurrentStep)
+}
+
+func TestApprovalExecutor_StrictTemplates(t *testing.T) {
+	t.Parallel()
+
+	flow := newApprovalFlow()
+
+	execCtx, err := newTestEngine(t, approvalExecutors(engine.ApprovalSettings{})).Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+
+	question, _ := engine.PendingQuestion(flow, execCtx)
+	assert.Equal(t, "Publish <no value>?", question)
+
+	strict := approvalExecutors(engine.ApprovalSettings{StrictTemplates: true})
+
+	execCtx, err = newTestEngine(t, strict).Run(context.Background(), flow, nil)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeTemplateError, execErr.Code)
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+}
@@ -0,0 +1,282 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Execution context metadata keys counting what a run has spent so far.
+// Unlike the step results, which keep the last run of every step, they
+// count every step that ran, including the steps of loops and of parallel
+// branches that did not join. They are float64 after a checkpoint was loaded.
+const (
+	TokensUsedKey = "tokensUsed"
+	CostKey       = "cost"
+)
+
+// Budget is what a step may spend: the limits of the step itself and the
+// limits of its run, which has already spent part of them. Executors that
+// spend tokens over several requests, such as prompt steps calling tools,
+// check it between requests to stop as soon as a limit is exceeded.
+type Budget struct {
+	step  types.BudgetConfig
+	run   types.BudgetConfig
+	spent *spending
+}
+
+// spending counts what a step, including the steps of its parallel
+// branches, has spent. The spending of every step is a child of the
+// spending of its run, or of the parallel step it runs under, and adding to
+// it adds to its ancestors too: the root counts everything the run spent,
+// even while parallel branches are still running. It is safe for concurrent use.
+type spending struct {
+	mu     sync.Mutex
+	parent *spending
+	tokens int
+	cost   float64
+}
+
+// SetBudget sets the default run budget. Flows that set no budget, or only
+// some of its limits, take the missing limits from it.
+func (e *Engine) SetBudget(budget types.BudgetConfig) {
+	e.budget = budget
+}
+
+// RunBudget returns the budget of runs of flow: the flow's own budget with
+// the limits it leaves unset taken from the default run budget.
+func (e *Engine) RunBudget(flow *types.FlowDefinition) types.BudgetConfig {
+	budget := e.budget
+	if flow.Budget == nil {
+		return budget
+	}
+
+	if flow.Budget.MaxTokens > 0 {
+		budget.MaxTokens = flow.Budget.MaxTokens
+	}
+
+	if flow.Budget.MaxCost > 0 {
+		budget.MaxCost = flow.Budget.MaxCost
+	}
+
+	if flow.Budget.WarnAt > 0 {
+		budget.WarnAt = flow.Budget.WarnAt
+	}
+
+	return budget
+}
+
+// RunUsage returns the tokens and cost a run has spent so far.
+func RunUsage(execCtx *types.ExecutionContext) (int, float64) {
+	tokens := 0
+
+	switch value := execCtx.Metadata[TokensUsedKey].(type) {
+	case int:
+		tokens = value
+	case float64:
+		tokens = int(value)
+	}
+
+	cost, _ := execCtx.Metadata[CostKey].(float64)
+
+	return tokens, cost
+}
+
+// newSpending returns the spending of a run that has spent tokens and cost so far.
+func newSpending(tokens int, cost float64) *spending {
+	return &spending{mu: sync.Mutex{}, parent: nil, tokens: tokens, cost: cost}
+}
+
+// runSpending returns the spending of the run of execCtx.
+func runSpending(execCtx *types.ExecutionContext) *spending {
+	return newSpending(RunUsage(execCtx))
+}
+
+// child returns the spending of a step that runs under s.
+func (s *spending) child() *spending {
+	return &spending{mu: sync.Mutex{}, parent: s, tokens: 0, cost: 0}
+}
+
+// total returns what s has counted so far.
+func (s *spending) total() (int, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens, s.cost
+}
+
+// root returns the spending of the run.
+func (s *spending) root() *spending {
+	for s.parent != nil {
+		s = s.parent
+	}
+
+	return s
+}
+
+// add adds tokens and cost to s and its ancestors. It returns what the run
+// had spent before, so concurrent steps never report the same threshold.
+func (s *spending) add(tokens int, cost float64) (int, float64) {
+	for {
+		s.mu.Lock()
+		tokensBefore, costBefore := s.tokens, s.cost
+		s.tokens += tokens
+		s.cost += cost
+		s.mu.Unlock()
+
+		if s.parent == nil {
+			return tokensBefore, costBefore
+		}
+
+		s = s.parent
+	}
+}
+
+// recordRunUsage stores what the run of spent has spent so far in the
+// metadata of execCtx, so checkpoints carry it.
+func recordRunUsage(execCtx *types.ExecutionContext, spent *spending) {
+	tokens, cost := spent.root().total()
+	if tokens == 0 && cost == 0 {
+		return
+	}
+
+	if execCtx.Metadata == nil {
+		execCtx.Metadata = make(map[string]any)
+	}
+
+	execCtx.Metadata[TokensUsedKey] = tokens
+	execCtx.Metadata[CostKey] = cost
+}
+
+// stepBudget returns the budget of a step of flow that runs under parent.
+func (e *Engine) stepBudget(flow *types.FlowDefinition, parent *spending, step types.Step) *Budget {
+	budget := &Budget{
+		step:  types.BudgetConfig{MaxTokens: 0, MaxCost: 0, WarnAt: 0},
+		run:   e.RunBudget(flow),
+		spent: parent.child(),
+	}
+
+	if step.Budget != nil {
+		budget.step = *step.Budget
+	}
+
+	return budget
+}
+
+// unlimitedBudget returns a budget without limits for a step run outside a
+// run of the engine, which counts only what it spends itself.
+func unlimitedBudget() *Budget {
+	return &Budget{
+		step:  types.BudgetConfig{MaxTokens: 0, MaxCost: 0, WarnAt: 0},
+		run:   types.BudgetConfig{MaxTokens: 0, MaxCost: 0, WarnAt: 0},
+		spent: newSpending(0, 0),
+	}
+}
+
+// spending returns the spending steps running under the step of b count
+// under; without a budget they count under a new spending of the run of execCtx.
+func (b *Budget) spending(execCtx *types.ExecutionContext) *spending {
+	if b == nil {
+		return runSpending(execCtx)
+	}
+
+	return b.spent
+}
+
+// Exceeded reports whether a step that has spent tokens and cost so far is
+// over the limits of the step or of its run. A nil budget is unlimited.
+func (b *Budget) Exceeded(tokens int, cost float64) bool {
+	return b.check(tokens, cost) != nil
+}
+
+// check returns the error for the first limit a step that has spent tokens
+// and cost so far, besides what it already counted, is over.
+func (b *Budget) check(tokens int, cost float64) *types.ExecutionError {
+	if b == nil {
+		return nil
+	}
+
+	stepTokens, stepCost := b.spent.total()
+
+	execErr := overBudget("step", b.step, stepTokens+tokens, stepCost+cost)
+	if execErr != nil {
+		return execErr
+	}
+
+	runTokens, runCost := b.spent.root().total()
+
+	return overBudget("run", b.run, runTokens+tokens, runCost+cost)
+}
+
+// settle counts what a step reports it spent in total, less what the steps
+// of its parallel branches already counted, and returns the error for the
+// first limit the step is over. Within its limits, it logs a warning for
+// every limit the step brought to its warning threshold.
+func (b *Budget) settle(ctx context.Context, logger *slog.Logger, tokens int, cost float64) *types.ExecutionError {
+	counted, countedCost := b.spent.total()
+	tokens = max(tokens-counted, 0)
+	cost = max(cost-countedCost, 0)
+
+	runTokens, runCost := b.spent.add(tokens, cost)
+
+	execErr := b.check(0, 0)
+	if execErr != nil {
+		return execErr
+	}
+
+	stepTokens, stepCost := b.spent.total()
+
+	warnBudget(ctx, logger, "step", b.step, 0, stepTokens, 0, stepCost)
+	warnBudget(ctx, logger, "run", b.run, runTokens, runTokens+tokens, runCost, runCost+cost)
+
+	return nil
+}
+
+// overBudget returns a budget error when tokens or cost are over the limits of budget.
+func overBudget(scope string, budget types.BudgetConfig, tokens int, cost float64) *types.ExecutionError {
+	switch {
+	case budget.MaxTokens > 0 && tokens > budget.MaxTokens:
+		return newExecutionError(CodeBudgetExceeded,
+			fmt.Sprintf("%s spent %d tokens, over its budget of %d", scope, tokens, budget.MaxTokens),
+			map[string]any{"scope": scope, "tokensUsed": tokens, "maxTokens": budget.MaxTokens})
+	case budget.MaxCost > 0 && cost > budget.MaxCost:
+		return newExecutionError(CodeBudgetExceeded,
+			fmt.Sprintf("%s spent $%.6f, over its budget of $%.6f", scope, cost, budget.MaxCost),
+			map[string]any{"scope": scope, "cost": cost, "maxCost": budget.MaxCost})
+	default:
+		return nil
+	}
+}
+
+// warnBudget logs a warning for every limit of budget that spending from
+// before to after reached the warning threshold of.
+func warnBudget(
+	ctx context.Context,
+	logger *slog.Logger,
+	scope string,
+	budget types.BudgetConfig,
+	tokensBefore, tokensAfter int,
+	costBefore, costAfter float64,
+) {
+	warnAt := budget.WarnAt
+	if warnAt == 0 {
+		warnAt = types.DefaultBudgetWarnAt
+	}
+
+	if budget.MaxTokens > 0 && crossed(float64(tokensBefore), float64(tokensAfter), warnAt*float64(budget.MaxTokens)) {
+		logger.WarnContext(ctx, "budget nearly spent", "scope", scope,
+			"tokensUsed", tokensAfter, "maxTokens", budget.MaxTokens)
+	}
+
+	if budget.MaxCost > 0 && crossed(costBefore, costAfter, warnAt*budget.MaxCost) {
+		logger.WarnContext(ctx, "budget nearly spent", "scope", scope, "cost", costAfter, "maxCost", budget.MaxCost)
+	}
+}
+
+// crossed reports whether spending from before to after reached threshold.
+func crossed(before, after, threshold float64) bool {
+	return before < threshold && after >= threshold
+}
@@ -0,0 +1,276 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const stepTypeSpend types.StepType = "spend"
+
+// spender is a step executor that spends a fixed amount of tokens and cost
+// every time it runs.
+type spender struct {
+	tokens int
+	cost   float64
+	runs   atomic.Int32
+}
+
+func (s *spender) Execute(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+	s.runs.Add(1)
+
+	return &engine.StepOutcome{Output: "spent", Next: req.Step.Next, TokensUsed: s.tokens, Cost: s.cost}, nil
+}
+
+// newLoopFlow returns a flow whose spend step runs again and again until
+// its visit limit or a budget stops it.
+func newLoopFlow(budget *types.BudgetConfig) *types.FlowDefinition {
+	flow := newTestFlow("looping", "spend", map[string]types.Step{
+		"spend": {Type: stepTypeSpend, Next: "spend", MaxVisits: 10},
+	})
+	flow.Budget = budget
+
+	return flow
+}
+
+func TestEngine_Run_StepBudgetExceeded(t *testing.T) {
+	t.Parallel()
+
+	flow := newTestFlow("spending", "spend", map[string]types.Step{
+		"spend": {Type: stepTypeSpend, Budget: &types.BudgetConfig{MaxTokens: 500}, Next: "done"},
+		"done":  {Type: types.StepTypeEnd},
+	})
+
+	executor := &spender{tokens: 600}
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeSpend: executor}).Run(context.Background(), flow, nil)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeBudgetExceeded, execErr.Code)
+	assert.False(t, execErr.Recoverable)
+	assert.Equal(t, "step spent 600 tokens, over its budget of 500", execErr.Message)
+	assert.Equal(t, map[string]any{"scope": "step", "tokensUsed": 600, "maxTokens": 500, "stepId": "spend"},
+		execErr.Details)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+
+	result := execCtx.StepResults["spend"]
+	assert.Equal(t, types.StepStatusFailed, result.Status)
+	assert.Equal(t, "spent", result.Output, "the step keeps what it produced")
+	assert.Equal(t, 600, result.TokensUsed)
+	assert.NotContains(t, execCtx.StepResults, "done")
+
+	tokens, _ := engine.RunUsage(execCtx)
+	assert.Equal(t, 600, tokens)
+}
+
+func TestEngine_Run_RunBudgetCountsEveryVisit(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	logger, _, err := logging.New(logging.Options{Level: "warn", Format: "json", Console: &output})
+	require.NoError(t, err)
+
+	executor := &spender{tokens: 300, cost: 0.03}
+	flowEngine := newTestEngine(t, stepExecutors{stepTypeSpend: executor})
+	flowEngine.SetLogger(logger)
+
+	execCtx, err := flowEngine.Run(context.Background(), newLoopFlow(&types.BudgetConfig{MaxTokens: 1000}), nil)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeBudgetExceeded, execErr.Code)
+	assert.Equal(t, "run spent 1200 tokens, over its budget of 1000", execErr.Message)
+	assert.Equal(t, int32(4), executor.runs.Load())
+
+	tokens, cost := engine.RunUsage(execCtx)
+	assert.Equal(t, 1200, tokens)
+	assert.InDelta(t, 0.12, cost, 1e-9)
+	assert.Equal(t, 300, execCtx.StepResults["spend"].TokensUsed, "step results keep the last visit only")
+
+	var warnings []string
+
+	for line := range strings.SplitSeq(strings.TrimSpace(output.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+
+		if record["msg"] == "budget nearly spent" {
+			warnings = append(warnings, record["scope"].(string))
+			assert.InDelta(t, 900, record["tokensUsed"], 0)
+		}
+	}
+
+	assert.Equal(t, []string{"run"}, warnings, "the warning is logged once, when the threshold is reached")
+
+	// A resumed run that is over budget stops before running another step.
+	err = flowEngine.Resume(context.Background(), newLoopFlow(&types.BudgetConfig{MaxTokens: 1000}), execCtx)
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeBudgetExceeded, execErr.Code)
+	assert.Equal(t, int32(4), executor.runs.Load())
+}
+
+func TestEngine_RunBudget(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := newEngine()
+	flowEngine.SetBudget(types.BudgetConfig{MaxTokens: 5000, MaxCost: 2, WarnAt: 0.9})
+
+	assert.Equal(t, types.BudgetConfig{MaxTokens: 5000, MaxCost: 2, WarnAt: 0.9}, flowEngine.RunBudget(newLoopFlow(nil)))
+	assert.Equal(t, types.BudgetConfig{MaxTokens: 5000, MaxCost: 0.5, WarnAt: 0.9},
+		flowEngine.RunBudget(newLoopFlow(&types.BudgetConfig{MaxCost: 0.5})))
+
+	executor := &spender{cost: 0.75}
+	flowEngine = newTestEngine(t, stepExecutors{stepTypeSpend: executor})
+	flowEngine.SetBudget(types.BudgetConfig{MaxCost: 2})
+
+	_, err := flowEngine.Run(context.Background(), newLoopFlow(nil), nil)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, "run spent $2.250000, over its budget of $2.000000", execErr.Message)
+	assert.Equal(t, int32(3), executor.runs.Load())
+}
+
+func TestPromptExecutor_StopsToolCallsOverBudget(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{responses: []*llm.Response{
+		toolCallResponse(functionCall("call-1", "add", `{"a": 2, "b": 3}`)),
+	}}
+
+	flow := newPromptFlow("")
+	step := flow.Steps["ask"]
+	step.Tools = []string{"add"}
+	step.Budget = &types.BudgetConfig{MaxTokens: 30}
+	flow.Steps["ask"] = step
+
+	executor := engine.NewPromptExecutor(provider, engine.PromptSettings{})
+	executor.SetTools(newAgentTools(t), nil)
+
+	execCtx, err := newTestEngine(t, stepExecutors{types.StepTypePrompt: executor}).Run(context.Background(), flow, nil)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeBudgetExceeded, execErr.Code)
+
+	require.Len(t, provider.requests, 3, "the model is not asked again once the step is over budget")
+
+	result := execCtx.StepResults["ask"]
+	assert.Equal(t, types.StepStatusFailed, result.Status)
+	assert.Equal(t, 36, result.TokensUsed)
+	assert.Equal(t, 3, result.Metadata["iterations"])
+}
+
+func TestEngine_Run_CountsFailedAttempts(t *testing.T) {
+	t.Parallel()
+
+	recoverable := &types.ExecutionError{Code: "RATE_LIMITED", Recoverable: true}
+	retry := &types.RetryConfig{MaxAttempts: 3, Delay: time.Millisecond}
+
+	flow := newShellFlow(types.Step{Retry: retry})
+	flaky := &flakyExecutor{failures: 2, err: recoverable, tokens: 12}
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: flaky}).Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 36, execCtx.StepResults["run"].TokensUsed, "the step counts its failed attempts")
+
+	tokens, _ := engine.RunUsage(execCtx)
+	assert.Equal(t, 36, tokens)
+
+	flaky = &flakyExecutor{failures: 5, err: recoverable, tokens: 12}
+
+	execCtx, err = newTestEngine(t, stepExecutors{stepTypeShell: flaky}).Run(context.Background(), flow, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, types.StepStatusFailed, execCtx.StepResults["run"].Status)
+	assert.Equal(t, 36, execCtx.StepResults["run"].TokensUsed, "a failed step counts every attempt")
+
+	tokens, _ = engine.RunUsage(execCtx)
+	assert.Equal(t, 36, tokens)
+}
+
+func TestPromptExecutor_ToolLoopLimitCountsSpending(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{responses: []*llm.Response{
+		toolCallResponse(functionCall("call", "add", `{"a": 1, "b": 1}`)),
+	}}
+
+	execCtx, err := runAgentFlow(t, provider, engine.PromptSettings{MaxToolIterations: 3}, newAgentTools(t), nil, "")
+	require.Error(t, err)
+
+	tokens, cost := engine.RunUsage(execCtx)
+	assert.Equal(t, 36, tokens)
+	assert.InDelta(t, 0.003, cost, 1e-9)
+}
+
+func TestParallelExecutor_CountsBranchSpending(t *testing.T) {
+	t.Parallel()
+
+	spend := func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+		return &engine.StepOutcome{Next: req.Step.Next, TokensUsed: 200}, nil
+	}
+
+	execCtx, err := runParallelFlow(t, newFanOutFlow(types.ParallelConfig{}), true, spend)
+	require.NoError(t, err)
+
+	assert.Equal(t, 600, execCtx.StepResults["fanout"].TokensUsed, "the parallel step reports its branches")
+
+	tokens, _ := engine.RunUsage(execCtx)
+	assert.Equal(t, 600, tokens, "branch spending is counted once")
+
+	flow := newFanOutFlow(types.ParallelConfig{})
+	flow.Budget = &types.BudgetConfig{MaxTokens: 500}
+
+	execCtx, err = runParallelFlow(t, flow, true, spend)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeBudgetExceeded, execErr.Code)
+	assert.Equal(t, "fanout", execCtx.CurrentStep)
+	assert.Equal(t, 600, execCtx.StepResults["fanout"].TokensUsed)
+
+	tokens, _ = engine.RunUsage(execCtx)
+	assert.Equal(t, 600, tokens)
+}
+
+func TestParallelExecutor_BranchesShareRunBudget(t *testing.T) {
+	t.Parallel()
+
+	var runs atomic.Int32
+
+	flow := newFanOutFlow(types.ParallelConfig{})
+	flow.Budget = &types.BudgetConfig{MaxTokens: 300}
+
+	execCtx, err := runParallelFlow(t, flow, false,
+		func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+			runs.Add(1)
+
+			return &engine.StepOutcome{Next: req.Step.Next, TokensUsed: 200}, nil
+		})
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeBudgetExceeded, execErr.Code)
+	assert.Equal(t, "run spent 400 tokens, over its budget of 300", execErr.Message)
+	assert.Equal(t, int32(2), runs.Load(), "the second branch sees what the first spent")
+
+	tokens, _ := engine.RunUsage(execCtx)
+	assert.Equal(t, 400, tokens)
+}
@@ -0,0 +1,99 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var errDiskFull = errors.New("no space left on device")
+
+// recordingCheckpointer records the step and status of every saved checkpoint.
+type recordingCheckpointer struct {
+	saved []string
+	err   error
+}
+
+func (r *recordingCheckpointer) Save(_ *types.FlowDefinition, execCtx *types.ExecutionContext) error {
+	r.saved = append(r.saved, execCtx.CurrentStep+":"+string(execCtx.Status))
+
+	return r.err
+}
+
+func newResumableFlow() *types.FlowDefinition {
+	return newTestFlow("resumable", "first", map[string]types.Step{
+		"first":  {Type: stepTypeShell, Next: "second"},
+		"second": {Type: stepTypeShell, Next: "done"},
+		"done":   {Type: types.StepTypeEnd},
+	})
+}
+
+func TestEngine_Run_CheckpointsEveryStep(t *testing.T) {
+	t.Parallel()
+
+	checkpointer := &recordingCheckpointer{}
+	flowEngine := newTestEngine(t, stepExecutors{stepTypeShell: &flakyExecutor{}})
+	flowEngine.SetCheckpointer(checkpointer)
+
+	_, err := flowEngine.Run(context.Background(), newResumableFlow(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"second:running", "done:running", "done:completed"}, checkpointer.saved)
+}
+
+func TestEngine_Resume(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]int{}
+	failSecondOnce := engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+		calls[req.StepID]++
+		if req.StepID == "second" && calls["second"] == 1 {
+			return nil, errShellFailed
+		}
+
+		return &engine.StepOutcome{Output: calls[req.StepID], Next: req.Step.Next}, nil
+	})
+
+	checkpointer := &recordingCheckpointer{}
+	flowEngine := newTestEngine(t, stepExecutors{stepTypeShell: failSecondOnce})
+	flowEngine.SetCheckpointer(checkpointer)
+
+	flow := newResumableFlow()
+
+	execCtx, err := flowEngine.Run(context.Background(), flow, nil)
+	require.Error(t, err)
+	assert.Equal(t, "second", execCtx.CurrentStep)
+	assert.Equal(t, "second:failed", checkpointer.saved[len(checkpointer.saved)-1])
+
+	require.NoError(t, flowEngine.Resume(context.Background(), flow, execCtx))
+
+	assert.Equal(t, types.StatusCompleted, execCtx.Status)
+	assert.Nil(t, execCtx.Error)
+	assert.Equal(t, map[string]int{"first": 1, "second": 2}, calls, "finished steps are not repeated")
+	assert.Equal(t, types.StepStatusCompleted, execCtx.StepResults["second"].Status)
+
+	err = flowEngine.Resume(context.Background(), flow, execCtx)
+	require.ErrorIs(t, err, engine.ErrRunCompleted)
+}
+
+func TestEngine_Run_CheckpointFailure(t *testing.T) {
+	t.Parallel()
+
+	flowEngine := newTestEngine(t, stepExecutors{stepTypeShell: &flakyExecutor{}})
+	flowEngine.SetCheckpointer(&recordingCheckpointer{err: errDiskFull})
+
+	execCtx, err := flowEngine.Run(context.Background(), newResumableFlow(), nil)
+	require.Error(t, err)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+	assert.Equal(t, engine.CodeCheckpointFailed, execCtx.Error.Code)
+	assert.Contains(t, execCtx.Error.Message, errDiskFull.Error())
+	assert.NotContains(t, execCtx.StepResults, "second", "the run stops when its state cannot be saved")
+}
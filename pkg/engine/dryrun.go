@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Execution context metadata keys set by DryRun.
+const (
+	// DryRunKey marks the execution context of a dry run.
+	DryRunKey = "dryRun"
+	// LoopsToKey names the step a dry run stopped at because it had already
+	// been planned; stubbed outputs would take the same path around again.
+	LoopsToKey = "loopsTo"
+)
+
+// DryRunSettings configures a dry run.
+type DryRunSettings struct {
+	// Prompt, Tool, GitHub and Approval are the settings the executors of a
+	// real run use, so a dry run resolves models, repositories and templates
+	// the same way.
+	Prompt   PromptSettings
+	Tool     ToolSettings
+	GitHub   GitHubSettings
+	Approval ApprovalSettings
+	// Outputs are the outputs steps are assumed to produce, by step ID.
+	// Steps without one get a stub: a placeholder text for prompt steps, an
+	// approval for approval steps and an empty object otherwise.
+	Outputs map[string]any
+}
+
+// dryRunExecutor plans the steps that would call a language model, a tool,
+// GitHub or a person, or that have a custom step type, without doing so.
+type dryRunExecutor struct {
+	settings DryRunSettings
+}
+
+// DryRun walks flow from its initial step like Run, but without side
+// effects: prompts, tool arguments, GitHub arguments and approval questions
+// are rendered but nothing is called, and nothing is checkpointed.
+// Condition, end and parallel steps run as usual against the assumed step
+// outputs. Every step walked has a result with StepStatusSkipped whose
+// metadata says what the step would do; a step that cannot be planned,
+// such as one whose prompt does not render, fails the dry run.
+//
+// The walk stops when it reaches a step it already planned and records
+// that step under LoopsToKey in the execution context metadata.
+func (e *Engine) DryRun(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	variables map[string]any,
+	settings DryRunSettings,
+) (*types.ExecutionContext, error) {
+	if flow == nil {
+		return nil, ErrNilFlow
+	}
+
+	planner := &Engine{
+		registry:       newDryRunRegistry(e.registry, settings),
+		maxSteps:       e.maxSteps,
+		defaultTimeout: 0,
+		defaultRetry:   nil,
+		checkpointer:   nil,
+		logger:         e.logger,
+		tracer:         noop.NewTracerProvider().Tracer(""),
+		budget:         e.budget,
+		dryRun:         true,
+	}
+
+	execCtx := NewExecutionContext(flow, variables)
+	execCtx.Metadata = map[string]any{DryRunKey: true}
+
+	if flow.InitialStep == "" {
+		return execCtx, fail(execCtx, newExecutionError(CodeMissingInitialStep, "flow has no initial step", nil))
+	}
+
+	execCtx.Status = types.StatusRunning
+
+	loopsBack := newExecutionError("", "", nil)
+
+	_, execErr := planner.advance(ctx, flow, execCtx, runSpending(execCtx), flow.InitialStep, "",
+		func(next string) *types.ExecutionError {
+			if _, planned := execCtx.StepResults[next]; planned {
+				execCtx.Metadata[LoopsToKey] = next
+
+				return loopsBack
+			}
+
+			return nil
+		})
+
+	if execErr != nil && execErr != loopsBack {
+		return execCtx, fail(execCtx, execErr)
+	}
+
+	execCtx.Status = types.StatusCompleted
+	execCtx.LastUpdate = time.Now()
+
+	return execCtx, nil
+}
+
+// newDryRunRegistry returns the executors of a dry run for the step types
+// base supports. Condition, end and parallel steps have no side effects and
+// keep their executors.
+func newDryRunRegistry(base *Registry, settings DryRunSettings) *Registry {
+	registry := NewRegistry()
+	planner := dryRunExecutor{settings: settings}
+
+	for _, stepType := range base.StepTypes() {
+		executor, _ := base.Lookup(stepType)
+
+		switch stepType {
+		case types.StepTypeCondition, types.StepTypeEnd, types.StepTypeParallel:
+		default:
+			executor = planner
+		}
+
+		registry.mustRegister(stepType, executor)
+	}
+
+	return registry
+}
+
+// Execute renders what the step would send and reports the assumed output.
+func (d dryRunExecutor) Execute(_ context.Context, req *StepRequest) (*StepOutcome, error) {
+	var (
+		stub any = map[string]any{}
+		plan map[string]any
+		err  error
+	)
+
+	switch req.Step.Type {
+	case types.StepTypePrompt:
+		stub = fmt.Sprintf("[dry run output of step %s]", req.StepID)
+		plan, err = d.planPrompt(req)
+	case types.StepTypeTool:
+		plan, err = d.planTool(req)
+	case types.StepTypeGitHub:
+		plan, err = d.planGitHub(req)
+	case types.StepTypeApproval:
+		plan, err = d.planApproval(req)
+		stub = map[string]any{"question": plan["question"], "decision": DecisionYes, "approved": true, "comment": ""}
+	default:
+		plan = map[string]any{"note": fmt.Sprintf("step type %s is not simulated", req.Step.Type)}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	output, assumed := d.settings.Outputs[req.StepID]
+	if !assumed {
+		output = stub
+	}
+
+	return &StepOutcome{
+		Output:     output,
+		Next:       req.Step.Next,
+		TokensUsed: 0,
+		Cost:       0,
+		Metadata:   plan,
+		Paused:     false,
+	}, nil
+}
+
+// planPrompt renders the prompt and resolves the model a prompt step would call.
+func (d dryRunExecutor) planPrompt(req *StepRequest) (map[string]any, error) {
+	prompt, err := RenderPrompt(req.Flow, req.Execution, req.StepID, req.Step, d.settings.Prompt.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := map[string]any{
+		"model":  d.settings.Prompt.ResolveModel(req.Step.Model),
+		"prompt": prompt.User,
+	}
+
+	if prompt.System != "" {
+		plan["system"] = prompt.System
+	}
+
+	if len(req.Step.Tools) > 0 {
+		plan["tools"] = req.Step.Tools
+	}
+
+	if req.Step.MCPServer != "" {
+		plan["mcpServer"] = req.Step.MCPServer
+	}
+
+	return plan, nil
+}
+
+// planTool renders the arguments of the tool a tool step would call.
+func (d dryRunExecutor) planTool(req *StepRequest) (map[string]any, error) {
+	config := req.Step.Tool
+	if config == nil || config.Name == "" {
+		return nil, newExecutionError(CodeInvalidTool, "tool step has no tool name", nil)
+	}
+
+	arguments, err := RenderArguments(req.Flow, req.Execution, req.StepID, config.Arguments,
+		d.settings.Tool.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	server := tools.EmbeddedServer
+	if req.Step.MCPServer != "" {
+		server = req.Step.MCPServer
+	}
+
+	return map[string]any{"tool": config.Name, "server": server, "arguments": arguments}, nil
+}
+
+// planGitHub renders the arguments of the GitHub operation a github step
+// would perform, with the configured repository filled in.
+func (d dryRunExecutor) planGitHub(req *StepRequest) (map[string]any, error) {
+	config := req.Step.GitHub
+	if config == nil {
+		return nil, newExecutionError(CodeInvalidGitHubStep, "github step has no operation", nil)
+	}
+
+	values, err := RenderArguments(req.Flow, req.Execution, req.StepID, config.Arguments,
+		d.settings.GitHub.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	args := gitHubArguments{operation: config.Operation, values: values}
+	if config.Operation != types.GitHubResolveReviewThread {
+		values["owner"] = args.text("owner", d.settings.GitHub.Owner)
+		values["repository"] = args.text("repository", d.settings.GitHub.Repository)
+	}
+
+	return map[string]any{"operation": config.Operation, "arguments": values}, nil
+}
+
+// planApproval renders the question an approval step would ask.
+func (d dryRunExecutor) planApproval(req *StepRequest) (map[string]any, error) {
+	question, err := renderQuestion(req, d.settings.Approval.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"question": question}, nil
+}
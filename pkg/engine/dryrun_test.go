@@ -0,0 +1,188 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+func newDryRunFlow() *types.FlowDefinition {
+	flow := newTestFlow("triage", "fetch", map[string]types.Step{
+		"fetch": {
+			Type: types.StepTypeGitHub,
+			GitHub: &types.GitHubConfig{
+				Operation: types.GitHubGetPullRequest,
+				Arguments: map[string]any{"number": "{{.vars.pr}}"},
+			},
+			Next: "review",
+		},
+		"review": {
+			Type:   types.StepTypePrompt,
+			Model:  "openai/gpt-4o",
+			Tools:  []string{"read_file"},
+			Prompt: &types.PromptConfig{Template: "Review PR {{.vars.pr}} titled {{.steps.fetch.output.title}}"},
+			Next:   "check",
+		},
+		"check": {
+			Type:       types.StepTypeCondition,
+			Conditions: []types.ConditionConfig{{Expression: `steps.review.output == "LGTM"`, Next: "approve"}},
+			Next:       "label",
+		},
+		"approve": {
+			Type:     types.StepTypeApproval,
+			Approval: &types.ApprovalConfig{Question: "Merge PR {{.vars.pr}}?"},
+			Next:     "done",
+		},
+		"label": {
+			Type: types.StepTypeTool,
+			Tool: &types.ToolConfig{Name: "add_label", Arguments: map[string]any{"pr": "{{.vars.pr}}"}},
+			Next: "done",
+		},
+		"done": {Type: types.StepTypeEnd},
+	})
+	flow.Variables = map[string]string{"pr": "12"}
+
+	return flow
+}
+
+// dryRunExecutors runs the step types of newDryRunFlow against fakes that record every real call.
+func dryRunExecutors(provider *fakeProvider, api *fakeGitHub, caller *fakeCaller) stepExecutors {
+	return stepExecutors{
+		types.StepTypePrompt:   engine.NewPromptExecutor(provider, engine.PromptSettings{}),
+		types.StepTypeGitHub:   engine.NewGitHubExecutor(api, engine.GitHubSettings{}),
+		types.StepTypeTool:     engine.NewToolExecutor(caller, nil, engine.ToolSettings{}),
+		types.StepTypeApproval: engine.NewApprovalExecutor(engine.ApprovalSettings{}),
+	}
+}
+
+func TestEngine_DryRun_PlansWithoutSideEffects(t *testing.T) {
+	t.Parallel()
+
+	provider, api, caller := &fakeProvider{}, &fakeGitHub{}, &fakeCaller{}
+
+	flowEngine := newTestEngine(t, dryRunExecutors(provider, api, caller))
+
+	execCtx, err := flowEngine.DryRun(context.Background(), newDryRunFlow(), nil,
+		engine.DryRunSettings{
+			Prompt:  engine.PromptSettings{DefaultModel: "openai/gpt-4o-mini"},
+			GitHub:  engine.GitHubSettings{Owner: "ondatra-ai", Repository: "flow-test-go"},
+			Outputs: map[string]any{"fetch": map[string]any{"title": "Fix crash"}},
+		})
+	require.NoError(t, err)
+
+	assert.Empty(t, provider.requests)
+	assert.Empty(t, api.calls)
+	assert.Empty(t, caller.calls)
+
+	assert.Equal(t, types.StatusCompleted, execCtx.Status)
+	assert.Equal(t, true, execCtx.Metadata[engine.DryRunKey])
+	assert.ElementsMatch(t, []string{"fetch", "review", "check", "label", "done"}, resultIDs(execCtx))
+
+	for _, result := range execCtx.StepResults {
+		assert.Equal(t, types.StepStatusSkipped, result.Status, result.StepID)
+	}
+
+	fetch := execCtx.StepResults["fetch"].Metadata
+	assert.Equal(t, types.GitHubGetPullRequest, fetch["operation"])
+	assert.Equal(t, map[string]any{"number": "12", "owner": "ondatra-ai", "repository": "flow-test-go"},
+		fetch["arguments"])
+
+	review := execCtx.StepResults["review"]
+	assert.Equal(t, "Review PR 12 titled Fix crash", review.Metadata["prompt"])
+	assert.Equal(t, "openai/gpt-4o", review.Metadata["model"])
+	assert.Equal(t, []string{"read_file"}, review.Metadata["tools"])
+	assert.Equal(t, "[dry run output of step review]", review.Output)
+
+	label := execCtx.StepResults["label"].Metadata
+	assert.Equal(t, "add_label", label["tool"])
+	assert.Equal(t, "embedded", label["server"])
+	assert.Equal(t, map[string]any{"pr": "12"}, label["arguments"])
+}
+
+func TestEngine_DryRun_UsesAssumedOutputs(t *testing.T) {
+	t.Parallel()
+
+	execCtx, err := newTestEngine(t, dryRunExecutors(&fakeProvider{}, &fakeGitHub{}, &fakeCaller{})).DryRun(
+		context.Background(), newDryRunFlow(), nil,
+		engine.DryRunSettings{Outputs: map[string]any{"review": "LGTM"}})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"fetch", "review", "check", "approve", "done"}, resultIDs(execCtx))
+
+	approve := execCtx.StepResults["approve"]
+	assert.Equal(t, "Merge PR 12?", approve.Metadata["question"])
+	assert.Equal(t, true, approve.Output.(map[string]any)["approved"])
+}
+
+func TestEngine_DryRun_StrictApprovalQuestion(t *testing.T) {
+	t.Parallel()
+
+	flow := newDryRunFlow()
+	approve := flow.Steps["approve"]
+	approve.Approval = &types.ApprovalConfig{Question: "Merge PR {{.vars.pull}}?"}
+	flow.Steps["approve"] = approve
+
+	execCtx, err := newTestEngine(t, dryRunExecutors(&fakeProvider{}, &fakeGitHub{}, &fakeCaller{})).DryRun(
+		context.Background(), flow, nil, engine.DryRunSettings{Outputs: map[string]any{"review": "LGTM"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Merge PR <no value>?", execCtx.StepResults["approve"].Metadata["question"])
+
+	execCtx, err = newTestEngine(t, dryRunExecutors(&fakeProvider{}, &fakeGitHub{}, &fakeCaller{})).DryRun(
+		context.Background(), flow, nil, engine.DryRunSettings{
+			Outputs:  map[string]any{"review": "LGTM"},
+			Approval: engine.ApprovalSettings{StrictTemplates: true},
+		})
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeTemplateError, execErr.Code)
+	assert.Equal(t, "approve", execCtx.CurrentStep)
+}
+
+func TestEngine_DryRun_StopsAtLoops(t *testing.T) {
+	t.Parallel()
+
+	flow := newConditionalFlow("true")
+	flow.Steps["yes"] = types.Step{Type: types.StepTypeCondition, Next: "check"}
+
+	execCtx, err := newEngine().DryRun(context.Background(), flow, nil, engine.DryRunSettings{})
+	require.NoError(t, err)
+
+	assert.Equal(t, types.StatusCompleted, execCtx.Status)
+	assert.Equal(t, "check", execCtx.Metadata[engine.LoopsToKey])
+	assert.ElementsMatch(t, []string{"check", "yes"}, resultIDs(execCtx))
+}
+
+func TestEngine_DryRun_FailsOnUnrenderablePrompt(t *testing.T) {
+	t.Parallel()
+
+	flow := newDryRunFlow()
+	flow.Steps["review"] = types.Step{
+		Type:   types.StepTypePrompt,
+		Prompt: &types.PromptConfig{Template: "{{.vars.missing}}"},
+	}
+
+	execCtx, err := newTestEngine(t, dryRunExecutors(&fakeProvider{}, &fakeGitHub{}, &fakeCaller{})).DryRun(
+		context.Background(), flow, nil,
+		engine.DryRunSettings{Prompt: engine.PromptSettings{StrictTemplates: true}})
+	require.Error(t, err)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+	assert.Equal(t, types.StepStatusFailed, execCtx.StepResults["review"].Status)
+	assert.Equal(t, engine.CodeTemplateError, execCtx.Error.Code)
+}
+
+func resultIDs(execCtx *types.ExecutionContext) []string {
+	ids := make([]string, 0, len(execCtx.StepResults))
+	for stepID := range execCtx.StepResults {
+		ids = append(ids, stepID)
+	}
+
+	return ids
+}
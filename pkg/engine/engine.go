@@ -0,0 +1,598 @@
+// Package engine executes flow definitions by walking their step graph.
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var (
+	// ErrNilFlow is returned when Run is called without a flow definition.
+	ErrNilFlow = errors.New("flow definition is required")
+
+	// ErrRunCompleted is returned when resuming a run that already completed.
+	ErrRunCompleted = errors.New("run has already completed")
+)
+
+// Execution error codes recorded on failed runs and steps.
+const (
+	CodeMissingInitialStep  = "MISSING_INITIAL_STEP"
+	CodeStepNotFound        = "STEP_NOT_FOUND"
+	CodeUnsupportedStepType = "UNSUPPORTED_STEP_TYPE"
+	CodeInvalidExpression   = "INVALID_EXPRESSION"
+	CodeNoMatchingCondition = "NO_MATCHING_CONDITION"
+	CodeStepFailed          = "STEP_FAILED"
+	CodeStepLimitExceeded   = "STEP_LIMIT_EXCEEDED"
+	CodeVisitLimitExceeded  = "VISIT_LIMIT_EXCEEDED"
+	CodeCanceled            = "CANCELED"
+	CodeCheckpointFailed    = "CHECKPOINT_FAILED"
+	CodeBranchPaused        = "BRANCH_PAUSED"
+	CodeBudgetExceeded      = "BUDGET_EXCEEDED"
+)
+
+// Step result metadata keys counting the visits of steps with MaxVisits and
+// the attempts of every step.
+const (
+	visitsKey   = "visits"
+	attemptsKey = "attempts"
+)
+
+// DefaultMaxSteps bounds how many steps a single run may execute.
+const DefaultMaxSteps = 1000
+
+const sessionIDBytes = 8
+
+// Checkpointer persists the state of a run after every step and when the run ends.
+type Checkpointer interface {
+	Save(flow *types.FlowDefinition, execCtx *types.ExecutionContext) error
+}
+
+// Engine walks a flow from its initial step and records a result for every step.
+// Each step is handed to the executor registered for its type, bounded by
+// its timeout and retried on recoverable errors.
+type Engine struct {
+	registry       *Registry
+	maxSteps       int
+	defaultTimeout time.Duration
+	defaultRetry   *types.RetryConfig
+	checkpointer   Checkpointer
+	logger         *slog.Logger
+	tracer         trace.Tracer
+	budget         types.BudgetConfig
+	// dryRun records the steps it walks as skipped; see DryRun.
+	dryRun bool
+}
+
+// NewEngine creates a new flow execution engine that dispatches steps through registry.
+func NewEngine(registry *Registry) *Engine {
+	return &Engine{
+		registry:       registry,
+		maxSteps:       DefaultMaxSteps,
+		defaultTimeout: 0,
+		defaultRetry:   nil,
+		checkpointer:   nil,
+		logger:         logging.Discard(),
+		tracer:         noop.NewTracerProvider().Tracer(""),
+		budget:         types.BudgetConfig{MaxTokens: 0, MaxCost: 0, WarnAt: 0},
+		dryRun:         false,
+	}
+}
+
+// SetMaxSteps overrides the maximum number of steps a single run may execute.
+func (e *Engine) SetMaxSteps(maxSteps int) {
+	e.maxSteps = maxSteps
+}
+
+// Run executes the flow starting at its initial step.
+// The returned execution context is always populated; the error is non-nil
+// when the run failed or was canceled. A run that waits for a paused step
+// returns no error and has StatusPaused.
+func (e *Engine) Run(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	variables map[string]any,
+) (*types.ExecutionContext, error) {
+	if flow == nil {
+		return nil, ErrNilFlow
+	}
+
+	execCtx := NewExecutionContext(flow, variables)
+
+	return execCtx, e.walk(ctx, flow, execCtx, flow.InitialStep)
+}
+
+// Resume continues a checkpointed run from its CurrentStep. The step that
+// failed, was interrupted or paused the run runs again; completed steps keep
+// their results.
+func (e *Engine) Resume(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+) error {
+	if flow == nil {
+		return ErrNilFlow
+	}
+
+	if execCtx.Status == types.StatusCompleted {
+		return fmt.Errorf("%w: %s", ErrRunCompleted, execCtx.SessionID)
+	}
+
+	execCtx.Error = nil
+
+	return e.walk(ctx, flow, execCtx, execCtx.CurrentStep)
+}
+
+// SetCheckpointer sets where the run state is saved after every step.
+func (e *Engine) SetCheckpointer(checkpointer Checkpointer) {
+	e.checkpointer = checkpointer
+}
+
+// SetLogger sets where the engine logs runs and steps. Executors log
+// through logging.FromContext with the logger of the step they run.
+func (e *Engine) SetLogger(logger *slog.Logger) {
+	e.logger = logger
+}
+
+// walk executes steps from stepID until the flow ends, fails or pauses.
+func (e *Engine) walk(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	stepID string,
+) error {
+	ctx = e.startRunSpan(ctx, flow, execCtx)
+
+	if stepID == "" {
+		return e.finish(ctx, flow, execCtx,
+			fail(execCtx, newExecutionError(CodeMissingInitialStep, "flow has no initial step", nil)))
+	}
+
+	execCtx.Status = types.StatusRunning
+
+	logging.ForStep(e.logger, flow.ID, execCtx.SessionID, stepID).DebugContext(ctx, "run started")
+
+	paused, execErr := e.advance(ctx, flow, execCtx, runSpending(execCtx), stepID, "",
+		func(next string) *types.ExecutionError {
+			// The checkpoint points at the step to run next, so resuming never
+			// repeats a step that already finished.
+			execCtx.CurrentStep = next
+
+			return e.checkpoint(flow, execCtx)
+		})
+
+	switch {
+	case execErr != nil && execErr.Code == CodeCanceled:
+		execCtx.Status = types.StatusCanceled
+		execCtx.Error = execErr
+		execCtx.LastUpdate = time.Now()
+
+		return e.finish(ctx, flow, execCtx, execErr)
+	case execErr != nil:
+		return e.finish(ctx, flow, execCtx, fail(execCtx, execErr))
+	case paused:
+		execCtx.Status = types.StatusPaused
+	default:
+		execCtx.Status = types.StatusCompleted
+	}
+
+	execCtx.LastUpdate = time.Now()
+
+	return e.finish(ctx, flow, execCtx, nil)
+}
+
+// logRun logs how a run ended: failed and canceled runs as errors, others at debug level.
+func (e *Engine) logRun(ctx context.Context, flow *types.FlowDefinition, execCtx *types.ExecutionContext) {
+	logger := logging.ForStep(e.logger, flow.ID, execCtx.SessionID, execCtx.CurrentStep)
+
+	if execCtx.Error != nil {
+		logger.ErrorContext(ctx, "run ended", "status", execCtx.Status,
+			"code", execCtx.Error.Code, "error", execCtx.Error.Message)
+
+		return
+	}
+
+	logger.DebugContext(ctx, "run ended", "status", execCtx.Status, "steps", len(execCtx.StepResults))
+}
+
+// RunBranch executes steps of flow in execCtx from stepID until the next
+// step is until or the flow ends. Parallel steps use it to run each branch
+// in its own execution context; branches are not checkpointed and cannot pause.
+// The branch steps count what they spend under budget, the budget of the
+// step starting the branch, so concurrent branches share the run budget.
+func (e *Engine) RunBranch(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	stepID string,
+	until string,
+	budget *Budget,
+) error {
+	paused, execErr := e.advance(ctx, flow, execCtx, budget.spending(execCtx), stepID, until,
+		func(string) *types.ExecutionError {
+			return nil
+		})
+	if execErr != nil {
+		return execErr
+	}
+
+	if paused {
+		return newExecutionError(CodeBranchPaused, "steps cannot pause inside a parallel branch",
+			map[string]any{"stepId": execCtx.CurrentStep})
+	}
+
+	return nil
+}
+
+// advance executes steps from stepID until a step has no next step, the
+// next step is until or a step pauses the run. The steps count what they
+// spend under spent. afterStep is called with the next step after every
+// step that does not end the walk.
+func (e *Engine) advance(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	spent *spending,
+	stepID string,
+	until string,
+	afterStep func(next string) *types.ExecutionError,
+) (bool, *types.ExecutionError) {
+	for executed := 0; ; executed++ {
+		if executed >= e.maxSteps {
+			return false, newExecutionError(CodeStepLimitExceeded,
+				fmt.Sprintf("flow exceeded the limit of %d executed steps", e.maxSteps),
+				map[string]any{"stepId": stepID})
+		}
+
+		err := ctx.Err()
+		if err != nil {
+			return false, newExecutionError(CodeCanceled, err.Error(), map[string]any{"stepId": stepID})
+		}
+
+		step, exists := flow.Steps[stepID]
+		if !exists {
+			return false, newExecutionError(CodeStepNotFound, "step not found", map[string]any{"stepId": stepID})
+		}
+
+		execCtx.CurrentStep = stepID
+
+		if step.MaxVisits > 0 && visits(execCtx, stepID) >= step.MaxVisits {
+			return false, newExecutionError(CodeVisitLimitExceeded,
+				fmt.Sprintf("step %s reached its limit of %d visits", stepID, step.MaxVisits),
+				map[string]any{"stepId": stepID, "maxVisits": step.MaxVisits})
+		}
+
+		next, execErr := e.runStep(ctx, flow, execCtx, spent, stepID, step)
+		if execErr != nil {
+			return false, execErr
+		}
+
+		if execCtx.StepResults[stepID].Status == types.StepStatusPending {
+			return true, nil
+		}
+
+		if next == "" || next == until {
+			return false, nil
+		}
+
+		execErr = afterStep(next)
+		if execErr != nil {
+			return false, execErr
+		}
+
+		stepID = next
+	}
+}
+
+// finish saves, logs and traces the final state of a run and returns runErr, or
+// the checkpoint error when the run itself succeeded.
+func (e *Engine) finish(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	runErr error,
+) error {
+	saveErr := e.checkpoint(flow, execCtx)
+	if saveErr != nil && runErr == nil {
+		runErr = fail(execCtx, saveErr)
+	}
+
+	e.logRun(ctx, flow, execCtx)
+	endRunSpan(ctx, execCtx)
+
+	return runErr
+}
+
+// checkpoint saves the run state when a checkpointer is set.
+func (e *Engine) checkpoint(flow *types.FlowDefinition, execCtx *types.ExecutionContext) *types.ExecutionError {
+	if e.checkpointer == nil {
+		return nil
+	}
+
+	err := e.checkpointer.Save(flow, execCtx)
+	if err != nil {
+		return newExecutionError(CodeCheckpointFailed, fmt.Sprintf("failed to save checkpoint: %v", err),
+			map[string]any{"sessionId": execCtx.SessionID})
+	}
+
+	return nil
+}
+
+// runStep executes a single step, stores its result and returns the next
+// step ID. The step counts what it spends under spent.
+func (e *Engine) runStep(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	spent *spending,
+	stepID string,
+	step types.Step,
+) (string, *types.ExecutionError) {
+	logger := logging.ForStep(e.logger, flow.ID, execCtx.SessionID, stepID)
+	ctx = logging.NewContext(ctx, logger)
+
+	// A run that is already over budget, such as a resumed one, runs no more steps.
+	budget := e.stepBudget(flow, spent, step)
+
+	budgetErr := budget.check(0, 0)
+	if budgetErr != nil {
+		budgetErr.Details = withStepID(budgetErr.Details, stepID)
+
+		return "", budgetErr
+	}
+
+	logger.DebugContext(ctx, "step started", "type", step.Type)
+
+	start := time.Now()
+
+	ctx, span := e.startStepSpan(ctx, flow, execCtx, stepID, step, start)
+
+	outcome, attempts, err := e.execute(ctx, &StepRequest{
+		Flow:      flow,
+		StepID:    stepID,
+		Step:      step,
+		Execution: execCtx,
+		Branches:  e,
+		Budget:    budget,
+	})
+
+	end := time.Now()
+	result := types.StepResult{
+		StepID:     stepID,
+		Status:     types.StepStatusCompleted,
+		Output:     nil,
+		Error:      nil,
+		StartTime:  start,
+		EndTime:    end,
+		Duration:   end.Sub(start),
+		TokensUsed: 0,
+		Cost:       0,
+		Metadata:   map[string]any{attemptsKey: attempts},
+	}
+
+	// Failed steps count what they spent as well; the step keeps its output
+	// and usage when it takes the run over budget.
+	if outcome != nil {
+		result.Output = outcome.Output
+		result.TokensUsed = outcome.TokensUsed
+		result.Cost = outcome.Cost
+		maps.Copy(result.Metadata, outcome.Metadata)
+	}
+
+	execErr := budget.settle(ctx, logger, result.TokensUsed, result.Cost)
+	result.TokensUsed, result.Cost = budget.spent.total()
+	recordRunUsage(execCtx, spent)
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// The step failed because the run was canceled, which ends the run as canceled.
+		execErr = newExecutionError(CodeCanceled, ctx.Err().Error(), nil)
+	case err != nil:
+		// The failure of the step itself takes precedence over its budget.
+		execErr = toExecutionError(err)
+	}
+
+	if execErr != nil {
+		if step.MaxVisits > 0 {
+			result.Metadata[visitsKey] = visits(execCtx, stepID)
+		}
+
+		return "", failStep(ctx, execCtx, result, execErr, attempts, span)
+	}
+
+	switch {
+	case e.dryRun:
+		result.Status = types.StepStatusSkipped
+	case outcome.Paused:
+		result.Status = types.StepStatusPending
+	}
+
+	if step.MaxVisits > 0 {
+		count := visits(execCtx, stepID)
+		if !outcome.Paused {
+			count++
+		}
+
+		result.Metadata[visitsKey] = count
+	}
+
+	execCtx.StepResults[stepID] = result
+	execCtx.LastUpdate = end
+
+	logger.DebugContext(ctx, "step finished", "status", result.Status, "attempts", attempts,
+		"duration", result.Duration, "tokensUsed", result.TokensUsed, "next", outcome.Next)
+	endStepSpan(span, result, attempts)
+
+	return outcome.Next, nil
+}
+
+// failStep records result as failed with execErr, or as canceled when execErr
+// is a cancellation, ends the step span and returns execErr.
+func failStep(
+	ctx context.Context,
+	execCtx *types.ExecutionContext,
+	result types.StepResult,
+	execErr *types.ExecutionError,
+	attempts int,
+	span trace.Span,
+) *types.ExecutionError {
+	execErr.Details = withStepID(execErr.Details, result.StepID)
+	result.Status = types.StepStatusFailed
+	if execErr.Code == CodeCanceled {
+		result.Status = types.StepStatusCanceled
+	}
+
+	result.Error = execErr
+	execCtx.StepResults[result.StepID] = result
+	execCtx.LastUpdate = result.EndTime
+
+	logging.FromContext(ctx).WarnContext(ctx, "step failed", "attempts", attempts, "duration", result.Duration,
+		"code", execErr.Code, "error", execErr.Message)
+	endStepSpan(span, result, attempts)
+
+	return execErr
+}
+
+// visits returns how many times a step with MaxVisits completed in the run
+// so far. The count is kept in the step result metadata so checkpoints carry it.
+func visits(execCtx *types.ExecutionContext, stepID string) int {
+	return metadataCount(execCtx.StepResults[stepID].Metadata, visitsKey)
+}
+
+// Attempts returns how many times the step of result was attempted, or 0
+// when its result does not record it.
+func Attempts(result types.StepResult) int {
+	return metadataCount(result.Metadata, attemptsKey)
+}
+
+// metadataCount returns the count metadata keeps under key, which is a
+// float64 after a checkpoint was loaded.
+func metadataCount(metadata map[string]any, key string) int {
+	switch count := metadata[key].(type) {
+	case int:
+		return count
+	case float64:
+		return int(count)
+	default:
+		return 0
+	}
+}
+
+// dispatch hands the step to the executor registered for its type.
+func (e *Engine) dispatch(ctx context.Context, req *StepRequest) (*StepOutcome, error) {
+	executor, exists := e.registry.Lookup(req.Step.Type)
+	if !exists {
+		return nil, newExecutionError(CodeUnsupportedStepType,
+			fmt.Sprintf("no executor registered for step type %q", req.Step.Type), nil)
+	}
+
+	outcome, err := executor.Execute(ctx, req)
+	if err != nil {
+		return outcome, fmt.Errorf("step %s: %w", req.StepID, err)
+	}
+
+	if outcome == nil {
+		return &StepOutcome{Output: nil, Next: req.Step.Next, TokensUsed: 0, Cost: 0, Metadata: nil, Paused: false}, nil
+	}
+
+	return outcome, nil
+}
+
+// NewExecutionContext creates the runtime context for a new run of flow with
+// the flow variables overridden by variables.
+func NewExecutionContext(flow *types.FlowDefinition, variables map[string]any) *types.ExecutionContext {
+	now := time.Now()
+
+	vars := make(map[string]any, len(flow.Variables)+len(variables))
+	for name, value := range flow.Variables {
+		vars[name] = value
+	}
+
+	for name, value := range variables {
+		vars[name] = value
+	}
+
+	return &types.ExecutionContext{
+		FlowID:      flow.ID,
+		SessionID:   newSessionID(now),
+		CurrentStep: flow.InitialStep,
+		Variables:   vars,
+		StepResults: make(map[string]types.StepResult),
+		StartTime:   now,
+		LastUpdate:  now,
+		Status:      types.StatusPending,
+		Error:       nil,
+		Metadata:    nil,
+	}
+}
+
+// newSessionID returns a unique, time-ordered identifier for a run.
+func newSessionID(now time.Time) string {
+	buf := make([]byte, sessionIDBytes)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return now.UTC().Format("20060102T150405.000000000")
+	}
+
+	return now.UTC().Format("20060102T150405") + "-" + hex.EncodeToString(buf)
+}
+
+// fail marks the run as failed with execErr.
+func fail(execCtx *types.ExecutionContext, execErr *types.ExecutionError) error {
+	execCtx.Status = types.StatusFailed
+	execCtx.Error = execErr
+	execCtx.LastUpdate = time.Now()
+
+	return execErr
+}
+
+// newExecutionError creates a non-recoverable execution error.
+func newExecutionError(code, message string, details any) *types.ExecutionError {
+	return &types.ExecutionError{
+		Code:        code,
+		Message:     message,
+		Details:     details,
+		Recoverable: false,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
+
+// toExecutionError converts an executor error into an ExecutionError,
+// preserving one that is already present in the error chain.
+func toExecutionError(err error) *types.ExecutionError {
+	var execErr *types.ExecutionError
+	if errors.As(err, &execErr) {
+		return execErr
+	}
+
+	return newExecutionError(CodeStepFailed, err.Error(), nil)
+}
+
+// withStepID adds the step ID to error details when they are a map or empty.
+func withStepID(details any, stepID string) any {
+	switch typed := details.(type) {
+	case nil:
+		return map[string]any{"stepId": stepID}
+	case map[string]any:
+		if _, exists := typed["stepId"]; !exists {
+			typed["stepId"] = stepID
+		}
+
+		return typed
+	default:
+		return details
+	}
+}
@@ -0,0 +1,277 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+func newEngine() *engine.Engine {
+	return engine.NewEngine(engine.NewDefaultRegistry())
+}
+
+// stepExecutors maps step types to the executors a test runs them with.
+type stepExecutors map[types.StepType]engine.StepExecutor
+
+// newTestRegistry returns the default registry with executors registered on top.
+func newTestRegistry(t *testing.T, executors stepExecutors) *engine.Registry {
+	t.Helper()
+
+	registry := engine.NewDefaultRegistry()
+	for stepType, executor := range executors {
+		require.NoError(t, registry.Register(stepType, executor))
+	}
+
+	return registry
+}
+
+// newTestEngine returns an engine that runs executors on top of the default ones.
+func newTestEngine(t *testing.T, executors stepExecutors) *engine.Engine {
+	t.Helper()
+
+	return engine.NewEngine(newTestRegistry(t, executors))
+}
+
+// newTestFlow returns a flow called id that starts at initial.
+func newTestFlow(id, initial string, steps map[string]types.Step) *types.FlowDefinition {
+	return &types.FlowDefinition{ID: id, Name: id, InitialStep: initial, Steps: steps}
+}
+
+func newConditionalFlow(expression string) *types.FlowDefinition {
+	return &types.FlowDefinition{
+		ID:          "conditional",
+		Name:        "Conditional",
+		InitialStep: "check",
+		Variables:   map[string]string{"env": "test"},
+		Steps: map[string]types.Step{
+			"check": {
+				Type: types.StepTypeCondition,
+				Conditions: []types.ConditionConfig{
+					{Expression: expression, Next: "yes"},
+				},
+				Next: "no",
+			},
+			"yes": {Type: types.StepTypeEnd},
+			"no":  {Type: types.StepTypeEnd},
+		},
+	}
+}
+
+func TestEngine_Run_FollowsMatchingCondition(t *testing.T) {
+	t.Parallel()
+
+	execCtx, err := newEngine().Run(context.Background(), newConditionalFlow("true"), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.StatusCompleted, execCtx.Status)
+	assert.Equal(t, "yes", execCtx.CurrentStep)
+	assert.Contains(t, execCtx.StepResults, "check")
+	assert.Contains(t, execCtx.StepResults, "yes")
+	assert.NotContains(t, execCtx.StepResults, "no")
+	assert.NotEmpty(t, execCtx.SessionID)
+}
+
+func TestEngine_Run_FallsBackToNext(t *testing.T) {
+	t.Parallel()
+
+	execCtx, err := newEngine().Run(context.Background(), newConditionalFlow("false"), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.StatusCompleted, execCtx.Status)
+	assert.Contains(t, execCtx.StepResults, "no")
+	assert.NotContains(t, execCtx.StepResults, "yes")
+}
+
+func TestEngine_Run_MergesVariables(t *testing.T) {
+	t.Parallel()
+
+	execCtx, err := newEngine().Run(context.Background(), newConditionalFlow("true"),
+		map[string]any{"repo": "myrepo", "env": "prod"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "myrepo", execCtx.Variables["repo"])
+	assert.Equal(t, "prod", execCtx.Variables["env"], "runtime variables override flow variables")
+}
+
+func TestEngine_Run_MissingInitialStep(t *testing.T) {
+	t.Parallel()
+
+	flow := newConditionalFlow("true")
+	flow.InitialStep = ""
+
+	execCtx, err := newEngine().Run(context.Background(), flow, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+	require.NotNil(t, execCtx.Error)
+	assert.Equal(t, engine.CodeMissingInitialStep, execCtx.Error.Code)
+}
+
+func TestEngine_Run_InvalidExpressionFailsStep(t *testing.T) {
+	t.Parallel()
+
+	execCtx, err := newEngine().Run(context.Background(), newConditionalFlow("maybe"), nil)
+	require.Error(t, err)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+	assert.Equal(t, types.StepStatusFailed, execCtx.StepResults["check"].Status)
+	assert.Equal(t, engine.CodeInvalidExpression, execCtx.Error.Code)
+}
+
+func TestEngine_Run_StepLimit(t *testing.T) {
+	t.Parallel()
+
+	flow := newTestFlow("loop", "a", map[string]types.Step{
+		"a": {
+			Type:       types.StepTypeCondition,
+			Conditions: []types.ConditionConfig{{Expression: "true", Next: "a"}},
+		},
+	})
+
+	runner := newEngine()
+	runner.SetMaxSteps(5)
+
+	execCtx, err := runner.Run(context.Background(), flow, nil)
+	require.Error(t, err)
+	assert.Equal(t, engine.CodeStepLimitExceeded, execCtx.Error.Code)
+}
+
+func TestEngine_Run_VisitLimit(t *testing.T) {
+	t.Parallel()
+
+	flow := newTestFlow("loop", "a", map[string]types.Step{
+		"a": {
+			Type:       types.StepTypeCondition,
+			Conditions: []types.ConditionConfig{{Expression: "true", Next: "a"}},
+			MaxVisits:  3,
+		},
+	})
+
+	execCtx, err := newEngine().Run(context.Background(), flow, nil)
+	require.Error(t, err)
+	assert.Equal(t, engine.CodeVisitLimitExceeded, execCtx.Error.Code)
+	assert.Equal(t, 3, execCtx.StepResults["a"].Metadata["visits"])
+}
+
+func TestEngine_Run_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	execCtx, err := newEngine().Run(ctx, newConditionalFlow("true"), nil)
+	require.Error(t, err)
+	assert.Equal(t, types.StatusCanceled, execCtx.Status)
+}
+
+func TestEngine_Run_CanceledDuringStep(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupted := engine.StepExecutorFunc(func(ctx context.Context, _ *engine.StepRequest) (*engine.StepOutcome, error) {
+		cancel()
+		<-ctx.Done()
+
+		return nil, fmt.Errorf("shell interrupted: %w", ctx.Err())
+	})
+
+	flow := newTestFlow("interrupted", "run", map[string]types.Step{"run": {Type: stepTypeShell}})
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: interrupted}).Run(ctx, flow, nil)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeCanceled, execErr.Code)
+	assert.Equal(t, types.StatusCanceled, execCtx.Status)
+	assert.Equal(t, types.StepStatusCanceled, execCtx.StepResults["run"].Status)
+	assert.Equal(t, engine.CodeCanceled, execCtx.StepResults["run"].Error.Code)
+}
+
+func TestEngine_Run_NilFlow(t *testing.T) {
+	t.Parallel()
+
+	_, err := newEngine().Run(context.Background(), nil, nil)
+	require.ErrorIs(t, err, engine.ErrNilFlow)
+}
+
+func TestEngine_Run_LogsStepsWithRunAttributes(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	logger, _, err := logging.New(logging.Options{Level: "debug", Format: "json", Console: &output})
+	require.NoError(t, err)
+
+	flowEngine := newEngine()
+	flowEngine.SetLogger(logger)
+
+	execCtx, err := flowEngine.Run(context.Background(), newConditionalFlow("unknown +"), nil)
+	require.Error(t, err)
+
+	var messages []string
+
+	for line := range strings.SplitSeq(strings.TrimSpace(output.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+
+		assert.Equal(t, "conditional", record[logging.FlowIDKey], line)
+		assert.Equal(t, execCtx.SessionID, record[logging.SessionIDKey], line)
+		assert.Equal(t, "check", record[logging.StepIDKey], line)
+
+		messages = append(messages, record["level"].(string)+" "+record["msg"].(string))
+	}
+
+	assert.Equal(t, []string{"DEBUG run started", "DEBUG step started", "WARN step failed", "ERROR run ended"}, messages)
+}
+
+func TestEngine_Run_ConditionReadsOutputsAndVariables(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestRegistry(t, stepExecutors{
+		"fetch": engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+			return &engine.StepOutcome{Output: map[string]int{"count": 5}, Next: req.Step.Next}, nil
+		}),
+	})
+
+	flow := &types.FlowDefinition{
+		ID:          "expressions",
+		Name:        "Expressions",
+		InitialStep: "fetch",
+		Variables:   map[string]string{"env": "staging"},
+		Steps: map[string]types.Step{
+			"fetch": {Type: "fetch", Next: "check"},
+			"check": {
+				Type: types.StepTypeCondition,
+				Conditions: []types.ConditionConfig{
+					{Expression: `steps.fetch.output.count > 3 && vars.env == "prod"`, Next: "deploy"},
+				},
+				Next: "skip",
+			},
+			"deploy": {Type: types.StepTypeEnd},
+			"skip":   {Type: types.StepTypeEnd},
+		},
+	}
+
+	require.NoError(t, flow.Validate(types.WithStepTypes(registry)))
+
+	execCtx, err := engine.NewEngine(registry).Run(context.Background(), flow, map[string]any{"env": "prod"})
+	require.NoError(t, err)
+	assert.Contains(t, execCtx.StepResults, "deploy")
+
+	execCtx, err = engine.NewEngine(registry).Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+	assert.Contains(t, execCtx.StepResults, "skip")
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/expr"
+)
+
+// EndExecutor terminates the flow.
+type EndExecutor struct{}
+
+// ConditionExecutor picks the next step from the first condition that holds,
+// falling back to the step's own next reference.
+type ConditionExecutor struct{}
+
+// Execute ends the flow without producing output.
+func (EndExecutor) Execute(_ context.Context, _ *StepRequest) (*StepOutcome, error) {
+	return &StepOutcome{
+		Output:     nil,
+		Next:       "",
+		TokensUsed: 0,
+		Cost:       0,
+		Metadata:   nil,
+		Paused:     false,
+	}, nil
+}
+
+// Execute evaluates the step conditions in order.
+func (ConditionExecutor) Execute(_ context.Context, req *StepRequest) (*StepOutcome, error) {
+	scope := NewScope(req.Flow, req.Execution)
+
+	for _, condition := range req.Step.Conditions {
+		matched, err := evaluateCondition(condition.Expression, scope)
+		if err != nil {
+			return nil, newExecutionError(CodeInvalidExpression,
+				fmt.Sprintf("condition %q failed: %v", condition.Expression, err),
+				map[string]any{"expression": condition.Expression})
+		}
+
+		if matched {
+			return &StepOutcome{
+				Output:     map[string]any{"expression": condition.Expression, "next": condition.Next},
+				Next:       condition.Next,
+				TokensUsed: 0,
+				Cost:       0,
+				Metadata:   nil,
+				Paused:     false,
+			}, nil
+		}
+	}
+
+	if req.Step.Next != "" {
+		return &StepOutcome{
+			Output:     map[string]any{"next": req.Step.Next},
+			Next:       req.Step.Next,
+			TokensUsed: 0,
+			Cost:       0,
+			Metadata:   nil,
+			Paused:     false,
+		}, nil
+	}
+
+	return nil, newExecutionError(CodeNoMatchingCondition, "no condition matched and no fallback step is set", nil)
+}
+
+// evaluateCondition parses and evaluates a boolean condition expression.
+func evaluateCondition(source string, scope map[string]any) (bool, error) {
+	expression, err := expr.Parse(source)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse expression: %w", err)
+	}
+
+	matched, err := expression.EvalBool(scope)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate expression: %w", err)
+	}
+
+	return matched, nil
+}
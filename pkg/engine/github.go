@@ -0,0 +1,273 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/github"
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// CodeInvalidGitHubStep is reported for github steps with a missing or malformed argument.
+const CodeInvalidGitHubStep = "INVALID_GITHUB_STEP"
+
+// GitHubAPI is the part of the GitHub API that github steps use.
+// *github.Client implements it. Its writes report failures after which the
+// write may have been applied as not recoverable, so steps never repeat them.
+type GitHubAPI interface {
+	GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error)
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error)
+	CreateComment(ctx context.Context, owner, repo string, number int, body string) (*github.Comment, error)
+	AddLabels(ctx context.Context, owner, repo string, number int, labels []string) ([]string, error)
+	ListReviewThreads(ctx context.Context, owner, repo string, number int) ([]github.ReviewThread, error)
+	ResolveReviewThread(ctx context.Context, threadID string) (*github.ReviewThread, error)
+	ReplyToReviewThread(ctx context.Context, threadID, body string) (*github.ReviewComment, error)
+}
+
+// GitHubSettings configures github steps.
+type GitHubSettings struct {
+	// Owner and Repository name the repository of steps without owner and
+	// repository arguments.
+	Owner      string
+	Repository string
+	// StrictTemplates fails argument rendering on references to missing keys.
+	StrictTemplates bool
+}
+
+// GitHubExecutor performs the GitHub operation of a github step and stores
+// the API result as the step output.
+type GitHubExecutor struct {
+	api      GitHubAPI
+	settings GitHubSettings
+}
+
+// gitHubArguments are the rendered arguments of a github step.
+type gitHubArguments struct {
+	operation string
+	values    map[string]any
+}
+
+// NewGitHubExecutor creates a github step executor calling api.
+func NewGitHubExecutor(api GitHubAPI, settings GitHubSettings) *GitHubExecutor {
+	return &GitHubExecutor{
+		api:      api,
+		settings: settings,
+	}
+}
+
+// Execute renders the step arguments and performs its operation.
+func (g *GitHubExecutor) Execute(ctx context.Context, req *StepRequest) (*StepOutcome, error) {
+	config := req.Step.GitHub
+	if config == nil {
+		return nil, newExecutionError(CodeInvalidGitHubStep, "github step has no operation", nil)
+	}
+
+	values, err := RenderArguments(req.Flow, req.Execution, req.StepID, config.Arguments, g.settings.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).DebugContext(ctx, "calling GitHub", "operation", config.Operation, "arguments", values)
+
+	output, err := g.call(ctx, gitHubArguments{operation: config.Operation, values: values})
+	if err != nil {
+		return nil, err
+	}
+
+	return &StepOutcome{
+		Output:     output,
+		Next:       req.Step.Next,
+		TokensUsed: 0,
+		Cost:       0,
+		Metadata:   map[string]any{"operation": config.Operation},
+		Paused:     false,
+	}, nil
+}
+
+// call performs the operation with its arguments.
+func (g *GitHubExecutor) call(ctx context.Context, args gitHubArguments) (any, error) {
+	if args.operation == types.GitHubResolveReviewThread {
+		return g.resolveReviewThread(ctx, args)
+	}
+
+	owner := args.text("owner", g.settings.Owner)
+	repo := args.text("repository", g.settings.Repository)
+
+	if owner == "" || repo == "" {
+		return nil, args.invalid("owner and repository")
+	}
+
+	number, err := args.number()
+	if err != nil {
+		return nil, err
+	}
+
+	switch args.operation {
+	case types.GitHubGetIssue:
+		return g.api.GetIssue(ctx, owner, repo, number)
+	case types.GitHubGetPullRequest:
+		return g.api.GetPullRequest(ctx, owner, repo, number)
+	case types.GitHubComment:
+		body := args.text("body", "")
+		if body == "" {
+			return nil, args.invalid("body")
+		}
+
+		return g.api.CreateComment(ctx, owner, repo, number, body)
+	case types.GitHubAddLabels:
+		labels := args.list("labels")
+		if len(labels) == 0 {
+			return nil, args.invalid("labels")
+		}
+
+		added, err := g.api.AddLabels(ctx, owner, repo, number, labels)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"labels": added}, nil
+	case types.GitHubListReviewThreads:
+		return g.listReviewThreads(ctx, owner, repo, number, args.flag("unresolvedOnly"))
+	default:
+		return nil, newExecutionError(CodeInvalidGitHubStep,
+			fmt.Sprintf("unknown github operation %q", args.operation), map[string]any{"operation": args.operation})
+	}
+}
+
+// listReviewThreads lists the review threads of a pull request with the number of unresolved ones.
+func (g *GitHubExecutor) listReviewThreads(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+	unresolvedOnly bool,
+) (any, error) {
+	threads, err := g.api.ListReviewThreads(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]github.ReviewThread, 0, len(threads))
+	unresolved := 0
+
+	for _, thread := range threads {
+		if !thread.IsResolved {
+			unresolved++
+		}
+
+		if !unresolvedOnly || !thread.IsResolved {
+			listed = append(listed, thread)
+		}
+	}
+
+	return map[string]any{"threads": listed, "unresolved": unresolved}, nil
+}
+
+// resolveReviewThread replies to a review thread when a body is given and resolves it.
+func (g *GitHubExecutor) resolveReviewThread(ctx context.Context, args gitHubArguments) (any, error) {
+	threadID := args.text("threadId", "")
+	if threadID == "" {
+		return nil, args.invalid("threadId")
+	}
+
+	body := args.text("body", "")
+	if body == "" {
+		return g.api.ResolveReviewThread(ctx, threadID)
+	}
+
+	_, err := g.api.ReplyToReviewThread(ctx, threadID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := g.api.ResolveReviewThread(ctx, threadID)
+	if err != nil {
+		// The reply is posted; retrying the step would post it again.
+		execErr := *toExecutionError(err)
+		execErr.Recoverable = false
+
+		return nil, &execErr
+	}
+
+	return thread, nil
+}
+
+// text returns a string argument, or fallback when it is missing or empty.
+func (a gitHubArguments) text(name, fallback string) string {
+	value, exists := a.values[name]
+	if !exists || value == nil {
+		return fallback
+	}
+
+	text := fmt.Sprint(value)
+	if text == "" {
+		return fallback
+	}
+
+	return text
+}
+
+// number returns the issue or pull request number argument.
+func (a gitHubArguments) number() (int, error) {
+	switch value := a.values["number"].(type) {
+	case int:
+		return value, nil
+	case float64:
+		return int(value), nil
+	case string:
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		if err == nil {
+			return number, nil
+		}
+	}
+
+	return 0, a.invalid("number")
+}
+
+// list returns a list argument given either as a list or as a comma-separated string.
+func (a gitHubArguments) list(name string) []string {
+	var items []string
+
+	switch value := a.values[name].(type) {
+	case []any:
+		for _, item := range value {
+			items = append(items, fmt.Sprint(item))
+		}
+	case []string:
+		items = value
+	case string:
+		items = strings.Split(value, ",")
+	}
+
+	trimmed := make([]string, 0, len(items))
+
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+
+	return trimmed
+}
+
+// flag returns a boolean argument given as a boolean or as "true".
+func (a gitHubArguments) flag(name string) bool {
+	switch value := a.values[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	default:
+		return false
+	}
+}
+
+// invalid returns the error for a missing or malformed argument.
+func (a gitHubArguments) invalid(argument string) *types.ExecutionError {
+	return newExecutionError(CodeInvalidGitHubStep,
+		fmt.Sprintf("github %s needs a valid %s argument", a.operation, argument),
+		map[string]any{"operation": a.operation, "argument": argument})
+}
@@ -0,0 +1,234 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/github"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// fakeGitHub records the calls made through the GitHubAPI interface.
+type fakeGitHub struct {
+	calls      []string
+	threads    []github.ReviewThread
+	resolveErr error
+}
+
+func (f *fakeGitHub) GetIssue(_ context.Context, owner, repo string, number int) (*github.Issue, error) {
+	f.calls = append(f.calls, fmt.Sprintf("get_issue %s/%s#%d", owner, repo, number))
+
+	return &github.Issue{Number: number, Title: "Crash on start", Labels: []string{"bug"}}, nil
+}
+
+func (f *fakeGitHub) GetPullRequest(_ context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	f.calls = append(f.calls, fmt.Sprintf("get_pull_request %s/%s#%d", owner, repo, number))
+
+	return &github.PullRequest{Number: number, Title: "Fix crash"}, nil
+}
+
+func (f *fakeGitHub) CreateComment(_ context.Context, owner, repo string, number int, body string) (*github.Comment, error) {
+	f.calls = append(f.calls, fmt.Sprintf("comment %s/%s#%d %s", owner, repo, number, body))
+
+	return &github.Comment{ID: 1, Body: body}, nil
+}
+
+func (f *fakeGitHub) AddLabels(_ context.Context, owner, repo string, number int, labels []string) ([]string, error) {
+	f.calls = append(f.calls, fmt.Sprintf("add_labels %s/%s#%d %v", owner, repo, number, labels))
+
+	return append([]string{"bug"}, labels...), nil
+}
+
+func (f *fakeGitHub) ListReviewThreads(_ context.Context, owner, repo string, number int) ([]github.ReviewThread, error) {
+	f.calls = append(f.calls, fmt.Sprintf("list_review_threads %s/%s#%d", owner, repo, number))
+
+	return f.threads, nil
+}
+
+func (f *fakeGitHub) ResolveReviewThread(_ context.Context, threadID string) (*github.ReviewThread, error) {
+	f.calls = append(f.calls, "resolve "+threadID)
+	if f.resolveErr != nil {
+		return nil, f.resolveErr
+	}
+
+	return &github.ReviewThread{ID: threadID, IsResolved: true}, nil
+}
+
+func (f *fakeGitHub) ReplyToReviewThread(_ context.Context, threadID, body string) (*github.ReviewComment, error) {
+	f.calls = append(f.calls, "reply "+threadID+" "+body)
+
+	return &github.ReviewComment{Body: body}, nil
+}
+
+func executeGitHubStep(
+	t *testing.T,
+	api engine.GitHubAPI,
+	config *types.GitHubConfig,
+	variables map[string]any,
+) (*engine.StepOutcome, error) {
+	t.Helper()
+
+	step := types.Step{Type: types.StepTypeGitHub, GitHub: config, Next: "done"}
+	flow := &types.FlowDefinition{ID: "triage", Steps: map[string]types.Step{"gh": step}}
+	executor := engine.NewGitHubExecutor(api, engine.GitHubSettings{Owner: "acme", Repository: "widgets"})
+
+	return executor.Execute(context.Background(), &engine.StepRequest{
+		Flow:      flow,
+		StepID:    "gh",
+		Step:      step,
+		Execution: engine.NewExecutionContext(flow, variables),
+	})
+}
+
+func TestGitHubExecutor_Operations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config *types.GitHubConfig
+		call   string
+		output any
+	}{
+		{
+			name:   "get issue from rendered number",
+			config: &types.GitHubConfig{Operation: types.GitHubGetIssue, Arguments: map[string]any{"number": "{{.vars.pr}}"}},
+			call:   "get_issue acme/widgets#42",
+			output: &github.Issue{Number: 42, Title: "Crash on start", Labels: []string{"bug"}},
+		},
+		{
+			name: "get pull request from another repository",
+			config: &types.GitHubConfig{Operation: types.GitHubGetPullRequest, Arguments: map[string]any{
+				"owner": "other", "repository": "tools", "number": float64(7),
+			}},
+			call:   "get_pull_request other/tools#7",
+			output: &github.PullRequest{Number: 7, Title: "Fix crash"},
+		},
+		{
+			name: "comment",
+			config: &types.GitHubConfig{Operation: types.GitHubComment, Arguments: map[string]any{
+				"number": "{{.vars.pr}}", "body": "Reviewed by {{.flow.id}}",
+			}},
+			call:   "comment acme/widgets#42 Reviewed by triage",
+			output: &github.Comment{ID: 1, Body: "Reviewed by triage"},
+		},
+		{
+			name: "add labels from a comma-separated string",
+			config: &types.GitHubConfig{Operation: types.GitHubAddLabels, Arguments: map[string]any{
+				"number": 42, "labels": "reviewed, needs-tests",
+			}},
+			call:   "add_labels acme/widgets#42 [reviewed needs-tests]",
+			output: map[string]any{"labels": []string{"bug", "reviewed", "needs-tests"}},
+		},
+		{
+			name: "resolve review thread with a reply",
+			config: &types.GitHubConfig{Operation: types.GitHubResolveReviewThread, Arguments: map[string]any{
+				"threadId": "T1", "body": "Fixed",
+			}},
+			call:   "reply T1 Fixed",
+			output: &github.ReviewThread{ID: "T1", IsResolved: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			api := &fakeGitHub{}
+
+			outcome, err := executeGitHubStep(t, api, tt.config, map[string]any{"pr": "42"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.call, api.calls[0])
+			assert.Equal(t, tt.output, outcome.Output)
+			assert.Equal(t, "done", outcome.Next)
+			assert.Equal(t, tt.config.Operation, outcome.Metadata["operation"])
+		})
+	}
+}
+
+func TestGitHubExecutor_ListReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeGitHub{threads: []github.ReviewThread{
+		{ID: "T1", IsResolved: true},
+		{ID: "T2", IsResolved: false},
+	}}
+
+	outcome, err := executeGitHubStep(t, api, &types.GitHubConfig{
+		Operation: types.GitHubListReviewThreads,
+		Arguments: map[string]any{"number": 8, "unresolvedOnly": true},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"threads":    []github.ReviewThread{{ID: "T2", IsResolved: false}},
+		"unresolved": 1,
+	}, outcome.Output)
+}
+
+func TestGitHubExecutor_ResolveFailsAfterReply(t *testing.T) {
+	t.Parallel()
+
+	unavailable := &types.ExecutionError{Code: github.CodeGitHubRequestFailed, Message: "GitHub returned 502",
+		Recoverable: true}
+
+	for _, body := range []string{"", "Fixed in 1a2b3c"} {
+		api := &fakeGitHub{resolveErr: unavailable}
+
+		_, err := executeGitHubStep(t, api, &types.GitHubConfig{
+			Operation: types.GitHubResolveReviewThread,
+			Arguments: map[string]any{"threadId": "T1", "body": body},
+		}, nil)
+
+		var execErr *types.ExecutionError
+		require.ErrorAs(t, err, &execErr)
+		assert.Equal(t, github.CodeGitHubRequestFailed, execErr.Code)
+		assert.Equal(t, body == "", execErr.Recoverable, "a step that posted its reply is not retried")
+	}
+
+	assert.True(t, unavailable.Recoverable)
+}
+
+func TestGitHubExecutor_InvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		config   *types.GitHubConfig
+		argument string
+	}{
+		{name: "missing number", config: &types.GitHubConfig{Operation: types.GitHubGetIssue}, argument: "number"},
+		{
+			name:     "missing body",
+			config:   &types.GitHubConfig{Operation: types.GitHubComment, Arguments: map[string]any{"number": 1}},
+			argument: "body",
+		},
+		{
+			name:     "missing thread",
+			config:   &types.GitHubConfig{Operation: types.GitHubResolveReviewThread},
+			argument: "threadId",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			api := &fakeGitHub{}
+
+			_, err := executeGitHubStep(t, api, tt.config, nil)
+
+			var execErr *types.ExecutionError
+			require.ErrorAs(t, err, &execErr)
+			assert.Equal(t, engine.CodeInvalidGitHubStep, execErr.Code)
+			assert.Equal(t, tt.argument, execErr.Details.(map[string]any)["argument"])
+			assert.Empty(t, api.calls)
+		})
+	}
+}
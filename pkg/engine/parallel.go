@@ -0,0 +1,313 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Error codes reported by parallel steps.
+const (
+	CodeInvalidParallel = "INVALID_PARALLEL"
+	CodeBranchFailed    = "BRANCH_FAILED"
+	CodeMergeConflict   = "MERGE_CONFLICT"
+)
+
+// ParallelSettings configures how parallel steps run their branches.
+type ParallelSettings struct {
+	// Concurrent runs the branches at the same time; otherwise they run one
+	// after another in the order they are listed.
+	Concurrent bool
+}
+
+// ParallelExecutor fans a parallel step out into its branches and joins
+// them once all of them, or the first ParallelConfig.Wait, have completed.
+// Every branch runs in its own copy of the execution context; the step
+// results of the branches are merged back when the join succeeds.
+type ParallelExecutor struct {
+	settings ParallelSettings
+}
+
+// branch is the state of one branch of a parallel step.
+type branch struct {
+	entry     string
+	execution *types.ExecutionContext
+	status    types.StepStatus
+	err       *types.ExecutionError
+}
+
+// join counts finished branches and cancels the others once the parallel
+// step has enough completed branches or can no longer get them.
+type join struct {
+	mu        sync.Mutex
+	total     int
+	required  int
+	completed int
+	failed    int
+	decided   bool
+	cancel    context.CancelFunc
+}
+
+// NewParallelExecutor creates a parallel step executor.
+func NewParallelExecutor(settings ParallelSettings) *ParallelExecutor {
+	return &ParallelExecutor{settings: settings}
+}
+
+// Execute runs the branches, merges their step results and reports the
+// status and final output of every branch.
+func (p *ParallelExecutor) Execute(ctx context.Context, req *StepRequest) (*StepOutcome, error) {
+	config := req.Step.Parallel
+	if config == nil || len(config.Branches) == 0 {
+		return nil, newExecutionError(CodeInvalidParallel, "parallel step has no branches", nil)
+	}
+
+	if req.Branches == nil {
+		return nil, newExecutionError(CodeInvalidParallel, "parallel step has no branch runner", nil)
+	}
+
+	branches := make([]*branch, len(config.Branches))
+	for i, entry := range config.Branches {
+		branches[i] = &branch{
+			entry:     entry,
+			execution: forkExecution(req.Execution),
+			status:    types.StepStatusSkipped,
+			err:       nil,
+		}
+	}
+
+	branchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	required := config.Wait
+	if required == 0 {
+		required = len(branches)
+	}
+
+	joined := &join{
+		mu:        sync.Mutex{},
+		total:     len(branches),
+		required:  required,
+		completed: 0,
+		failed:    0,
+		decided:   false,
+		cancel:    cancel,
+	}
+
+	budget := req.Budget
+	if budget == nil {
+		budget = unlimitedBudget()
+	}
+
+	run := func(b *branch) {
+		joined.record(b, req.Branches.RunBranch(branchCtx, req.Flow, b.execution, b.entry, req.Step.Next, budget))
+	}
+
+	if p.settings.Concurrent {
+		runConcurrently(branches, run)
+	} else {
+		for _, b := range branches {
+			if joined.isDecided() {
+				break
+			}
+
+			run(b)
+		}
+	}
+
+	if joined.completed < required {
+		return nil, branchFailure(ctx, branches)
+	}
+
+	outputs := branchOutputs(req.Execution, branches)
+
+	err := mergeBranches(req.Execution, branches, config.Merge)
+	if err != nil {
+		return nil, err
+	}
+
+	// The branches, whether or not they completed, spent under the budget of the parallel step.
+	tokens, cost := budget.spent.total()
+
+	return &StepOutcome{
+		Output:     map[string]any{"branches": outputs, "completed": joined.completed},
+		Next:       req.Step.Next,
+		TokensUsed: tokens,
+		Cost:       cost,
+		Metadata:   nil,
+		Paused:     false,
+	}, nil
+}
+
+// runConcurrently runs every branch in its own goroutine and waits for all of them.
+func runConcurrently(branches []*branch, run func(*branch)) {
+	var wg sync.WaitGroup
+
+	for _, b := range branches {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			run(b)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// record stores the outcome of a branch. A branch that fails after the
+// join was decided was canceled by it and counts as skipped.
+func (j *join) record(b *branch, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch {
+	case err == nil:
+		b.status = types.StepStatusCompleted
+		j.completed++
+	case j.decided:
+		b.status = types.StepStatusSkipped
+	default:
+		b.status = types.StepStatusFailed
+		b.err = toExecutionError(err)
+		j.failed++
+	}
+
+	if !j.decided && (j.completed >= j.required || j.failed > j.total-j.required) {
+		j.decided = true
+		j.cancel()
+	}
+}
+
+// isDecided reports whether the join no longer needs more branches.
+func (j *join) isDecided() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.decided
+}
+
+// branchFailure returns the error of the first failed branch in listed order,
+// or a cancellation error when the parallel step itself was canceled. A
+// branch that took the run over budget fails the step with its budget error.
+func branchFailure(ctx context.Context, branches []*branch) *types.ExecutionError {
+	err := ctx.Err()
+	if err != nil {
+		return newExecutionError(CodeCanceled, err.Error(), nil)
+	}
+
+	for _, b := range branches {
+		if b.status == types.StepStatusFailed && b.err.Code == CodeBudgetExceeded {
+			return b.err
+		}
+	}
+
+	for _, b := range branches {
+		if b.status == types.StepStatusFailed {
+			return newExecutionError(CodeBranchFailed,
+				fmt.Sprintf("branch %s failed: %s", b.entry, b.err.Message),
+				map[string]any{"branch": b.entry, "failedStep": b.execution.CurrentStep, "cause": b.err.Code})
+		}
+	}
+
+	return newExecutionError(CodeBranchFailed, "not enough branches completed", nil)
+}
+
+// mergeBranches copies the step results of the completed branches into
+// execCtx. With MergeUnique a step that ran in more than one branch is a
+// conflict; with MergeOrdered the branch listed last wins.
+func mergeBranches(execCtx *types.ExecutionContext, branches []*branch, strategy string) *types.ExecutionError {
+	owners := make(map[string]string)
+	merged := make(map[string]types.StepResult)
+
+	for _, b := range branches {
+		if b.status != types.StepStatusCompleted {
+			continue
+		}
+
+		for stepID, result := range branchResults(execCtx, b.execution) {
+			if owner, exists := owners[stepID]; exists && strategy != types.MergeOrdered {
+				return newExecutionError(CodeMergeConflict,
+					fmt.Sprintf("step %s ran in branches %s and %s", stepID, owner, b.entry),
+					map[string]any{"conflictingStep": stepID, "branches": []string{owner, b.entry}})
+			}
+
+			owners[stepID] = b.entry
+			merged[stepID] = result
+		}
+	}
+
+	maps.Copy(execCtx.StepResults, merged)
+
+	return nil
+}
+
+// branchOutputs reports the status of every branch, and the steps and final
+// output of the completed ones, keyed by branch entry step.
+func branchOutputs(execCtx *types.ExecutionContext, branches []*branch) map[string]any {
+	outputs := make(map[string]any, len(branches))
+
+	for _, b := range branches {
+		output := map[string]any{"status": string(b.status)}
+
+		if b.status == types.StepStatusCompleted {
+			steps := slices.Sorted(maps.Keys(branchResults(execCtx, b.execution)))
+			output["steps"] = steps
+			output["output"] = b.execution.StepResults[b.execution.CurrentStep].Output
+		}
+
+		outputs[b.entry] = output
+	}
+
+	return outputs
+}
+
+// branchResults returns the step results a branch added or replaced.
+func branchResults(parent, execution *types.ExecutionContext) map[string]types.StepResult {
+	results := make(map[string]types.StepResult)
+
+	for stepID, result := range execution.StepResults {
+		if before, exists := parent.StepResults[stepID]; exists && before.StartTime.Equal(result.StartTime) {
+			continue
+		}
+
+		results[stepID] = result
+	}
+
+	return results
+}
+
+// forkExecution copies execCtx for a branch so that branches never write to
+// shared maps. Metadata is copied deeply, as steps such as approvals keep
+// their state in maps nested in it.
+func forkExecution(execCtx *types.ExecutionContext) *types.ExecutionContext {
+	fork := *execCtx
+	fork.Variables = maps.Clone(execCtx.Variables)
+	fork.StepResults = maps.Clone(execCtx.StepResults)
+	fork.Metadata = cloneMetadata(execCtx.Metadata)
+	fork.Error = nil
+
+	return &fork
+}
+
+// cloneMetadata copies metadata and the maps nested in it.
+func cloneMetadata(metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+
+	clone := make(map[string]any, len(metadata))
+
+	for key, value := range metadata {
+		if nested, ok := value.(map[string]any); ok {
+			value = cloneMetadata(nested)
+		}
+
+		clone[key] = value
+	}
+
+	return clone
+}
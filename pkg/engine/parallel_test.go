@@ -0,0 +1,244 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var errBranchesNotConcurrent = errors.New("branches did not run concurrently")
+
+// newFanOutFlow returns a flow that fans out into branches a, b and c, each
+// of which runs a single shell step before joining at join.
+func newFanOutFlow(parallel types.ParallelConfig) *types.FlowDefinition {
+	parallel.Branches = []string{"a", "b", "c"}
+
+	return &types.FlowDefinition{
+		ID:          "fan-out",
+		Name:        "Fan out",
+		InitialStep: "fanout",
+		Steps: map[string]types.Step{
+			"fanout": {Type: types.StepTypeParallel, Parallel: &parallel, Next: "join"},
+			"a":      {Type: stepTypeShell, Next: "join"},
+			"b":      {Type: stepTypeShell, Next: "join"},
+			"c":      {Type: stepTypeShell, Next: "join"},
+			"join":   {Type: types.StepTypeEnd},
+		},
+	}
+}
+
+func runParallelFlow(
+	t *testing.T,
+	flow *types.FlowDefinition,
+	concurrent bool,
+	shell engine.StepExecutorFunc,
+) (*types.ExecutionContext, error) {
+	t.Helper()
+
+	flowEngine := newTestEngine(t, stepExecutors{
+		stepTypeShell:          shell,
+		types.StepTypeParallel: engine.NewParallelExecutor(engine.ParallelSettings{Concurrent: concurrent}),
+	})
+
+	return flowEngine.Run(context.Background(), flow, nil)
+}
+
+func branchOutput(t *testing.T, execCtx *types.ExecutionContext, entry string) map[string]any {
+	t.Helper()
+
+	output, ok := execCtx.StepResults["fanout"].Output.(map[string]any)
+	require.True(t, ok)
+
+	branches, ok := output["branches"].(map[string]any)
+	require.True(t, ok)
+
+	result, ok := branches[entry].(map[string]any)
+	require.True(t, ok)
+
+	return result
+}
+
+func TestParallelExecutor_RunsBranchesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	var started sync.WaitGroup
+
+	started.Add(3)
+
+	allStarted := make(chan struct{})
+
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+
+	shell := engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+		started.Done()
+
+		select {
+		case <-allStarted:
+		case <-time.After(5 * time.Second):
+			return nil, errBranchesNotConcurrent
+		}
+
+		return &engine.StepOutcome{Output: "reviewed " + req.StepID, Next: req.Step.Next}, nil
+	})
+
+	execCtx, err := runParallelFlow(t, newFanOutFlow(types.ParallelConfig{}), true, shell)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.StatusCompleted, execCtx.Status)
+	assert.Equal(t, "join", execCtx.CurrentStep)
+
+	for _, entry := range []string{"a", "b", "c"} {
+		assert.Equal(t, "reviewed "+entry, execCtx.StepResults[entry].Output)
+
+		output := branchOutput(t, execCtx, entry)
+		assert.Equal(t, "completed", output["status"])
+		assert.Equal(t, "reviewed "+entry, output["output"])
+		assert.Equal(t, []string{entry}, output["steps"])
+	}
+
+	assert.Equal(t, types.StepStatusCompleted, execCtx.StepResults["join"].Status)
+}
+
+func TestParallelExecutor_WaitsForFirstBranches(t *testing.T) {
+	t.Parallel()
+
+	shell := engine.StepExecutorFunc(func(ctx context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+		if req.StepID != "b" {
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}
+
+		return &engine.StepOutcome{Output: "fast", Next: req.Step.Next}, nil
+	})
+
+	execCtx, err := runParallelFlow(t, newFanOutFlow(types.ParallelConfig{Wait: 1}), true, shell)
+	require.NoError(t, err)
+
+	assert.Equal(t, "completed", branchOutput(t, execCtx, "b")["status"])
+	assert.Equal(t, "skipped", branchOutput(t, execCtx, "a")["status"])
+	assert.Equal(t, "skipped", branchOutput(t, execCtx, "c")["status"])
+	assert.Contains(t, execCtx.StepResults, "b")
+	assert.NotContains(t, execCtx.StepResults, "a", "canceled branches are not merged")
+}
+
+func TestParallelExecutor_SequentialWhenNotConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+
+	shell := engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+		order = append(order, req.StepID)
+		if req.StepID == "b" {
+			return nil, errShellFailed
+		}
+
+		return &engine.StepOutcome{Next: req.Step.Next}, nil
+	})
+
+	execCtx, err := runParallelFlow(t, newFanOutFlow(types.ParallelConfig{}), false, shell)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, order, "the join fails as soon as a required branch fails")
+	assert.Equal(t, engine.CodeBranchFailed, execCtx.Error.Code)
+	assert.Equal(t, "b", execCtx.Error.Details.(map[string]any)["branch"])
+	assert.Equal(t, "fanout", execCtx.CurrentStep)
+}
+
+func TestParallelExecutor_MergeStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		merge   string
+		wantErr string
+	}{
+		{name: "unique", merge: types.MergeUnique, wantErr: engine.CodeMergeConflict},
+		{name: "ordered", merge: types.MergeOrdered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flow := newFanOutFlow(types.ParallelConfig{Merge: tt.merge})
+			flow.Steps["a"] = types.Step{Type: stepTypeShell, Next: "shared"}
+			flow.Steps["c"] = types.Step{Type: stepTypeShell, Next: "shared"}
+			flow.Steps["shared"] = types.Step{Type: stepTypeShell, Next: "join"}
+
+			var mu sync.Mutex
+
+			shell := engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				previous := req.Execution.StepResults["a"].Output
+				if req.Execution.StepResults["c"].Output != nil {
+					previous = req.Execution.StepResults["c"].Output
+				}
+
+				return &engine.StepOutcome{Output: req.StepID + " after " + toString(previous), Next: req.Step.Next}, nil
+			})
+
+			execCtx, err := runParallelFlow(t, flow, true, shell)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, execCtx.Error.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "shared after c after <nil>", execCtx.StepResults["shared"].Output,
+				"the branch listed last wins")
+		})
+	}
+}
+
+func TestParallelExecutor_BranchesCopyNestedMetadata(t *testing.T) {
+	t.Parallel()
+
+	flow := newFanOutFlow(types.ParallelConfig{})
+	flow.InitialStep = "seed"
+	flow.Steps["seed"] = types.Step{Type: stepTypeShell, Next: "fanout"}
+
+	shell := engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+		if req.StepID == "seed" {
+			req.Execution.Metadata = map[string]any{"approvals": map[string]any{"gate": "approved"}}
+		} else {
+			approvals, ok := req.Execution.Metadata["approvals"].(map[string]any)
+			require.True(t, ok)
+
+			delete(approvals, "gate")
+			approvals[req.StepID] = "approved"
+		}
+
+		return &engine.StepOutcome{Next: req.Step.Next}, nil
+	})
+
+	execCtx, err := runParallelFlow(t, flow, true, shell)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"gate": "approved"}, execCtx.Metadata["approvals"],
+		"branches write to their own copy of nested metadata")
+}
+
+func toString(value any) string {
+	if value == nil {
+		return "<nil>"
+	}
+
+	return value.(string)
+}
@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Error codes reported by prompt steps.
+const (
+	CodeInvalidPrompt      = "INVALID_PROMPT"
+	CodeToolNotFound       = "TOOL_NOT_FOUND"
+	CodeToolLimitExceeded  = "TOOL_LIMIT_EXCEEDED"
+	CodeInvalidToolRequest = "INVALID_TOOL_REQUEST"
+)
+
+// DefaultMaxToolIterations bounds how many completions a prompt step with
+// tools may request before the model must give a final answer.
+const DefaultMaxToolIterations = 10
+
+// PromptSettings configures how prompt steps call the language model.
+type PromptSettings struct {
+	DefaultModel   string
+	ModelOverrides map[string]string
+	MaxTokens      int
+	Temperature    float64
+	// StrictTemplates fails prompt rendering on references to missing keys.
+	StrictTemplates bool
+	// MaxToolIterations caps the completions of a tool-calling prompt step.
+	// Zero selects DefaultMaxToolIterations.
+	MaxToolIterations int
+}
+
+// PromptExecutor sends prompt steps to a language model provider and stores
+// the completion text as the step output. Steps that declare tools run an
+// agent loop: the model may call those tools until it gives a final answer.
+type PromptExecutor struct {
+	provider llm.Provider
+	settings PromptSettings
+	embedded ToolCatalog
+	servers  ToolCatalog
+}
+
+// NewPromptExecutor creates a prompt step executor backed by provider.
+func NewPromptExecutor(provider llm.Provider, settings PromptSettings) *PromptExecutor {
+	return &PromptExecutor{
+		provider: provider,
+		settings: settings,
+		embedded: nil,
+		servers:  nil,
+	}
+}
+
+// SetTools sets where the tools declared by prompt steps are found:
+// embedded tools and the tools of each step's MCPServer.
+func (p *PromptExecutor) SetTools(embedded, servers ToolCatalog) {
+	p.embedded = embedded
+	p.servers = servers
+}
+
+// Execute renders the prompt, runs the completion loop and records token
+// usage, cost and every tool call made along the way. A step that fails
+// after the first completion request still reports them with its error.
+func (p *PromptExecutor) Execute(ctx context.Context, req *StepRequest) (*StepOutcome, error) {
+	prompt, err := RenderPrompt(req.Flow, req.Execution, req.StepID, req.Step, p.settings.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := p.resolveTools(ctx, req.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	model := p.ResolveModel(req.Step.Model)
+	limit := p.maxIterations(len(tools.definitions))
+	loop := newAgentLoop(req.StepID, tools, prompt.Messages())
+
+	logger := logging.FromContext(ctx)
+
+	for iteration := 1; iteration <= limit; iteration++ {
+		logger.DebugContext(ctx, "requesting completion", "model", model, "iteration", iteration,
+			"messages", len(loop.messages), "tools", len(tools.definitions))
+
+		spanCtx, span := startCompletionSpan(ctx, model, iteration)
+		resp, err := p.provider.Complete(spanCtx, &llm.Request{
+			Model:       model,
+			Messages:    loop.messages,
+			Tools:       tools.definitions,
+			MaxTokens:   p.settings.MaxTokens,
+			Temperature: p.settings.Temperature,
+		})
+		endCompletionSpan(span, resp, err)
+
+		if err != nil {
+			return loop.partial(model, iteration-1), fmt.Errorf("completion with model %s failed: %w", model, err)
+		}
+
+		logger.DebugContext(ctx, "completion received", "model", model, "iteration", iteration,
+			"tokensUsed", resp.Usage.TotalTokens, "toolCalls", len(resp.ToolCalls))
+
+		loop.record(resp)
+
+		if len(resp.ToolCalls) == 0 {
+			return loop.outcome(req.Step.Next, resp, model, iteration), nil
+		}
+
+		// The engine fails the step once it is over budget; stop before the
+		// tools run and the model is asked again.
+		if req.Budget.Exceeded(loop.usage.TotalTokens, loop.cost) {
+			logger.DebugContext(ctx, "budget exceeded, stopping tool calls", "iteration", iteration,
+				"tokensUsed", loop.usage.TotalTokens, "cost", loop.cost)
+
+			return loop.outcome(req.Step.Next, resp, model, iteration), nil
+		}
+
+		err = loop.callTools(ctx, resp)
+		if err != nil {
+			return loop.partial(model, iteration), err
+		}
+	}
+
+	return loop.partial(model, limit), newExecutionError(CodeToolLimitExceeded,
+		fmt.Sprintf("model did not give a final answer within %d tool iterations", limit),
+		map[string]any{"toolCalls": len(loop.calls)})
+}
+
+// ResolveModel returns the model for a step: the step's own model or the
+// default model, replaced by its override when one is configured.
+func (p *PromptExecutor) ResolveModel(stepModel string) string {
+	return p.settings.ResolveModel(stepModel)
+}
+
+// ResolveModel returns the model prompt steps with these settings call for
+// a step declaring stepModel.
+func (s PromptSettings) ResolveModel(stepModel string) string {
+	model := stepModel
+	if model == "" {
+		model = s.DefaultModel
+	}
+
+	if override, exists := s.ModelOverrides[model]; exists && override != "" {
+		return override
+	}
+
+	return model
+}
+
+// maxIterations returns how many completions a step may request. A step
+// without tools needs exactly one.
+func (p *PromptExecutor) maxIterations(toolCount int) int {
+	if toolCount == 0 {
+		return 1
+	}
+
+	if p.settings.MaxToolIterations > 0 {
+		return p.settings.MaxToolIterations
+	}
+
+	return DefaultMaxToolIterations
+}
+
+// resolveTools finds every tool a step declares. A tool offered by the
+// step's MCPServer takes precedence over an embedded tool of the same name.
+func (p *PromptExecutor) resolveTools(ctx context.Context, step types.Step) (*toolset, error) {
+	set := &toolset{definitions: nil, bindings: make(map[string]toolBinding, len(step.Tools))}
+	if len(step.Tools) == 0 {
+		return set, nil
+	}
+
+	available := make(map[string]toolBinding)
+
+	if p.embedded != nil {
+		err := addTools(ctx, available, p.embedded, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.servers != nil && step.MCPServer != "" {
+		err := addTools(ctx, available, p.servers, step.MCPServer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range step.Tools {
+		binding, exists := available[name]
+		if !exists {
+			return nil, newExecutionError(CodeToolNotFound,
+				fmt.Sprintf("declared tool %s is not available", name),
+				map[string]any{"tool": name, "server": step.MCPServer})
+		}
+
+		set.bindings[name] = binding
+		set.definitions = append(set.definitions, llm.Tool{
+			Name:        binding.tool.Name,
+			Description: binding.tool.Description,
+			Parameters:  binding.tool.Schema,
+		})
+	}
+
+	return set, nil
+}
+
+// addTools adds the tools listed by catalog to available, replacing tools of the same name.
+func addTools(ctx context.Context, available map[string]toolBinding, catalog ToolCatalog, server string) error {
+	listed, err := catalog.ListTools(ctx, server)
+	if err != nil {
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+
+	for _, tool := range listed {
+		available[tool.Name] = toolBinding{tool: tool, caller: catalog}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// fakeProvider records requests and answers with a fixed response.
+type fakeProvider struct {
+	requests []*llm.Request
+	response *llm.Response
+	err      error
+}
+
+func (f *fakeProvider) Complete(_ context.Context, req *llm.Request) (*llm.Response, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return f.response, nil
+}
+
+func newPromptFlow(model string) *types.FlowDefinition {
+	return &types.FlowDefinition{
+		ID:          "prompting",
+		Name:        "Prompting",
+		InitialStep: "ask",
+		Variables:   map[string]string{"repo": "flow-test-go"},
+		Steps: map[string]types.Step{
+			"ask": {
+				Type:  types.StepTypePrompt,
+				Model: model,
+				Prompt: &types.PromptConfig{
+					System:   "You review {{.flow.name}} flows.",
+					Template: "Summarize {{.vars.repo}}",
+				},
+				Next: "done",
+			},
+			"done": {Type: types.StepTypeEnd},
+		},
+	}
+}
+
+func TestPromptExecutor_Execute(t *testing.T) {
+	t.Parallel()
+
+	provider := &fakeProvider{response: &llm.Response{
+		Content:      "A flow engine.",
+		Model:        "anthropic/claude-3.5-sonnet",
+		FinishReason: "stop",
+		Usage:        llm.Usage{PromptTokens: 20, CompletionTokens: 5, TotalTokens: 25},
+		Cost:         0.0031,
+	}}
+
+	executor := engine.NewPromptExecutor(provider, engine.PromptSettings{
+		DefaultModel:   "openai/gpt-4-turbo",
+		ModelOverrides: map[string]string{"smart": "anthropic/claude-3.5-sonnet"},
+		MaxTokens:      256,
+		Temperature:    0.1,
+	})
+
+	execCtx, err := newTestEngine(t, stepExecutors{types.StepTypePrompt: executor}).
+		Run(context.Background(), newPromptFlow("smart"), nil)
+	require.NoError(t, err)
+
+	require.Len(t, provider.requests, 1)
+	req := provider.requests[0]
+	assert.Equal(t, "anthropic/claude-3.5-sonnet", req.Model)
+	assert.Equal(t, 256, req.MaxTokens)
+	assert.Equal(t, []llm.Message{
+		{Role: llm.RoleSystem, Content: "You review Prompting flows."},
+		{Role: llm.RoleUser, Content: "Summarize flow-test-go"},
+	}, req.Messages)
+
+	result := execCtx.StepResults["ask"]
+	assert.Equal(t, "A flow engine.", result.Output)
+	assert.Equal(t, 25, result.TokensUsed)
+	assert.InDelta(t, 0.0031, result.Cost, 1e-9)
+	assert.Equal(t, "anthropic/claude-3.5-sonnet", result.Metadata["model"])
+	assert.Contains(t, execCtx.StepResults, "done")
+}
+
+func TestPromptExecutor_ResolveModel(t *testing.T) {
+	t.Parallel()
+
+	executor := engine.NewPromptExecutor(&fakeProvider{}, engine.PromptSettings{
+		DefaultModel:   "openai/gpt-4-turbo",
+		ModelOverrides: map[string]string{"openai/gpt-4-turbo": "openai/gpt-4o", "fast": "openai/gpt-4o-mini"},
+	})
+
+	assert.Equal(t, "openai/gpt-4o", executor.ResolveModel(""))
+	assert.Equal(t, "openai/gpt-4o-mini", executor.ResolveModel("fast"))
+	assert.Equal(t, "meta/llama-3", executor.ResolveModel("meta/llama-3"))
+}
+
+func TestPromptExecutor_ProviderError(t *testing.T) {
+	t.Parallel()
+
+	provider := &fakeProvider{err: &types.ExecutionError{Code: llm.CodeLLMRequestFailed, Message: "rate limited", Recoverable: true}}
+
+	executor := engine.NewPromptExecutor(provider, engine.PromptSettings{})
+
+	execCtx, err := newTestEngine(t, stepExecutors{types.StepTypePrompt: executor}).
+		Run(context.Background(), newPromptFlow(""), nil)
+	require.Error(t, err)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+	assert.Equal(t, llm.CodeLLMRequestFailed, execCtx.Error.Code)
+	assert.True(t, execCtx.Error.Recoverable)
+}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var (
+	// ErrDuplicateStepType is returned when a step type already has an executor.
+	ErrDuplicateStepType = errors.New("step type already has a registered executor")
+
+	// ErrInvalidExecutor is returned when registering an empty step type or a nil executor.
+	ErrInvalidExecutor = errors.New("step type and executor are required")
+)
+
+// StepExecutor runs every step of the step type it is registered for.
+type StepExecutor interface {
+	Execute(ctx context.Context, req *StepRequest) (*StepOutcome, error)
+}
+
+// StepExecutorFunc adapts an ordinary function to the StepExecutor interface.
+type StepExecutorFunc func(ctx context.Context, req *StepRequest) (*StepOutcome, error)
+
+// StepRequest describes the step an executor is asked to run.
+// Branches runs parts of the flow for steps that start branches of their own.
+type StepRequest struct {
+	Flow      *types.FlowDefinition
+	StepID    string
+	Step      types.Step
+	Execution *types.ExecutionContext
+	Branches  BranchRunner
+	// Budget is what the step may spend; nil is unlimited.
+	Budget *Budget
+}
+
+// BranchRunner executes steps of a flow in an execution context from stepID
+// until the next step is until or the flow ends, counting what they spend
+// under budget. The engine implements it.
+type BranchRunner interface {
+	RunBranch(ctx context.Context, flow *types.FlowDefinition, execCtx *types.ExecutionContext,
+		stepID string, until string, budget *Budget) error
+}
+
+// StepOutcome is what an executor reports back to the engine after running a step.
+// Next names the step to run afterwards; an empty Next ends the flow.
+// Paused stops the run at this step until it is resumed, when the step runs again.
+// An executor that fails may return the outcome of what it did before failing
+// along with its error; the engine keeps its usage and metadata.
+type StepOutcome struct {
+	Output     any
+	Next       string
+	TokensUsed int
+	Cost       float64
+	Metadata   map[string]any
+	Paused     bool
+}
+
+// Registry maps step types to the executors that run them.
+type Registry struct {
+	mu        sync.RWMutex
+	executors map[types.StepType]StepExecutor
+}
+
+// NewRegistry creates an empty step executor registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		mu:        sync.RWMutex{},
+		executors: make(map[types.StepType]StepExecutor),
+	}
+}
+
+// NewDefaultRegistry creates a registry with the built-in step executors registered.
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	registry.mustRegister(types.StepTypeEnd, EndExecutor{})
+	registry.mustRegister(types.StepTypeCondition, ConditionExecutor{})
+
+	return registry
+}
+
+// Register attaches executor to stepType.
+func (r *Registry) Register(stepType types.StepType, executor StepExecutor) error {
+	if stepType == "" || executor == nil {
+		return ErrInvalidExecutor
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.executors[stepType]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateStepType, stepType)
+	}
+
+	r.executors[stepType] = executor
+
+	return nil
+}
+
+// Lookup returns the executor registered for stepType.
+func (r *Registry) Lookup(stepType types.StepType) (StepExecutor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	executor, exists := r.executors[stepType]
+
+	return executor, exists
+}
+
+// Supports reports whether stepType has a registered executor.
+func (r *Registry) Supports(stepType types.StepType) bool {
+	_, exists := r.Lookup(stepType)
+
+	return exists
+}
+
+// StepTypes returns the registered step types in sorted order.
+func (r *Registry) StepTypes() []types.StepType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stepTypes := make([]types.StepType, 0, len(r.executors))
+	for stepType := range r.executors {
+		stepTypes = append(stepTypes, stepType)
+	}
+
+	slices.Sort(stepTypes)
+
+	return stepTypes
+}
+
+// mustRegister registers a built-in executor, panicking on programmer error.
+func (r *Registry) mustRegister(stepType types.StepType, executor StepExecutor) {
+	err := r.Register(stepType, executor)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Execute calls f(ctx, req).
+func (f StepExecutorFunc) Execute(ctx context.Context, req *StepRequest) (*StepOutcome, error) {
+	return f(ctx, req)
+}
@@ -0,0 +1,125 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const stepTypeShell types.StepType = "shell"
+
+var errShellFailed = errors.New("shell exited with status 1")
+
+func TestNewDefaultRegistry(t *testing.T) {
+	t.Parallel()
+
+	registry := engine.NewDefaultRegistry()
+
+	assert.True(t, registry.Supports(types.StepTypeEnd))
+	assert.True(t, registry.Supports(types.StepTypeCondition))
+	assert.False(t, registry.Supports(stepTypeShell))
+	assert.Equal(t, []types.StepType{types.StepTypeCondition, types.StepTypeEnd}, registry.StepTypes())
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	registry := engine.NewRegistry()
+	executor := engine.StepExecutorFunc(func(_ context.Context, _ *engine.StepRequest) (*engine.StepOutcome, error) {
+		return &engine.StepOutcome{}, nil
+	})
+
+	require.NoError(t, registry.Register(stepTypeShell, executor))
+	assert.True(t, registry.Supports(stepTypeShell))
+
+	err := registry.Register(stepTypeShell, executor)
+	require.ErrorIs(t, err, engine.ErrDuplicateStepType)
+
+	require.ErrorIs(t, registry.Register("", executor), engine.ErrInvalidExecutor)
+	require.ErrorIs(t, registry.Register("http", nil), engine.ErrInvalidExecutor)
+}
+
+func TestEngine_Run_CustomStepType(t *testing.T) {
+	t.Parallel()
+
+	registry := engine.NewDefaultRegistry()
+	require.NoError(t, registry.Register(stepTypeShell,
+		engine.StepExecutorFunc(func(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+			return &engine.StepOutcome{
+				Output:   map[string]any{"command": req.Step.Metadata["command"], "exitCode": 0},
+				Next:     req.Step.Next,
+				Metadata: map[string]any{"executor": "shell"},
+			}, nil
+		})))
+
+	flow := &types.FlowDefinition{
+		ID:          "custom",
+		Name:        "Custom",
+		InitialStep: "run",
+		Steps: map[string]types.Step{
+			"run":  {Type: stepTypeShell, Next: "done", Metadata: map[string]any{"command": "echo hi"}},
+			"done": {Type: types.StepTypeEnd},
+		},
+	}
+
+	require.NoError(t, flow.Validate(types.WithStepTypes(registry)))
+
+	execCtx, err := engine.NewEngine(registry).Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.StatusCompleted, execCtx.Status)
+	assert.Equal(t, map[string]any{"command": "echo hi", "exitCode": 0}, execCtx.StepResults["run"].Output)
+	assert.Equal(t, "shell", execCtx.StepResults["run"].Metadata["executor"])
+	assert.Contains(t, execCtx.StepResults, "done")
+}
+
+func TestEngine_Run_ExecutorError(t *testing.T) {
+	t.Parallel()
+
+	registry := engine.NewDefaultRegistry()
+	require.NoError(t, registry.Register(stepTypeShell,
+		engine.StepExecutorFunc(func(_ context.Context, _ *engine.StepRequest) (*engine.StepOutcome, error) {
+			return nil, errShellFailed
+		})))
+
+	flow := &types.FlowDefinition{
+		ID:          "custom",
+		Name:        "Custom",
+		InitialStep: "run",
+		Steps: map[string]types.Step{
+			"run": {Type: stepTypeShell},
+		},
+	}
+
+	execCtx, err := engine.NewEngine(registry).Run(context.Background(), flow, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+	assert.Equal(t, engine.CodeStepFailed, execCtx.Error.Code)
+	assert.Contains(t, execCtx.Error.Message, errShellFailed.Error())
+	assert.Equal(t, types.StepStatusFailed, execCtx.StepResults["run"].Status)
+}
+
+func TestEngine_Run_UnregisteredStepType(t *testing.T) {
+	t.Parallel()
+
+	flow := &types.FlowDefinition{
+		ID:          "custom",
+		Name:        "Custom",
+		InitialStep: "run",
+		Steps: map[string]types.Step{
+			"run": {Type: stepTypeShell},
+		},
+	}
+
+	execCtx, err := engine.NewEngine(engine.NewDefaultRegistry()).Run(context.Background(), flow, nil)
+	require.Error(t, err)
+	assert.Equal(t, engine.CodeUnsupportedStepType, execCtx.Error.Code)
+}
@@ -0,0 +1,180 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// CodeStepTimeout is reported when a step attempt exceeds its timeout.
+const CodeStepTimeout = "STEP_TIMEOUT"
+
+// DefaultRetryDelay is the delay of the flow-level retry policy.
+const DefaultRetryDelay = time.Second
+
+// jitterFraction is the largest share of a retry delay added as random jitter.
+const jitterFraction = 0.2
+
+// retryPolicy is the resolved retry behavior of one step.
+type retryPolicy struct {
+	maxAttempts int
+	delay       time.Duration
+	backoff     string
+	maxDelay    time.Duration
+}
+
+// SetDefaultTimeout sets the timeout of each attempt of steps that do not
+// set their own. Zero disables the default timeout.
+func (e *Engine) SetDefaultTimeout(timeout time.Duration) {
+	e.defaultTimeout = timeout
+}
+
+// SetDefaultRetries sets how many times steps without their own retry
+// configuration are retried after a recoverable error.
+func (e *Engine) SetDefaultRetries(maxRetries int) {
+	e.defaultRetry = &types.RetryConfig{
+		MaxAttempts: maxRetries + 1,
+		Delay:       DefaultRetryDelay,
+		Backoff:     types.BackoffExponential,
+		MaxDelay:    types.DefaultMaxRetryDelay,
+	}
+}
+
+// execute runs a step until it succeeds, fails with an error that is not
+// recoverable or runs out of attempts. It returns the number of attempts made
+// and the outcome of the last attempt with the usage of every attempt.
+func (e *Engine) execute(ctx context.Context, req *StepRequest) (*StepOutcome, int, error) {
+	policy := e.retryPolicy(req.Step)
+	timeout := e.stepTimeout(req.Step)
+
+	tokens, cost := 0, 0.0
+
+	for attempt := 1; ; attempt++ {
+		outcome, err := e.attempt(ctx, req, timeout)
+		if outcome != nil {
+			tokens += outcome.TokensUsed
+			cost += outcome.Cost
+		}
+
+		if err == nil || attempt >= policy.maxAttempts || !toExecutionError(err).Recoverable {
+			return withUsage(outcome, tokens, cost), attempt, err
+		}
+
+		delay := policy.wait(attempt)
+		logging.FromContext(ctx).WarnContext(ctx, "step attempt failed, retrying",
+			"attempt", attempt, "maxAttempts", policy.maxAttempts, "delay", delay, "error", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return withUsage(nil, tokens, cost), attempt, newExecutionError(CodeCanceled, ctx.Err().Error(), nil)
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attempt runs a step once, bounded by timeout when it is positive.
+func (e *Engine) attempt(ctx context.Context, req *StepRequest, timeout time.Duration) (*StepOutcome, error) {
+	if timeout <= 0 {
+		return e.dispatch(ctx, req)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	outcome, err := e.dispatch(attemptCtx, req)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return outcome, &types.ExecutionError{
+			Code:        CodeStepTimeout,
+			Message:     fmt.Sprintf("step %s timed out after %v", req.StepID, timeout),
+			Details:     map[string]any{"timeout": timeout.String()},
+			Recoverable: true,
+			Timestamp:   time.Now(),
+			StackTrace:  "",
+		}
+	}
+
+	return outcome, err
+}
+
+// withUsage returns outcome with the usage of all attempts of its step.
+func withUsage(outcome *StepOutcome, tokens int, cost float64) *StepOutcome {
+	if outcome == nil {
+		if tokens == 0 && cost == 0 {
+			return nil
+		}
+
+		outcome = &StepOutcome{Output: nil, Next: "", TokensUsed: 0, Cost: 0, Metadata: nil, Paused: false}
+	}
+
+	total := *outcome
+	total.TokensUsed = tokens
+	total.Cost = cost
+
+	return &total
+}
+
+// stepTimeout returns the step's own timeout or the engine default.
+func (e *Engine) stepTimeout(step types.Step) time.Duration {
+	if step.Timeout != nil {
+		return *step.Timeout
+	}
+
+	return e.defaultTimeout
+}
+
+// retryPolicy returns the step's own retry configuration or the engine default.
+func (e *Engine) retryPolicy(step types.Step) retryPolicy {
+	retry := step.Retry
+	if retry == nil {
+		retry = e.defaultRetry
+	}
+
+	if retry == nil {
+		return retryPolicy{maxAttempts: 1, delay: 0, backoff: types.BackoffFixed, maxDelay: 0}
+	}
+
+	maxDelay := retry.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = types.DefaultMaxRetryDelay
+	}
+
+	return retryPolicy{
+		maxAttempts: max(retry.MaxAttempts, 1),
+		delay:       retry.Delay,
+		backoff:     retry.Backoff,
+		maxDelay:    maxDelay,
+	}
+}
+
+// wait returns the pause after the given failed attempt, at most maxDelay
+// before jitter is added.
+func (p retryPolicy) wait(attempt int) time.Duration {
+	delay := min(p.delay, p.maxDelay)
+	if delay <= 0 {
+		return 0
+	}
+
+	switch p.backoff {
+	case types.BackoffLinear:
+		if delay > p.maxDelay/time.Duration(attempt) {
+			delay = p.maxDelay
+		} else {
+			delay *= time.Duration(attempt)
+		}
+	case types.BackoffExponential:
+		// Shifting the cap right instead of the delay left cannot overflow.
+		if delay > p.maxDelay>>(attempt-1) {
+			delay = p.maxDelay
+		} else {
+			delay <<= attempt - 1
+		}
+	}
+
+	//nolint:gosec // jitter does not need a cryptographic random source
+	return delay + time.Duration(rand.Float64()*jitterFraction*float64(delay))
+}
@@ -0,0 +1,184 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// flakyExecutor fails with err until it has been called failures times.
+// Every call, failed or not, spends tokens.
+type flakyExecutor struct {
+	failures int
+	err      *types.ExecutionError
+	tokens   int
+	calls    int
+}
+
+func (f *flakyExecutor) Execute(_ context.Context, req *engine.StepRequest) (*engine.StepOutcome, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return &engine.StepOutcome{TokensUsed: f.tokens}, f.err
+	}
+
+	return &engine.StepOutcome{Output: "ok", Next: req.Step.Next, TokensUsed: f.tokens}, nil
+}
+
+// newShellFlow returns a flow that runs step as its only shell step.
+func newShellFlow(step types.Step) *types.FlowDefinition {
+	step.Type = stepTypeShell
+
+	return newTestFlow("retrying", "run", map[string]types.Step{"run": step})
+}
+
+func TestEngine_Run_RetriesRecoverableErrors(t *testing.T) {
+	t.Parallel()
+
+	executor := &flakyExecutor{failures: 2, err: &types.ExecutionError{Code: "RATE_LIMITED", Recoverable: true}}
+
+	flow := newShellFlow(types.Step{
+		Retry: &types.RetryConfig{MaxAttempts: 3, Delay: time.Millisecond, Backoff: types.BackoffExponential},
+	})
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: executor}).Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, executor.calls)
+	assert.Equal(t, "ok", execCtx.StepResults["run"].Output)
+	assert.Equal(t, 3, execCtx.StepResults["run"].Metadata["attempts"])
+}
+
+func TestEngine_Run_RetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	executor := &flakyExecutor{failures: 5, err: &types.ExecutionError{Code: "RATE_LIMITED", Recoverable: true}}
+
+	flow := newShellFlow(types.Step{
+		Retry: &types.RetryConfig{MaxAttempts: 2, Delay: time.Millisecond, Backoff: types.BackoffLinear},
+	})
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: executor}).Run(context.Background(), flow, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, 2, executor.calls)
+	assert.Equal(t, "RATE_LIMITED", execCtx.Error.Code)
+	assert.Equal(t, 2, execCtx.StepResults["run"].Metadata["attempts"])
+}
+
+func TestEngine_Run_CapsRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	executor := &flakyExecutor{failures: 99, err: &types.ExecutionError{Code: "RATE_LIMITED", Recoverable: true}}
+
+	start := time.Now()
+	flow := newShellFlow(types.Step{
+		Retry: &types.RetryConfig{
+			MaxAttempts: 100,
+			Delay:       time.Millisecond,
+			Backoff:     types.BackoffExponential,
+			MaxDelay:    2 * time.Millisecond,
+		},
+	})
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: executor}).Run(context.Background(), flow, nil)
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+
+	assert.Equal(t, 100, execCtx.StepResults["run"].Metadata["attempts"])
+	assert.Less(t, elapsed, 5*time.Second, "the delay stops doubling at MaxDelay")
+	assert.GreaterOrEqual(t, elapsed, 150*time.Millisecond, "late retries still wait MaxDelay")
+}
+
+func TestEngine_Run_DoesNotRetryUnrecoverableErrors(t *testing.T) {
+	t.Parallel()
+
+	executor := &flakyExecutor{failures: 1, err: &types.ExecutionError{Code: "INVALID_KEY", Recoverable: false}}
+
+	flow := newShellFlow(types.Step{
+		Retry: &types.RetryConfig{MaxAttempts: 3, Delay: time.Millisecond},
+	})
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: executor}).Run(context.Background(), flow, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, 1, executor.calls)
+	assert.Equal(t, 1, execCtx.StepResults["run"].Metadata["attempts"])
+}
+
+func TestEngine_Run_CanceledDuringBackoff(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	failing := engine.StepExecutorFunc(func(context.Context, *engine.StepRequest) (*engine.StepOutcome, error) {
+		calls++
+		cancel()
+
+		return nil, &types.ExecutionError{Code: "RATE_LIMITED", Recoverable: true}
+	})
+
+	flow := newShellFlow(types.Step{Retry: &types.RetryConfig{MaxAttempts: 3, Delay: time.Hour}})
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: failing}).Run(ctx, flow, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, 1, calls, "the backoff ends when the run is canceled")
+	assert.Equal(t, types.StatusCanceled, execCtx.Status)
+	assert.Equal(t, engine.CodeCanceled, execCtx.Error.Code)
+	assert.Equal(t, types.StepStatusCanceled, execCtx.StepResults["run"].Status)
+}
+
+func TestEngine_Run_StepTimeout(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	blocking := engine.StepExecutorFunc(func(ctx context.Context, _ *engine.StepRequest) (*engine.StepOutcome, error) {
+		calls++
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	})
+
+	timeout := 10 * time.Millisecond
+
+	flow := newShellFlow(types.Step{
+		Timeout: &timeout,
+		Retry:   &types.RetryConfig{MaxAttempts: 2, Delay: time.Millisecond},
+	})
+
+	execCtx, err := newTestEngine(t, stepExecutors{stepTypeShell: blocking}).Run(context.Background(), flow, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, 2, calls, "timeouts are recoverable")
+	assert.Equal(t, engine.CodeStepTimeout, execCtx.Error.Code)
+	assert.Equal(t, "run", execCtx.Error.Details.(map[string]any)["stepId"])
+}
+
+func TestEngine_Run_DefaultTimeout(t *testing.T) {
+	t.Parallel()
+
+	blocking := engine.StepExecutorFunc(func(ctx context.Context, _ *engine.StepRequest) (*engine.StepOutcome, error) {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	})
+
+	flowEngine := newTestEngine(t, stepExecutors{stepTypeShell: blocking})
+	flowEngine.SetDefaultTimeout(10 * time.Millisecond)
+	flowEngine.SetDefaultRetries(0)
+
+	execCtx, err := flowEngine.Run(context.Background(), newShellFlow(types.Step{}), nil)
+	require.Error(t, err)
+
+	assert.Equal(t, engine.CodeStepTimeout, execCtx.Error.Code)
+	assert.Equal(t, 1, execCtx.StepResults["run"].Metadata["attempts"])
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"encoding/json"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/expr"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// NewScope builds the values that expressions can reference during a run:
+// vars holds the runtime variables, steps the results of executed steps and
+// flow the identity of the running flow.
+func NewScope(flow *types.FlowDefinition, execCtx *types.ExecutionContext) map[string]any {
+	steps := make(map[string]any, len(execCtx.StepResults))
+	for stepID, result := range execCtx.StepResults {
+		steps[stepID] = map[string]any{
+			"output":     normalize(result.Output),
+			"status":     string(result.Status),
+			"error":      normalize(result.Error),
+			"tokensUsed": result.TokensUsed,
+			"cost":       result.Cost,
+			"duration":   result.Duration.Seconds(),
+			"metadata":   normalize(result.Metadata),
+		}
+	}
+
+	return map[string]any{
+		expr.RootVars:  normalize(execCtx.Variables),
+		expr.RootSteps: steps,
+		expr.RootFlow: map[string]any{
+			"id":      flow.ID,
+			"name":    flow.Name,
+			"version": flow.Version,
+		},
+	}
+}
+
+// normalize converts a value to the generic JSON shapes (maps, slices,
+// float64 numbers) that expressions operate on.
+func normalize(value any) any {
+	if value == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var generic any
+
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return value
+	}
+
+	return generic
+}
@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// CodeTemplateError is reported when a prompt template cannot be parsed or rendered.
+const CodeTemplateError = "TEMPLATE_ERROR"
+
+// templateContextKey is the scope key holding the rendered PromptConfig.Context.
+const templateContextKey = "context"
+
+// RenderedPrompt is the result of rendering a step's PromptConfig.
+type RenderedPrompt struct {
+	System  string
+	User    string
+	Context map[string]any
+}
+
+// templateFuncs returns the helper functions available to prompt templates.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"json": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode value as JSON: %w", err)
+			}
+
+			return string(data), nil
+		},
+	}
+}
+
+// RenderPrompt renders the prompt of step against the run scope.
+//
+// Templates see the same roots as expressions (vars, steps, flow) plus
+// context, the step's PromptConfig.Context whose string values are rendered
+// first. In strict mode a reference to a missing key fails the render
+// instead of producing "<no value>".
+func RenderPrompt(
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	stepID string,
+	step types.Step,
+	strict bool,
+) (*RenderedPrompt, error) {
+	if step.Prompt == nil {
+		return nil, newExecutionError(CodeInvalidPrompt, "prompt step has no prompt configuration", nil)
+	}
+
+	scope := NewScope(flow, execCtx)
+
+	promptContext, err := renderContextValue(stepID+".context", step.Prompt.Context, scope, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	contextMap, _ := promptContext.(map[string]any)
+	scope[templateContextKey] = contextMap
+
+	system, err := RenderTemplate(stepID+".system", step.Prompt.System, scope, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := RenderTemplate(stepID+".template", step.Prompt.Template, scope, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RenderedPrompt{
+		System:  system,
+		User:    user,
+		Context: contextMap,
+	}, nil
+}
+
+// RenderTemplate renders a single text/template against data.
+func RenderTemplate(name, text string, data map[string]any, strict bool) (string, error) {
+	tmpl := template.New(name).Funcs(templateFuncs())
+	if strict {
+		tmpl = tmpl.Option("missingkey=error")
+	}
+
+	tmpl, err := tmpl.Parse(text)
+	if err != nil {
+		return "", newExecutionError(CodeTemplateError, fmt.Sprintf("failed to parse %s: %v", name, err),
+			map[string]any{"template": name})
+	}
+
+	var builder strings.Builder
+
+	err = tmpl.Execute(&builder, data)
+	if err != nil {
+		return "", newExecutionError(CodeTemplateError, fmt.Sprintf("failed to render %s: %v", name, err),
+			map[string]any{"template": name})
+	}
+
+	return builder.String(), nil
+}
+
+// renderContextValue renders every string found in a PromptConfig.Context or
+// ToolConfig.Arguments value.
+func renderContextValue(name string, value any, scope map[string]any, strict bool) (any, error) {
+	switch typed := value.(type) {
+	case string:
+		return RenderTemplate(name, typed, scope, strict)
+	case map[string]any:
+		rendered := make(map[string]any, len(typed))
+
+		for key, item := range typed {
+			out, err := renderContextValue(name+"."+key, item, scope, strict)
+			if err != nil {
+				return nil, err
+			}
+
+			rendered[key] = out
+		}
+
+		return rendered, nil
+	case []any:
+		rendered := make([]any, len(typed))
+
+		for i, item := range typed {
+			out, err := renderContextValue(fmt.Sprintf("%s[%d]", name, i), item, scope, strict)
+			if err != nil {
+				return nil, err
+			}
+
+			rendered[i] = out
+		}
+
+		return rendered, nil
+	default:
+		return value, nil
+	}
+}
+
+// Messages returns the chat messages for the rendered prompt.
+// The system message is omitted when it is empty.
+func (r *RenderedPrompt) Messages() []llm.Message {
+	var messages []llm.Message
+
+	if r.System != "" {
+		messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: r.System, ToolCalls: nil, ToolCallID: ""})
+	}
+
+	return append(messages, llm.Message{Role: llm.RoleUser, Content: r.User, ToolCalls: nil, ToolCallID: ""})
+}
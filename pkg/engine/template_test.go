@@ -0,0 +1,76 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	t.Parallel()
+
+	flow := newPromptFlow("")
+	step := flow.Steps["ask"]
+	step.Prompt = &types.PromptConfig{
+		System:   "Repository: {{.vars.repo}}",
+		Template: "Labels for {{.context.issue}}: {{json .steps.fetch.output.labels}}",
+		Context:  map[string]any{"issue": "#{{.steps.fetch.output.number}}"},
+	}
+
+	execCtx := engine.NewExecutionContext(flow, map[string]any{"repo": "override"})
+	execCtx.StepResults["fetch"] = types.StepResult{
+		StepID: "fetch",
+		Status: types.StepStatusCompleted,
+		Output: map[string]any{"number": 42, "labels": []string{"bug", "ui"}},
+	}
+
+	rendered, err := engine.RenderPrompt(flow, execCtx, "ask", step, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Repository: override", rendered.System)
+	assert.Equal(t, `Labels for #42: ["bug","ui"]`, rendered.User)
+	assert.Equal(t, map[string]any{"issue": "#42"}, rendered.Context)
+	assert.Equal(t, []llm.Message{
+		{Role: llm.RoleSystem, Content: "Repository: override"},
+		{Role: llm.RoleUser, Content: `Labels for #42: ["bug","ui"]`},
+	}, rendered.Messages())
+}
+
+func TestRenderPrompt_StrictMissingKey(t *testing.T) {
+	t.Parallel()
+
+	flow := newPromptFlow("")
+	step := flow.Steps["ask"]
+	step.Prompt = &types.PromptConfig{Template: "Hello {{.vars.missing}}"}
+
+	execCtx := engine.NewExecutionContext(flow, nil)
+
+	_, err := engine.RenderPrompt(flow, execCtx, "ask", step, true)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeTemplateError, execErr.Code)
+	assert.Contains(t, execErr.Message, "missing")
+
+	rendered, err := engine.RenderPrompt(flow, execCtx, "ask", step, false)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello <no value>", rendered.User)
+	assert.Len(t, rendered.Messages(), 1)
+}
+
+func TestRenderTemplate_ParseError(t *testing.T) {
+	t.Parallel()
+
+	_, err := engine.RenderTemplate("broken", "{{.vars.repo", nil, true)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, engine.CodeTemplateError, execErr.Code)
+	assert.Equal(t, map[string]any{"template": "broken"}, execErr.Details)
+}
@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Error codes reported by tool steps.
+const (
+	CodeInvalidTool     = "INVALID_TOOL"
+	CodeToolUnavailable = "TOOL_UNAVAILABLE"
+)
+
+const callIDBytes = 6
+
+// ToolCaller calls a tool on behalf of a step. The embedded tool registry
+// and the MCP server pool both implement it.
+type ToolCaller interface {
+	CallTool(ctx context.Context, call types.MCPToolCall) (*types.MCPToolResult, error)
+}
+
+// ToolCatalog is a ToolCaller that can also list the tools it offers.
+// The embedded registry ignores server; the MCP pool lists that server's tools.
+type ToolCatalog interface {
+	ToolCaller
+	ListTools(ctx context.Context, server string) ([]types.MCPTool, error)
+}
+
+// ToolSettings configures how tool steps render their arguments.
+type ToolSettings struct {
+	// StrictTemplates fails argument rendering on references to missing keys.
+	StrictTemplates bool
+}
+
+// ToolExecutor calls the tool named by a tool step directly, without the
+// language model, and stores the tool result as the step output.
+type ToolExecutor struct {
+	embedded ToolCaller
+	servers  ToolCaller
+	settings ToolSettings
+}
+
+// NewToolExecutor creates a tool step executor. Steps without an MCPServer
+// call embedded tools; the others call tools on servers. Either caller may
+// be nil when that kind of tool is not available.
+func NewToolExecutor(embedded, servers ToolCaller, settings ToolSettings) *ToolExecutor {
+	return &ToolExecutor{
+		embedded: embedded,
+		servers:  servers,
+		settings: settings,
+	}
+}
+
+// Execute renders the tool arguments, calls the tool and records the call
+// and its result in the step metadata.
+func (t *ToolExecutor) Execute(ctx context.Context, req *StepRequest) (*StepOutcome, error) {
+	config := req.Step.Tool
+	if config == nil || config.Name == "" {
+		return nil, newExecutionError(CodeInvalidTool, "tool step has no tool name", nil)
+	}
+
+	arguments, err := RenderArguments(req.Flow, req.Execution, req.StepID, config.Arguments, t.settings.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	caller, server := t.embedded, tools.EmbeddedServer
+	if req.Step.MCPServer != "" {
+		caller, server = t.servers, req.Step.MCPServer
+	}
+
+	if caller == nil {
+		return nil, newExecutionError(CodeToolUnavailable,
+			fmt.Sprintf("no %s tools are available for tool %s", server, config.Name),
+			map[string]any{"tool": config.Name, "server": server})
+	}
+
+	call := types.MCPToolCall{
+		ID:         newCallID(req.StepID),
+		ToolName:   config.Name,
+		ServerName: server,
+		Arguments:  arguments,
+		Timestamp:  time.Now(),
+		Metadata:   nil,
+	}
+
+	logger := logging.FromContext(ctx)
+	logger.DebugContext(ctx, "calling tool", "tool", config.Name, "server", server, "arguments", arguments)
+
+	spanCtx, span := startToolSpan(ctx, call)
+	result, err := caller.CallTool(spanCtx, call)
+	endToolSpan(span, result, err)
+
+	if err != nil {
+		return failedToolOutcome(map[string]any{"toolCall": call}),
+			fmt.Errorf("tool %s on %s failed: %w", config.Name, server, err)
+	}
+
+	logger.DebugContext(ctx, "tool returned", "tool", config.Name, "server", server, "success", result.Success)
+
+	metadata := map[string]any{
+		"toolCall":   call,
+		"toolResult": result,
+	}
+
+	if !result.Success {
+		if result.Error != nil {
+			return failedToolOutcome(metadata), result.Error
+		}
+
+		return failedToolOutcome(metadata), newExecutionError(CodeStepFailed,
+			fmt.Sprintf("tool %s on %s reported an error", config.Name, server),
+			map[string]any{"tool": config.Name, "server": server})
+	}
+
+	return &StepOutcome{
+		Output:     result.Result,
+		Next:       req.Step.Next,
+		TokensUsed: 0,
+		Cost:       0,
+		Metadata:   metadata,
+		Paused:     false,
+	}, nil
+}
+
+// failedToolOutcome returns the partial outcome of a tool step whose call
+// failed, which keeps the call and, when there is one, its result.
+func failedToolOutcome(metadata map[string]any) *StepOutcome {
+	return &StepOutcome{
+		Output:     nil,
+		Next:       "",
+		TokensUsed: 0,
+		Cost:       0,
+		Metadata:   metadata,
+		Paused:     false,
+	}
+}
+
+// RenderArguments renders every string in a tool step's arguments against
+// the run scope. Non-string values are passed through unchanged.
+func RenderArguments(
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	stepID string,
+	arguments map[string]any,
+	strict bool,
+) (map[string]any, error) {
+	if arguments == nil {
+		return map[string]any{}, nil
+	}
+
+	rendered, err := renderContextValue(stepID+".arguments", arguments, NewScope(flow, execCtx), strict)
+	if err != nil {
+		return nil, err
+	}
+
+	renderedMap, _ := rendered.(map[string]any)
+
+	return renderedMap, nil
+}
+
+// newCallID returns a unique identifier for a tool call made by stepID.
+func newCallID(stepID string) string {
+	buf := make([]byte, callIDBytes)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return fmt.Sprintf("%s-%d", stepID, time.Now().UnixNano())
+	}
+
+	return stepID + "-" + hex.EncodeToString(buf)
+}
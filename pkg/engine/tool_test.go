@@ -0,0 +1,158 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// fakeCaller records tool calls and answers with a fixed result.
+type fakeCaller struct {
+	calls  []types.MCPToolCall
+	result *types.MCPToolResult
+	err    error
+}
+
+func (f *fakeCaller) CallTool(_ context.Context, call types.MCPToolCall) (*types.MCPToolResult, error) {
+	f.calls = append(f.calls, call)
+
+	return f.result, f.err
+}
+
+func newToolFlow(server string) *types.FlowDefinition {
+	flow := newTestFlow("tooling", "lookup", map[string]types.Step{
+		"lookup": {
+			Type:      types.StepTypeTool,
+			MCPServer: server,
+			Tool: &types.ToolConfig{
+				Name:      "search",
+				Arguments: map[string]any{"query": "repo:{{.vars.repo}}", "limit": 5},
+			},
+			Next: "done",
+		},
+		"done": {Type: types.StepTypeEnd},
+	})
+	flow.Variables = map[string]string{"repo": "flow-test-go"}
+
+	return flow
+}
+
+// toolExecutors runs tool steps against embedded and servers with strict templates.
+func toolExecutors(embedded, servers engine.ToolCaller) stepExecutors {
+	return stepExecutors{
+		types.StepTypeTool: engine.NewToolExecutor(embedded, servers, engine.ToolSettings{StrictTemplates: true}),
+	}
+}
+
+func TestToolExecutor_EmbeddedTool(t *testing.T) {
+	t.Parallel()
+
+	embedded := tools.NewRegistry()
+	require.NoError(t, embedded.Register(tools.Func{
+		Name: "search",
+		Fn: func(_ context.Context, arguments map[string]any) (any, error) {
+			return []any{arguments["query"], arguments["limit"]}, nil
+		},
+	}))
+
+	execCtx, err := newTestEngine(t, toolExecutors(embedded, nil)).Run(context.Background(), newToolFlow(""), nil)
+	require.NoError(t, err)
+
+	result := execCtx.StepResults["lookup"]
+	assert.Equal(t, []any{"repo:flow-test-go", 5}, result.Output)
+	assert.Zero(t, result.TokensUsed)
+
+	call, ok := result.Metadata["toolCall"].(types.MCPToolCall)
+	require.True(t, ok)
+	assert.Equal(t, tools.EmbeddedServer, call.ServerName)
+	assert.NotEmpty(t, call.ID)
+	assert.Contains(t, execCtx.StepResults, "done")
+}
+
+func TestToolExecutor_MCPServerTool(t *testing.T) {
+	t.Parallel()
+
+	servers := &fakeCaller{result: &types.MCPToolResult{Success: true, Result: "3 results"}}
+
+	execCtx, err := newTestEngine(t, toolExecutors(nil, servers)).Run(context.Background(), newToolFlow("github"), nil)
+	require.NoError(t, err)
+
+	require.Len(t, servers.calls, 1)
+	assert.Equal(t, "github", servers.calls[0].ServerName)
+	assert.Equal(t, "search", servers.calls[0].ToolName)
+	assert.Equal(t, map[string]any{"query": "repo:flow-test-go", "limit": 5}, servers.calls[0].Arguments)
+	assert.Equal(t, "3 results", execCtx.StepResults["lookup"].Output)
+}
+
+func TestToolExecutor_ToolError(t *testing.T) {
+	t.Parallel()
+
+	servers := &fakeCaller{result: &types.MCPToolResult{
+		Success: false,
+		Error:   &types.ExecutionError{Code: "MCP_TOOL_FAILED", Message: "not allowed"},
+	}}
+
+	execCtx, err := newTestEngine(t, toolExecutors(nil, servers)).Run(context.Background(), newToolFlow("github"), nil)
+	require.Error(t, err)
+
+	assert.Equal(t, types.StatusFailed, execCtx.Status)
+	assert.Equal(t, "MCP_TOOL_FAILED", execCtx.Error.Code)
+
+	result := execCtx.StepResults["lookup"]
+	assert.Equal(t, types.StepStatusFailed, result.Status)
+
+	call, ok := result.Metadata["toolCall"].(types.MCPToolCall)
+	require.True(t, ok, "a failed step keeps its call")
+	assert.Equal(t, "github", call.ServerName)
+	assert.Equal(t, map[string]any{"query": "repo:flow-test-go", "limit": 5}, call.Arguments)
+	assert.Equal(t, servers.result, result.Metadata["toolResult"])
+}
+
+func TestToolExecutor_CallerError(t *testing.T) {
+	t.Parallel()
+
+	servers := &fakeCaller{err: errServerGone}
+
+	execCtx, err := newTestEngine(t, toolExecutors(nil, servers)).Run(context.Background(), newToolFlow("github"), nil)
+	require.Error(t, err)
+
+	result := execCtx.StepResults["lookup"]
+	assert.Equal(t, types.StepStatusFailed, result.Status)
+	assert.Contains(t, result.Error.Message, errServerGone.Error())
+
+	call, ok := result.Metadata["toolCall"].(types.MCPToolCall)
+	require.True(t, ok, "a failed step keeps its call")
+	assert.Equal(t, "search", call.ToolName)
+	assert.Equal(t, map[string]any{"query": "repo:flow-test-go", "limit": 5}, call.Arguments)
+	assert.NotContains(t, result.Metadata, "toolResult")
+}
+
+func TestToolExecutor_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	execCtx, err := newTestEngine(t, toolExecutors(tools.NewRegistry(), nil)).
+		Run(context.Background(), newToolFlow("github"), nil)
+	require.Error(t, err)
+	assert.Equal(t, engine.CodeToolUnavailable, execCtx.Error.Code)
+}
+
+func TestToolExecutor_StrictArguments(t *testing.T) {
+	t.Parallel()
+
+	flow := newToolFlow("github")
+	flow.Steps["lookup"].Tool.Arguments["query"] = "{{.vars.missing}}"
+
+	servers := &fakeCaller{result: &types.MCPToolResult{Success: true}}
+
+	execCtx, err := newTestEngine(t, toolExecutors(nil, servers)).Run(context.Background(), flow, nil)
+	require.Error(t, err)
+	assert.Equal(t, engine.CodeTemplateError, execCtx.Error.Code)
+	assert.Empty(t, servers.calls)
+}
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/telemetry"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// SetTracer sets the tracer of run and step spans. Executors start their
+// spans, such as LLM requests and tool calls, as children of the step span.
+func (e *Engine) SetTracer(tracer trace.Tracer) {
+	e.tracer = tracer
+}
+
+// startRunSpan starts the span of a run or of the part of it a resume continues.
+func (e *Engine) startRunSpan(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+) context.Context {
+	ctx, _ = e.tracer.Start(ctx, "flow "+flow.ID, trace.WithAttributes(
+		telemetry.FlowIDKey.String(flow.ID),
+		telemetry.SessionIDKey.String(execCtx.SessionID),
+	))
+
+	return ctx
+}
+
+// endRunSpan ends the run span of ctx with the final status, tokens and cost of the run.
+func endRunSpan(ctx context.Context, execCtx *types.ExecutionContext) {
+	span := trace.SpanFromContext(ctx)
+
+	tokens, cost := 0, 0.0
+	for _, result := range execCtx.StepResults {
+		tokens += result.TokensUsed
+		cost += result.Cost
+	}
+
+	span.SetAttributes(
+		telemetry.RunStatusKey.String(string(execCtx.Status)),
+		telemetry.StepsKey.Int(len(execCtx.StepResults)),
+		telemetry.TokensUsedKey.Int(tokens),
+		telemetry.CostKey.Float64(cost),
+	)
+
+	if execCtx.Error != nil {
+		span.SetAttributes(telemetry.ErrorCodeKey.String(execCtx.Error.Code))
+		span.SetStatus(codes.Error, execCtx.Error.Message)
+	}
+
+	span.End(trace.WithTimestamp(execCtx.LastUpdate))
+}
+
+// startStepSpan starts the span of a step at start, the start time of its result.
+func (e *Engine) startStepSpan(
+	ctx context.Context,
+	flow *types.FlowDefinition,
+	execCtx *types.ExecutionContext,
+	stepID string,
+	step types.Step,
+	start time.Time,
+) (context.Context, trace.Span) {
+	return e.tracer.Start(ctx, "step "+stepID, trace.WithTimestamp(start), trace.WithAttributes(
+		telemetry.FlowIDKey.String(flow.ID),
+		telemetry.SessionIDKey.String(execCtx.SessionID),
+		telemetry.StepIDKey.String(stepID),
+		telemetry.StepTypeKey.String(string(step.Type)),
+	))
+}
+
+// endStepSpan ends a step span at the end time of its result, so span and
+// result timings agree.
+func endStepSpan(span trace.Span, result types.StepResult, attempts int) {
+	span.SetAttributes(
+		telemetry.StepStatusKey.String(string(result.Status)),
+		telemetry.AttemptsKey.Int(attempts),
+		telemetry.TokensUsedKey.Int(result.TokensUsed),
+		telemetry.CostKey.Float64(result.Cost),
+	)
+
+	if result.Error != nil {
+		span.SetAttributes(telemetry.ErrorCodeKey.String(result.Error.Code))
+		span.SetStatus(codes.Error, result.Error.Message)
+	}
+
+	span.End(trace.WithTimestamp(result.EndTime))
+}
+
+// startChildSpan starts a client span under the span of ctx, using the
+// tracer provider of that span.
+func startChildSpan(
+	ctx context.Context,
+	name string,
+	attributes ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(telemetry.TracerName)
+
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+// startCompletionSpan starts the span of one LLM request of a prompt step.
+func startCompletionSpan(ctx context.Context, model string, iteration int) (context.Context, trace.Span) {
+	return startChildSpan(ctx, "chat "+model,
+		telemetry.GenAIOperationKey.String("chat"),
+		telemetry.GenAIRequestModelKey.String(model),
+		telemetry.IterationKey.Int(iteration),
+	)
+}
+
+// endCompletionSpan ends an LLM request span with the usage and cost of the response.
+func endCompletionSpan(span trace.Span, resp *llm.Response, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+
+		return
+	}
+
+	span.SetAttributes(
+		telemetry.GenAIResponseModelKey.String(resp.Model),
+		telemetry.GenAIInputTokensKey.Int(resp.Usage.PromptTokens),
+		telemetry.GenAIOutputTokensKey.Int(resp.Usage.CompletionTokens),
+		telemetry.GenAIFinishReasonKey.StringSlice([]string{resp.FinishReason}),
+		telemetry.TokensUsedKey.Int(resp.Usage.TotalTokens),
+		telemetry.CostKey.Float64(resp.Cost),
+	)
+	span.End()
+}
+
+// startToolSpan starts the span of a tool call.
+func startToolSpan(ctx context.Context, call types.MCPToolCall) (context.Context, trace.Span) {
+	return startChildSpan(ctx, "tools/call "+call.ToolName,
+		telemetry.ToolNameKey.String(call.ToolName),
+		telemetry.ToolServerKey.String(call.ServerName),
+		telemetry.ToolCallIDKey.String(call.ID),
+	)
+}
+
+// endToolSpan ends a tool call span with the outcome of the call.
+func endToolSpan(span trace.Span, result *types.MCPToolResult, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case !result.Success:
+		span.SetAttributes(telemetry.ToolSuccessKey.Bool(false))
+
+		if result.Error != nil {
+			span.SetAttributes(telemetry.ErrorCodeKey.String(result.Error.Code))
+			span.SetStatus(codes.Error, result.Error.Message)
+		}
+	default:
+		span.SetAttributes(telemetry.ToolSuccessKey.Bool(true))
+	}
+
+	span.End()
+}
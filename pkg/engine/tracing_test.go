@@ -0,0 +1,147 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/engine"
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/telemetry"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// newTracedEngine creates an engine recording its spans in the returned recorder.
+func newTracedEngine(registry *engine.Registry) (*engine.Engine, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	flowEngine := engine.NewEngine(registry)
+	flowEngine.SetTracer(provider.Tracer(telemetry.TracerName))
+
+	return flowEngine, recorder
+}
+
+// spansByName indexes ended spans by name.
+func spansByName(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	return spans
+}
+
+// spanAttributes returns the attributes of span as a map.
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+
+	return attributes
+}
+
+func TestEngine_Run_TracesStepsLLMRequestsAndToolCalls(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{responses: []*llm.Response{
+		toolCallResponse(functionCall("call-1", "add", `{"a": 2, "b": 3}`)),
+		{Content: "The sum is 5.", Model: "openai/gpt-4o", FinishReason: "stop",
+			Usage: llm.Usage{PromptTokens: 15, CompletionTokens: 5, TotalTokens: 20}, Cost: 0.002},
+	}}
+
+	flow := newPromptFlow("openai/gpt-4o")
+	step := flow.Steps["ask"]
+	step.Tools = []string{"add"}
+	flow.Steps["ask"] = step
+
+	executor := engine.NewPromptExecutor(provider, engine.PromptSettings{})
+	executor.SetTools(newAgentTools(t), nil)
+
+	flowEngine, recorder := newTracedEngine(newTestRegistry(t, stepExecutors{types.StepTypePrompt: executor}))
+
+	execCtx, err := flowEngine.Run(context.Background(), flow, nil)
+	require.NoError(t, err)
+
+	spans := spansByName(recorder)
+	require.Len(t, recorder.Ended(), 6, "run, two steps, two LLM requests and a tool call")
+
+	run := spans["flow prompting"]
+	require.NotNil(t, run)
+	assert.False(t, run.Parent().IsValid())
+	assert.Equal(t, execCtx.SessionID, spanAttributes(run)[telemetry.SessionIDKey].AsString())
+	assert.Equal(t, "completed", spanAttributes(run)[telemetry.RunStatusKey].AsString())
+	assert.Equal(t, int64(32), spanAttributes(run)[telemetry.TokensUsedKey].AsInt64())
+
+	ask := spans["step ask"]
+	require.NotNil(t, ask)
+	assert.Equal(t, run.SpanContext().SpanID(), ask.Parent().SpanID())
+	assert.Equal(t, execCtx.StepResults["ask"].StartTime, ask.StartTime())
+	assert.Equal(t, execCtx.StepResults["ask"].EndTime, ask.EndTime())
+
+	attributes := spanAttributes(ask)
+	assert.Equal(t, "prompt", attributes[telemetry.StepTypeKey].AsString())
+	assert.Equal(t, "completed", attributes[telemetry.StepStatusKey].AsString())
+	assert.Equal(t, int64(32), attributes[telemetry.TokensUsedKey].AsInt64())
+	assert.InDelta(t, 0.003, attributes[telemetry.CostKey].AsFloat64(), 1e-9)
+
+	done := spans["step done"]
+	require.NotNil(t, done)
+	assert.Equal(t, execCtx.StepResults["done"].EndTime, done.EndTime())
+
+	var completions []sdktrace.ReadOnlySpan
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == "chat openai/gpt-4o" {
+			completions = append(completions, span)
+		}
+	}
+
+	require.Len(t, completions, 2)
+
+	for _, completion := range completions {
+		assert.Equal(t, ask.SpanContext().SpanID(), completion.Parent().SpanID())
+	}
+
+	attributes = spanAttributes(completions[1])
+	assert.Equal(t, "openai/gpt-4o", attributes[telemetry.GenAIResponseModelKey].AsString())
+	assert.Equal(t, int64(15), attributes[telemetry.GenAIInputTokensKey].AsInt64())
+	assert.Equal(t, int64(5), attributes[telemetry.GenAIOutputTokensKey].AsInt64())
+	assert.Equal(t, []string{"stop"}, attributes[telemetry.GenAIFinishReasonKey].AsStringSlice())
+
+	call := spans["tools/call add"]
+	require.NotNil(t, call)
+	assert.Equal(t, ask.SpanContext().SpanID(), call.Parent().SpanID())
+	assert.Equal(t, "call-1", spanAttributes(call)[telemetry.ToolCallIDKey].AsString())
+	assert.True(t, spanAttributes(call)[telemetry.ToolSuccessKey].AsBool())
+}
+
+func TestEngine_Run_TracesFailedStep(t *testing.T) {
+	t.Parallel()
+
+	flowEngine, recorder := newTracedEngine(engine.NewDefaultRegistry())
+
+	execCtx, err := flowEngine.Run(context.Background(), newConditionalFlow("unknown +"), nil)
+	require.Error(t, err)
+
+	spans := spansByName(recorder)
+
+	check := spans["step check"]
+	require.NotNil(t, check)
+	assert.Equal(t, codes.Error, check.Status().Code)
+	assert.Equal(t, engine.CodeInvalidExpression, spanAttributes(check)[telemetry.ErrorCodeKey].AsString())
+	assert.Equal(t, execCtx.StepResults["check"].EndTime, check.EndTime())
+
+	run := spans["flow conditional"]
+	require.NotNil(t, run)
+	assert.Equal(t, codes.Error, run.Status().Code)
+	assert.Equal(t, "failed", spanAttributes(run)[telemetry.RunStatusKey].AsString())
+}
@@ -0,0 +1,308 @@
+package expr
+
+import (
+	"fmt"
+	"slices"
+)
+
+// kind is the static type of an expression as far as it can be known before a run.
+type kind int
+
+const (
+	kindAny kind = iota
+	kindNull
+	kindBool
+	kindNumber
+	kindString
+	kindObject
+)
+
+// staticType is the checked type of a node. Path is set for objects reached
+// from a root through literal field names, so their fields can be verified.
+type staticType struct {
+	kind kind
+	path []string
+}
+
+// checker type-checks expression trees against a schema.
+type checker struct {
+	variables []string
+	steps     []string
+	functions map[string]function
+}
+
+// String returns the name of the kind used in error messages.
+func (k kind) String() string {
+	switch k {
+	case kindAny:
+		return "any"
+	case kindNull:
+		return "null"
+	case kindBool:
+		return "boolean"
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	case kindObject:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// is reports whether a value of this type may be of kind want.
+func (t staticType) is(want kind) bool {
+	return t.kind == want || t.kind == kindAny
+}
+
+// stepFields returns the static types of the fields exposed for each step.
+func stepFields() map[string]kind {
+	return map[string]kind{
+		"output":     kindAny,
+		"status":     kindString,
+		"error":      kindAny,
+		"tokensUsed": kindNumber,
+		"cost":       kindNumber,
+		"duration":   kindNumber,
+		"metadata":   kindAny,
+	}
+}
+
+// flowFields returns the static types of the fields exposed for the running flow.
+func flowFields() map[string]kind {
+	return map[string]kind{
+		"id":      kindString,
+		"name":    kindString,
+		"version": kindString,
+	}
+}
+
+func newChecker(schema Schema) *checker {
+	return &checker{
+		variables: schema.Variables,
+		steps:     schema.Steps,
+		functions: builtins(),
+	}
+}
+
+// check returns the static type of n or the first problem found in it.
+func (c *checker) check(n node) (staticType, error) {
+	switch typed := n.(type) {
+	case *literalNode:
+		return staticType{kind: kindOfValue(typed.value), path: nil}, nil
+	case *identNode:
+		if typed.name != RootVars && typed.name != RootSteps && typed.name != RootFlow {
+			return staticType{}, fmt.Errorf("%w %q at position %d", ErrUnknownIdentifier, typed.name, typed.pos)
+		}
+
+		return staticType{kind: kindObject, path: []string{typed.name}}, nil
+	case *memberNode:
+		object, err := c.check(typed.object)
+		if err != nil {
+			return staticType{}, err
+		}
+
+		return c.access(object, typed.name, typed.pos)
+	case *indexNode:
+		return c.checkIndex(typed)
+	case *callNode:
+		return c.checkCall(typed)
+	case *unaryNode:
+		return c.checkUnary(typed)
+	case *binaryNode:
+		return c.checkBinary(typed)
+	default:
+		return staticType{kind: kindAny, path: nil}, nil
+	}
+}
+
+// checkIndex checks object[index]; literal string keys are checked like member access.
+func (c *checker) checkIndex(n *indexNode) (staticType, error) {
+	object, err := c.check(n.object)
+	if err != nil {
+		return staticType{}, err
+	}
+
+	index, err := c.check(n.index)
+	if err != nil {
+		return staticType{}, err
+	}
+
+	if literal, isLiteral := n.index.(*literalNode); isLiteral {
+		if key, isString := literal.value.(string); isString {
+			return c.access(object, key, n.pos)
+		}
+	}
+
+	if !object.is(kindObject) {
+		return staticType{}, fmt.Errorf("%w at position %d: cannot index %s", ErrTypeMismatch, n.pos, object.kind)
+	}
+
+	if !index.is(kindString) && !index.is(kindNumber) {
+		return staticType{}, fmt.Errorf("%w at position %d: index must be a string or number", ErrTypeMismatch, n.pos)
+	}
+
+	return staticType{kind: kindAny, path: nil}, nil
+}
+
+// access resolves a field of object, verifying names under the known roots.
+func (c *checker) access(object staticType, name string, pos int) (staticType, error) {
+	if object.kind == kindAny {
+		return staticType{kind: kindAny, path: nil}, nil
+	}
+
+	if object.kind != kindObject {
+		return staticType{}, fmt.Errorf("%w at position %d: cannot read field %q of %s",
+			ErrTypeMismatch, pos, name, object.kind)
+	}
+
+	switch {
+	case len(object.path) == 1 && object.path[0] == RootVars:
+		if !slices.Contains(c.variables, name) {
+			return staticType{}, fmt.Errorf("%w %q at position %d", ErrUnknownVariable, name, pos)
+		}
+
+		return staticType{kind: kindString, path: nil}, nil
+	case len(object.path) == 1 && object.path[0] == RootSteps:
+		if !slices.Contains(c.steps, name) {
+			return staticType{}, fmt.Errorf("%w %q at position %d", ErrUnknownStep, name, pos)
+		}
+
+		return staticType{kind: kindObject, path: []string{RootSteps, name}}, nil
+	case len(object.path) == 2 && object.path[0] == RootSteps:
+		return lookupField(stepFields(), "step", name, pos)
+	case len(object.path) == 1 && object.path[0] == RootFlow:
+		return lookupField(flowFields(), "flow", name, pos)
+	default:
+		return staticType{kind: kindAny, path: nil}, nil
+	}
+}
+
+// checkCall checks that the function exists and its arguments have compatible types.
+func (c *checker) checkCall(n *callNode) (staticType, error) {
+	fn, exists := c.functions[n.name]
+	if !exists {
+		return staticType{}, fmt.Errorf("%w %q at position %d", ErrUnknownFunction, n.name, n.pos)
+	}
+
+	if len(n.args) != len(fn.params) {
+		return staticType{}, fmt.Errorf("%w at position %d: %s expects %d argument(s), got %d",
+			ErrTypeMismatch, n.pos, n.name, len(fn.params), len(n.args))
+	}
+
+	for index, arg := range n.args {
+		argType, err := c.check(arg)
+		if err != nil {
+			return staticType{}, err
+		}
+
+		if fn.params[index] != kindAny && !argType.is(fn.params[index]) {
+			return staticType{}, fmt.Errorf("%w at position %d: argument %d of %s must be %s, got %s",
+				ErrTypeMismatch, arg.position(), index+1, n.name, fn.params[index], argType.kind)
+		}
+	}
+
+	return staticType{kind: fn.result, path: nil}, nil
+}
+
+// checkUnary checks the operand of ! and unary -.
+func (c *checker) checkUnary(n *unaryNode) (staticType, error) {
+	operand, err := c.check(n.operand)
+	if err != nil {
+		return staticType{}, err
+	}
+
+	want := kindNumber
+	if n.op == "!" {
+		want = kindBool
+	}
+
+	if !operand.is(want) {
+		return staticType{}, fmt.Errorf("%w at position %d: operator %s needs %s, got %s",
+			ErrTypeMismatch, n.pos, n.op, want, operand.kind)
+	}
+
+	return staticType{kind: want, path: nil}, nil
+}
+
+// checkBinary checks both operands of an infix operator.
+func (c *checker) checkBinary(n *binaryNode) (staticType, error) {
+	left, err := c.check(n.left)
+	if err != nil {
+		return staticType{}, err
+	}
+
+	right, err := c.check(n.right)
+	if err != nil {
+		return staticType{}, err
+	}
+
+	mismatch := fmt.Errorf("%w at position %d: operator %s cannot combine %s and %s",
+		ErrTypeMismatch, n.pos, n.op, left.kind, right.kind)
+
+	switch n.op {
+	case "&&", "||":
+		if !left.is(kindBool) || !right.is(kindBool) {
+			return staticType{}, mismatch
+		}
+
+		return staticType{kind: kindBool, path: nil}, nil
+	case "==", "!=":
+		return staticType{kind: kindBool, path: nil}, nil
+	case "<", "<=", ">", ">=":
+		if !(left.is(kindNumber) && right.is(kindNumber)) && !(left.is(kindString) && right.is(kindString)) {
+			return staticType{}, mismatch
+		}
+
+		return staticType{kind: kindBool, path: nil}, nil
+	case "+":
+		return checkPlus(left, right, mismatch)
+	default:
+		if !left.is(kindNumber) || !right.is(kindNumber) {
+			return staticType{}, mismatch
+		}
+
+		return staticType{kind: kindNumber, path: nil}, nil
+	}
+}
+
+// checkPlus types + which adds numbers and concatenates strings.
+func checkPlus(left, right staticType, mismatch error) (staticType, error) {
+	switch {
+	case left.kind == kindNumber && right.kind == kindNumber:
+		return staticType{kind: kindNumber, path: nil}, nil
+	case left.kind == kindString && right.kind == kindString:
+		return staticType{kind: kindString, path: nil}, nil
+	case (left.is(kindNumber) && right.is(kindNumber)) || (left.is(kindString) && right.is(kindString)):
+		return staticType{kind: kindAny, path: nil}, nil
+	default:
+		return staticType{}, mismatch
+	}
+}
+
+// lookupField returns the type of a field on a fixed-shape object.
+func lookupField(fields map[string]kind, owner, name string, pos int) (staticType, error) {
+	fieldKind, exists := fields[name]
+	if !exists {
+		return staticType{}, fmt.Errorf("%w %q on %s at position %d", ErrUnknownField, name, owner, pos)
+	}
+
+	return staticType{kind: fieldKind, path: nil}, nil
+}
+
+// kindOfValue returns the static kind of a literal value.
+func kindOfValue(value any) kind {
+	switch value.(type) {
+	case nil:
+		return kindNull
+	case bool:
+		return kindBool
+	case float64:
+		return kindNumber
+	case string:
+		return kindString
+	default:
+		return kindAny
+	}
+}
@@ -0,0 +1,335 @@
+package expr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ErrEvaluation is returned when an expression fails at run time.
+var ErrEvaluation = errors.New("evaluation error")
+
+// evaluate computes the value of n against scope.
+func evaluate(n node, scope map[string]any) (any, error) {
+	switch typed := n.(type) {
+	case *literalNode:
+		return typed.value, nil
+	case *identNode:
+		value, exists := scope[typed.name]
+		if !exists {
+			return nil, fmt.Errorf("%w %q at position %d", ErrUnknownIdentifier, typed.name, typed.pos)
+		}
+
+		return value, nil
+	case *memberNode:
+		object, err := evaluate(typed.object, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		return field(object, typed.name, typed.pos)
+	case *indexNode:
+		return evaluateIndex(typed, scope)
+	case *callNode:
+		return evaluateCall(typed, scope)
+	case *unaryNode:
+		return evaluateUnary(typed, scope)
+	case *binaryNode:
+		return evaluateBinary(typed, scope)
+	default:
+		return nil, fmt.Errorf("%w: unsupported expression node %T", ErrEvaluation, n)
+	}
+}
+
+// evaluateIndex reads a map key or list element.
+func evaluateIndex(n *indexNode, scope map[string]any) (any, error) {
+	object, err := evaluate(n.object, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := evaluate(n.index, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, isString := index.(string); isString {
+		return field(object, key, n.pos)
+	}
+
+	position, isNumber := toNumber(index)
+	if !isNumber || position != math.Trunc(position) {
+		return nil, fmt.Errorf("%w at position %d: index must be a string or integer", ErrEvaluation, n.pos)
+	}
+
+	list, isList := object.([]any)
+	if !isList {
+		if object == nil {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("%w at position %d: cannot index %T by number", ErrEvaluation, n.pos, object)
+	}
+
+	if position < 0 || int(position) >= len(list) {
+		return nil, nil
+	}
+
+	return list[int(position)], nil
+}
+
+// evaluateCall evaluates the arguments and invokes a built-in function.
+func evaluateCall(n *callNode, scope map[string]any) (any, error) {
+	fn, exists := builtins()[n.name]
+	if !exists {
+		return nil, fmt.Errorf("%w %q at position %d", ErrUnknownFunction, n.name, n.pos)
+	}
+
+	if len(n.args) != len(fn.params) {
+		return nil, fmt.Errorf("%w at position %d: %s expects %d argument(s), got %d",
+			ErrEvaluation, n.pos, n.name, len(fn.params), len(n.args))
+	}
+
+	args := make([]any, 0, len(n.args))
+
+	for _, arg := range n.args {
+		value, err := evaluate(arg, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, value)
+	}
+
+	result, err := fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s at position %d: %w", n.name, n.pos, err)
+	}
+
+	return result, nil
+}
+
+// evaluateUnary applies ! or unary -.
+func evaluateUnary(n *unaryNode, scope map[string]any) (any, error) {
+	operand, err := evaluate(n.operand, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "!" {
+		value, isBool := operand.(bool)
+		if !isBool {
+			return nil, fmt.Errorf("%w at position %d: ! needs a boolean, got %T", ErrEvaluation, n.pos, operand)
+		}
+
+		return !value, nil
+	}
+
+	value, isNumber := toNumber(operand)
+	if !isNumber {
+		return nil, fmt.Errorf("%w at position %d: - needs a number, got %T", ErrEvaluation, n.pos, operand)
+	}
+
+	return -value, nil
+}
+
+// evaluateBinary applies an infix operator; && and || short-circuit.
+func evaluateBinary(n *binaryNode, scope map[string]any) (any, error) {
+	left, err := evaluate(n.left, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "&&" || n.op == "||" {
+		return evaluateLogical(n, left, scope)
+	}
+
+	right, err := evaluate(n.right, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compare(n, left, right)
+	default:
+		return arithmetic(n, left, right)
+	}
+}
+
+// evaluateLogical evaluates && and || with short-circuiting.
+func evaluateLogical(n *binaryNode, left any, scope map[string]any) (any, error) {
+	leftBool, isBool := left.(bool)
+	if !isBool {
+		return nil, fmt.Errorf("%w at position %d: %s needs booleans, got %T", ErrEvaluation, n.pos, n.op, left)
+	}
+
+	if (n.op == "&&" && !leftBool) || (n.op == "||" && leftBool) {
+		return leftBool, nil
+	}
+
+	right, err := evaluate(n.right, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	rightBool, isBool := right.(bool)
+	if !isBool {
+		return nil, fmt.Errorf("%w at position %d: %s needs booleans, got %T", ErrEvaluation, n.pos, n.op, right)
+	}
+
+	return rightBool, nil
+}
+
+// compare orders two numbers or two strings.
+func compare(n *binaryNode, left, right any) (any, error) {
+	var order int
+
+	leftNumber, leftIsNumber := toNumber(left)
+	rightNumber, rightIsNumber := toNumber(right)
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+
+	switch {
+	case leftIsNumber && rightIsNumber:
+		order = compareOrdered(leftNumber, rightNumber)
+	case leftIsString && rightIsString:
+		order = compareOrdered(leftString, rightString)
+	default:
+		return nil, fmt.Errorf("%w at position %d: cannot compare %T and %T", ErrEvaluation, n.pos, left, right)
+	}
+
+	switch n.op {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
+
+// arithmetic applies + - * / %; + also concatenates strings.
+func arithmetic(n *binaryNode, left, right any) (any, error) {
+	if n.op == "+" {
+		leftString, leftIsString := left.(string)
+		rightString, rightIsString := right.(string)
+
+		if leftIsString && rightIsString {
+			return leftString + rightString, nil
+		}
+	}
+
+	leftNumber, leftIsNumber := toNumber(left)
+	rightNumber, rightIsNumber := toNumber(right)
+
+	if !leftIsNumber || !rightIsNumber {
+		return nil, fmt.Errorf("%w at position %d: %s cannot combine %T and %T", ErrEvaluation, n.pos, n.op, left, right)
+	}
+
+	switch n.op {
+	case "+":
+		return leftNumber + rightNumber, nil
+	case "-":
+		return leftNumber - rightNumber, nil
+	case "*":
+		return leftNumber * rightNumber, nil
+	default:
+		if rightNumber == 0 {
+			return nil, fmt.Errorf("%w at position %d: division by zero", ErrEvaluation, n.pos)
+		}
+
+		if n.op == "%" {
+			return math.Mod(leftNumber, rightNumber), nil
+		}
+
+		return leftNumber / rightNumber, nil
+	}
+}
+
+// field reads name from a map; missing keys and nil objects yield nil.
+func field(object any, name string, pos int) (any, error) {
+	switch typed := object.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return typed[name], nil
+	case map[string]string:
+		value, exists := typed[name]
+		if !exists {
+			return nil, nil
+		}
+
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%w at position %d: cannot read field %q of %T", ErrEvaluation, pos, name, object)
+	}
+}
+
+// equal compares two values, treating all numeric types alike.
+func equal(left, right any) bool {
+	leftNumber, leftIsNumber := toNumber(left)
+	rightNumber, rightIsNumber := toNumber(right)
+
+	if leftIsNumber && rightIsNumber {
+		return leftNumber == rightNumber
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+// toNumber converts any Go numeric value to float64.
+func toNumber(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case float32:
+		return float64(typed), true
+	case int:
+		return float64(typed), true
+	case int8:
+		return float64(typed), true
+	case int16:
+		return float64(typed), true
+	case int32:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case uint:
+		return float64(typed), true
+	case uint8:
+		return float64(typed), true
+	case uint16:
+		return float64(typed), true
+	case uint32:
+		return float64(typed), true
+	case uint64:
+		return float64(typed), true
+	case json.Number:
+		number, err := typed.Float64()
+
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// compareOrdered returns -1, 0 or 1.
+func compareOrdered[T float64 | string](left, right T) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
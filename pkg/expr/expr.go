@@ -0,0 +1,109 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownIdentifier is returned for identifiers other than the known roots.
+	ErrUnknownIdentifier = errors.New("unknown identifier")
+
+	// ErrUnknownVariable is returned when vars references an undeclared variable.
+	ErrUnknownVariable = errors.New("unknown variable")
+
+	// ErrUnknownStep is returned when steps references a step that does not exist.
+	ErrUnknownStep = errors.New("unknown step")
+
+	// ErrUnknownField is returned when a known object has no such field.
+	ErrUnknownField = errors.New("unknown field")
+
+	// ErrUnknownFunction is returned when calling a function that is not built in.
+	ErrUnknownFunction = errors.New("unknown function")
+
+	// ErrTypeMismatch is returned when an operator or function receives operands of the wrong type.
+	ErrTypeMismatch = errors.New("type mismatch")
+
+	// ErrNotBoolean is returned when a condition does not produce a boolean.
+	ErrNotBoolean = errors.New("expression does not produce a boolean")
+)
+
+// Root identifiers available to every expression.
+const (
+	RootVars  = "vars"
+	RootSteps = "steps"
+	RootFlow  = "flow"
+)
+
+// Schema describes the names an expression may reference when it is checked.
+type Schema struct {
+	// Variables lists the declared variable names readable through vars.
+	// Flow variables are strings, so they are checked as strings; number
+	// converts one to compare it with numbers.
+	Variables []string
+	// Steps lists the step IDs readable through steps.
+	Steps []string
+}
+
+// Expression is a parsed expression ready to be checked or evaluated.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses source into an expression.
+func Parse(source string) (*Expression, error) {
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the source text of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Check verifies that every name the expression references exists in schema
+// and that operators and functions receive operands of compatible types.
+func (e *Expression) Check(schema Schema) error {
+	_, err := newChecker(schema).check(e.root)
+
+	return err
+}
+
+// CheckBool runs Check and additionally verifies the expression can produce a boolean.
+func (e *Expression) CheckBool(schema Schema) error {
+	result, err := newChecker(schema).check(e.root)
+	if err != nil {
+		return err
+	}
+
+	if !result.is(kindBool) {
+		return fmt.Errorf("%w: %q produces %s", ErrNotBoolean, e.source, result.kind)
+	}
+
+	return nil
+}
+
+// Eval evaluates the expression against scope, which maps root identifiers to values.
+func (e *Expression) Eval(scope map[string]any) (any, error) {
+	return evaluate(e.root, scope)
+}
+
+// EvalBool evaluates the expression and requires a boolean result.
+func (e *Expression) EvalBool(scope map[string]any) (bool, error) {
+	value, err := e.Eval(scope)
+	if err != nil {
+		return false, err
+	}
+
+	result, isBool := value.(bool)
+	if !isBool {
+		return false, fmt.Errorf("%w: %q produced %T", ErrNotBoolean, e.source, value)
+	}
+
+	return result, nil
+}
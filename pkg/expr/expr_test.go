@@ -0,0 +1,188 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/expr"
+)
+
+func testScope() map[string]any {
+	return map[string]any{
+		"vars": map[string]any{"env": "prod", "threshold": "3"},
+		"steps": map[string]any{
+			"fetch": map[string]any{
+				"output": map[string]any{
+					"count":  float64(5),
+					"labels": []any{"bug", "urgent"},
+					"title":  "Fix login",
+				},
+				"status":     "completed",
+				"tokensUsed": 120,
+			},
+			"true-branch": map[string]any{"status": "completed"},
+		},
+		"flow": map[string]any{"id": "demo", "name": "Demo", "version": "1.0"},
+	}
+}
+
+func testSchema() expr.Schema {
+	return expr.Schema{
+		Variables: []string{"env", "threshold"},
+		Steps:     []string{"fetch", "true-branch", "report"},
+	}
+}
+
+func TestExpression_EvalBool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"true", true},
+		{"false", false},
+		{`steps.fetch.output.count > 3 && vars.env == "prod"`, true},
+		{`steps.fetch.output.count > 3 && vars.env == 'staging'`, false},
+		{"steps.fetch.output.count >= 5 || false", true},
+		{"!(steps.fetch.output.count < 2)", true},
+		{"steps.fetch.output.count * 2 - 1 == 9", true},
+		{"steps.fetch.output.count % 2 == 1", true},
+		{"number(vars.threshold) < steps.fetch.output.count", true},
+		{`contains(steps.fetch.output.labels, "urgent")`, true},
+		{`contains(steps.fetch.output.title, "login")`, true},
+		{`startsWith(lower(steps.fetch.output.title), "fix")`, true},
+		{`endsWith(upper(flow.id), "MO")`, true},
+		{"len(steps.fetch.output.labels) == 2", true},
+		{`steps.fetch.output.labels[0] == "bug"`, true},
+		{`steps["true-branch"].status == "completed"`, true},
+		{"exists(steps.report)", false},
+		{"steps.report.output == null", true},
+		{`string(steps.fetch.tokensUsed) == "120"`, true},
+		{`"a" + "b" == "ab"`, true},
+		{"false && missing.value", false},
+		{"true || missing.value", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := expr.Parse(tt.source)
+			require.NoError(t, err)
+
+			got, err := expression.EvalBool(testScope())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpression_EvalErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"non-boolean result", "steps.fetch.output.count"},
+		{"compare with null", "steps.report.output.count > 3"},
+		{"field of string", "flow.id.length == 1"},
+		{"division by zero", "1 / 0 == 1"},
+		{"non-boolean logic", "1 && true"},
+		{"unknown root", "context.value == 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := expr.Parse(tt.source)
+			require.NoError(t, err)
+
+			_, err = expression.EvalBool(testScope())
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParse_SyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, source := range []string{
+		"",
+		"1 +",
+		"(true",
+		`"unterminated`,
+		"steps.",
+		"a ? b : c",
+		"vars.env()",
+		"contains(1 2)",
+	} {
+		t.Run(source, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := expr.Parse(source)
+			require.ErrorIs(t, err, expr.ErrSyntax)
+		})
+	}
+}
+
+func TestExpression_CheckBool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+	}{
+		{"valid", `steps.fetch.output.count > 3 && vars.env == "prod"`, nil},
+		{"index access", `steps["true-branch"].status == "completed"`, nil},
+		{"dynamic output", "steps.fetch.output.items[0].id == 1", nil},
+		{"unknown root", "context.issue > 0", expr.ErrUnknownIdentifier},
+		{"unknown variable", `vars.enviroment == "prod"`, expr.ErrUnknownVariable},
+		{"unknown step", "steps.fetch_data.output.count > 3", expr.ErrUnknownStep},
+		{"unknown step field", "steps.fetch.outptu.count > 3", expr.ErrUnknownField},
+		{"unknown flow field", `flow.owner == "me"`, expr.ErrUnknownField},
+		{"unknown function", "size(steps.fetch.output) > 1", expr.ErrUnknownFunction},
+		{"wrong arity", "len(1, 2) > 1", expr.ErrTypeMismatch},
+		{"field of string", `steps.fetch.status.code == "x"`, expr.ErrTypeMismatch},
+		{"string minus number", `"a" - 1 == 0`, expr.ErrTypeMismatch},
+		{"compare bool", "true > false", expr.ErrTypeMismatch},
+		{"negate string", `!"yes"`, expr.ErrTypeMismatch},
+		{"numeric condition", "steps.fetch.tokensUsed + 1", expr.ErrNotBoolean},
+		{"converted variable", "number(vars.threshold) < 3", nil},
+		{"compare variable with number", "vars.threshold > 3", expr.ErrTypeMismatch},
+		{"variable condition", "vars.env", expr.ErrNotBoolean},
+		{"negate variable", "!vars.env", expr.ErrTypeMismatch},
+		{"variable operand", `vars.env && steps.fetch.status == "completed"`, expr.ErrTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := expr.Parse(tt.source)
+			require.NoError(t, err)
+
+			err = expression.CheckBool(testSchema())
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestExpression_String(t *testing.T) {
+	t.Parallel()
+
+	expression, err := expr.Parse("vars.env == 'prod'")
+	require.NoError(t, err)
+	assert.Equal(t, "vars.env == 'prod'", expression.String())
+}
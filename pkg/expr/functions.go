@@ -0,0 +1,146 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// function is a built-in function callable from expressions.
+type function struct {
+	params []kind
+	result kind
+	call   func(args []any) (any, error)
+}
+
+// builtins returns the functions available to every expression.
+func builtins() map[string]function {
+	return map[string]function{
+		"len":        {params: []kind{kindAny}, result: kindNumber, call: builtinLen},
+		"contains":   {params: []kind{kindAny, kindAny}, result: kindBool, call: builtinContains},
+		"startsWith": {params: []kind{kindString, kindString}, result: kindBool, call: stringPredicate(strings.HasPrefix)},
+		"endsWith":   {params: []kind{kindString, kindString}, result: kindBool, call: stringPredicate(strings.HasSuffix)},
+		"lower":      {params: []kind{kindString}, result: kindString, call: stringTransform(strings.ToLower)},
+		"upper":      {params: []kind{kindString}, result: kindString, call: stringTransform(strings.ToUpper)},
+		"exists":     {params: []kind{kindAny}, result: kindBool, call: builtinExists},
+		"string":     {params: []kind{kindAny}, result: kindString, call: builtinString},
+		"number":     {params: []kind{kindAny}, result: kindNumber, call: builtinNumber},
+	}
+}
+
+// builtinLen returns the length of a string, list or map; nil has length 0.
+func builtinLen(args []any) (any, error) {
+	switch typed := args[0].(type) {
+	case nil:
+		return float64(0), nil
+	case string:
+		return float64(utf8.RuneCountInString(typed)), nil
+	case []any:
+		return float64(len(typed)), nil
+	case []string:
+		return float64(len(typed)), nil
+	case map[string]any:
+		return float64(len(typed)), nil
+	case map[string]string:
+		return float64(len(typed)), nil
+	default:
+		return nil, fmt.Errorf("%w: len of %T", ErrEvaluation, args[0])
+	}
+}
+
+// builtinContains reports whether a string contains a substring or a list contains an element.
+func builtinContains(args []any) (any, error) {
+	switch typed := args[0].(type) {
+	case nil:
+		return false, nil
+	case string:
+		needle, isString := args[1].(string)
+		if !isString {
+			return nil, fmt.Errorf("%w: contains on a string needs a string, got %T", ErrEvaluation, args[1])
+		}
+
+		return strings.Contains(typed, needle), nil
+	case []any:
+		for _, element := range typed {
+			if equal(element, args[1]) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case []string:
+		for _, element := range typed {
+			if equal(element, args[1]) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	default:
+		return nil, fmt.Errorf("%w: contains on %T", ErrEvaluation, args[0])
+	}
+}
+
+// builtinExists reports whether its argument is not null.
+func builtinExists(args []any) (any, error) {
+	return args[0] != nil, nil
+}
+
+// builtinString formats its argument as a string.
+func builtinString(args []any) (any, error) {
+	if args[0] == nil {
+		return "", nil
+	}
+
+	if number, isNumber := toNumber(args[0]); isNumber {
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	}
+
+	return fmt.Sprint(args[0]), nil
+}
+
+// builtinNumber converts a number or numeric string to a number.
+func builtinNumber(args []any) (any, error) {
+	if number, isNumber := toNumber(args[0]); isNumber {
+		return number, nil
+	}
+
+	text, isString := args[0].(string)
+	if !isString {
+		return nil, fmt.Errorf("%w: cannot convert %T to number", ErrEvaluation, args[0])
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot convert %q to number", ErrEvaluation, text)
+	}
+
+	return number, nil
+}
+
+// stringPredicate adapts a two-string predicate to a built-in function.
+func stringPredicate(predicate func(value, affix string) bool) func(args []any) (any, error) {
+	return func(args []any) (any, error) {
+		value, valueIsString := args[0].(string)
+		affix, affixIsString := args[1].(string)
+
+		if !valueIsString || !affixIsString {
+			return nil, fmt.Errorf("%w: expected strings, got %T and %T", ErrEvaluation, args[0], args[1])
+		}
+
+		return predicate(value, affix), nil
+	}
+}
+
+// stringTransform adapts a string mapping to a built-in function.
+func stringTransform(transform func(value string) string) func(args []any) (any, error) {
+	return func(args []any) (any, error) {
+		value, isString := args[0].(string)
+		if !isString {
+			return nil, fmt.Errorf("%w: expected a string, got %T", ErrEvaluation, args[0])
+		}
+
+		return transform(value), nil
+	}
+}
@@ -0,0 +1,190 @@
+// Package expr implements the expression language used by condition steps.
+//
+// Expressions read three roots: vars (runtime variables seeded from the flow
+// variables, which are strings; number(vars.count) reads one as a number),
+// steps (results of executed steps, e.g. steps.fetch.output.count) and flow
+// (id, name and version of the running flow). They support the
+// usual comparison, arithmetic and boolean operators, member and index
+// access, and a small set of built-in functions.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrSyntax is returned when an expression cannot be parsed.
+var ErrSyntax = errors.New("syntax error")
+
+// tokenKind classifies a lexical token.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+// token is a single lexical token and its byte offset in the source.
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+// operators lists multi-character operators before their single-character prefixes.
+func operators() []string {
+	return []string{
+		"||", "&&", "==", "!=", "<=", ">=",
+		"<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ".", ",",
+	}
+}
+
+// tokenize splits source into tokens.
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+
+	pos := 0
+	for pos < len(source) {
+		char, width := utf8.DecodeRuneInString(source[pos:])
+
+		switch {
+		case unicode.IsSpace(char):
+			pos += width
+		case char == '"' || char == '\'':
+			tok, end, err := lexString(source, pos, char)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			pos = end
+		case isDigit(char):
+			tok, end, err := lexNumber(source, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			pos = end
+		case isIdentStart(char):
+			end := pos
+			for end < len(source) {
+				next, nextWidth := utf8.DecodeRuneInString(source[end:])
+				if !isIdentPart(next) {
+					break
+				}
+
+				end += nextWidth
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: source[pos:end], value: nil, pos: pos})
+			pos = end
+		default:
+			tok, err := lexOperator(source, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			pos += len(tok.text)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, text: "", value: nil, pos: pos}), nil
+}
+
+// lexString reads a quoted string literal starting at pos.
+func lexString(source string, pos int, quote rune) (token, int, error) {
+	var builder strings.Builder
+
+	for index := pos + 1; index < len(source); index++ {
+		char := rune(source[index])
+
+		switch {
+		case char == quote:
+			return token{kind: tokenString, text: source[pos : index+1], value: builder.String(), pos: pos}, index + 1, nil
+		case char == '\\' && index+1 < len(source):
+			index++
+			builder.WriteRune(unescape(rune(source[index])))
+		default:
+			builder.WriteByte(source[index])
+		}
+	}
+
+	return token{}, 0, fmt.Errorf("%w at position %d: unterminated string", ErrSyntax, pos)
+}
+
+// lexNumber reads a decimal number literal starting at pos.
+func lexNumber(source string, pos int) (token, int, error) {
+	end := pos
+	seenDot := false
+
+	for end < len(source) {
+		char := rune(source[end])
+		if char == '.' && !seenDot && end+1 < len(source) && isDigit(rune(source[end+1])) {
+			seenDot = true
+			end++
+
+			continue
+		}
+
+		if !isDigit(char) {
+			break
+		}
+
+		end++
+	}
+
+	var value float64
+
+	_, err := fmt.Sscanf(source[pos:end], "%g", &value)
+	if err != nil {
+		return token{}, 0, fmt.Errorf("%w at position %d: invalid number %q", ErrSyntax, pos, source[pos:end])
+	}
+
+	return token{kind: tokenNumber, text: source[pos:end], value: value, pos: pos}, end, nil
+}
+
+// lexOperator reads the longest operator starting at pos.
+func lexOperator(source string, pos int) (token, error) {
+	for _, operator := range operators() {
+		if strings.HasPrefix(source[pos:], operator) {
+			return token{kind: tokenOperator, text: operator, value: nil, pos: pos}, nil
+		}
+	}
+
+	return token{}, fmt.Errorf("%w at position %d: unexpected character %q", ErrSyntax, pos, source[pos])
+}
+
+// unescape returns the character denoted by a backslash escape.
+func unescape(char rune) rune {
+	switch char {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	default:
+		return char
+	}
+}
+
+func isDigit(char rune) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isIdentStart(char rune) bool {
+	return char == '_' || unicode.IsLetter(char)
+}
+
+func isIdentPart(char rune) bool {
+	return isIdentStart(char) || unicode.IsDigit(char)
+}
@@ -0,0 +1,304 @@
+package expr
+
+import (
+	"fmt"
+	"slices"
+)
+
+// node is an element of a parsed expression tree.
+type node interface {
+	position() int
+}
+
+// literalNode is a number, string, boolean or null literal.
+type literalNode struct {
+	pos   int
+	value any
+}
+
+// identNode references a root identifier such as vars or steps.
+type identNode struct {
+	pos  int
+	name string
+}
+
+// memberNode reads a named field: object.name.
+type memberNode struct {
+	pos    int
+	object node
+	name   string
+}
+
+// indexNode reads an element or key: object[index].
+type indexNode struct {
+	pos    int
+	object node
+	index  node
+}
+
+// callNode invokes a built-in function.
+type callNode struct {
+	pos  int
+	name string
+	args []node
+}
+
+// unaryNode applies a prefix operator.
+type unaryNode struct {
+	pos     int
+	op      string
+	operand node
+}
+
+// binaryNode applies an infix operator.
+type binaryNode struct {
+	pos   int
+	op    string
+	left  node
+	right node
+}
+
+func (n *literalNode) position() int { return n.pos }
+func (n *identNode) position() int   { return n.pos }
+func (n *memberNode) position() int  { return n.pos }
+func (n *indexNode) position() int   { return n.pos }
+func (n *callNode) position() int    { return n.pos }
+func (n *unaryNode) position() int   { return n.pos }
+func (n *binaryNode) position() int  { return n.pos }
+
+// parser is a recursive-descent parser over a token stream.
+type parser struct {
+	tokens  []token
+	current int
+}
+
+// binaryLevels lists infix operators from lowest to highest precedence.
+func binaryLevels() [][]string {
+	return [][]string{
+		{"||"},
+		{"&&"},
+		{"==", "!="},
+		{"<", "<=", ">", ">="},
+		{"+", "-"},
+		{"*", "/", "%"},
+	}
+}
+
+// parse builds the expression tree for source.
+func parse(source string) (node, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, current: 0}
+
+	root, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w at position %d: unexpected %q", ErrSyntax, tok.pos, tok.text)
+	}
+
+	return root, nil
+}
+
+// parseBinary parses infix operators at the given precedence level and above.
+func (p *parser) parseBinary(level int) (node, error) {
+	levels := binaryLevels()
+	if level >= len(levels) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator || !slices.Contains(levels[level], tok.text) {
+			return left, nil
+		}
+
+		p.advance()
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryNode{pos: tok.pos, op: tok.text, left: left, right: right}
+	}
+}
+
+// parseUnary parses prefix ! and - operators.
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+	if tok.kind == tokenOperator && (tok.text == "!" || tok.text == "-") {
+		p.advance()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryNode{pos: tok.pos, op: tok.text, operand: operand}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+// parsePostfix parses member access, indexing and calls after a primary expression.
+func (p *parser) parsePostfix() (node, error) {
+	result, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator {
+			return result, nil
+		}
+
+		switch tok.text {
+		case ".":
+			p.advance()
+
+			name := p.advance()
+			if name.kind != tokenIdent {
+				return nil, fmt.Errorf("%w at position %d: expected field name after '.'", ErrSyntax, name.pos)
+			}
+
+			result = &memberNode{pos: tok.pos, object: result, name: name.text}
+		case "[":
+			p.advance()
+
+			index, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+
+			err = p.expect("]")
+			if err != nil {
+				return nil, err
+			}
+
+			result = &indexNode{pos: tok.pos, object: result, index: index}
+		case "(":
+			ident, isIdent := result.(*identNode)
+			if !isIdent {
+				return nil, fmt.Errorf("%w at position %d: only built-in functions can be called", ErrSyntax, tok.pos)
+			}
+
+			args, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+
+			result = &callNode{pos: ident.pos, name: ident.name, args: args}
+		default:
+			return result, nil
+		}
+	}
+}
+
+// parseArguments parses a parenthesized, comma-separated argument list.
+func (p *parser) parseArguments() ([]node, error) {
+	p.advance() // consume "("
+
+	var args []node
+
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == ")" {
+		p.advance()
+
+		return args, nil
+	}
+
+	for {
+		arg, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+
+		tok := p.advance()
+		if tok.kind == tokenOperator && tok.text == ")" {
+			return args, nil
+		}
+
+		if tok.kind != tokenOperator || tok.text != "," {
+			return nil, fmt.Errorf("%w at position %d: expected ',' or ')'", ErrSyntax, tok.pos)
+		}
+	}
+}
+
+// parsePrimary parses literals, identifiers and parenthesized expressions.
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.advance()
+
+	switch tok.kind {
+	case tokenNumber, tokenString:
+		return &literalNode{pos: tok.pos, value: tok.value}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{pos: tok.pos, value: true}, nil
+		case "false":
+			return &literalNode{pos: tok.pos, value: false}, nil
+		case "null", "nil":
+			return &literalNode{pos: tok.pos, value: nil}, nil
+		default:
+			return &identNode{pos: tok.pos, name: tok.text}, nil
+		}
+	case tokenOperator:
+		if tok.text == "(" {
+			inner, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+
+			err = p.expect(")")
+			if err != nil {
+				return nil, err
+			}
+
+			return inner, nil
+		}
+
+		return nil, fmt.Errorf("%w at position %d: unexpected %q", ErrSyntax, tok.pos, tok.text)
+	case tokenEOF:
+		return nil, fmt.Errorf("%w at position %d: unexpected end of expression", ErrSyntax, tok.pos)
+	default:
+		return nil, fmt.Errorf("%w at position %d: unexpected %q", ErrSyntax, tok.pos, tok.text)
+	}
+}
+
+// expect consumes the operator text or returns a syntax error.
+func (p *parser) expect(text string) error {
+	tok := p.advance()
+	if tok.kind != tokenOperator || tok.text != text {
+		return fmt.Errorf("%w at position %d: expected %q", ErrSyntax, tok.pos, text)
+	}
+
+	return nil
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.current]
+}
+
+// advance consumes and returns the current token, stopping at EOF.
+func (p *parser) advance() token {
+	tok := p.tokens[p.current]
+	if tok.kind != tokenEOF {
+		p.current++
+	}
+
+	return tok
+}
@@ -0,0 +1,244 @@
+// Package github is a small client for the GitHub REST and GraphQL APIs
+// covering the issue, pull request and review operations used by flow steps.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// DefaultBaseURL is the GitHub API endpoint used when no base URL is configured.
+// The GraphQL API is found from the base URL: at /graphql on github.com and,
+// for a GitHub Enterprise Server base URL ending in /api/v3, at /api/graphql.
+const DefaultBaseURL = "https://api.github.com"
+
+// enterpriseRESTPath ends the REST API base URL of GitHub Enterprise Server.
+const enterpriseRESTPath = "/api/v3"
+
+// Error codes reported by the GitHub client.
+const (
+	CodeGitHubRequestFailed = "GITHUB_REQUEST_FAILED"
+	CodeGitHubBadResponse   = "GITHUB_BAD_RESPONSE"
+)
+
+// ErrMissingToken is returned when the client has no token.
+var ErrMissingToken = errors.New("GitHub token is not configured")
+
+const (
+	apiVersion        = "2022-11-28"
+	maxErrorBodyBytes = 4096
+)
+
+// Client calls the GitHub API with a token.
+type Client struct {
+	token      string
+	baseURL    string
+	graphQLURL string
+	httpClient *http.Client
+}
+
+// errorResponse is the error body of the REST API.
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// graphQLRequest is the body of a GraphQL request.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// graphQLResponse is the body of a GraphQL response.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewClient creates a client for the GitHub API.
+// An empty baseURL selects DefaultBaseURL.
+func NewClient(token, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	return &Client{
+		token:      token,
+		baseURL:    baseURL,
+		graphQLURL: graphQLURL(baseURL),
+		httpClient: &http.Client{}, //nolint:exhaustruct // zero values are the documented defaults
+	}
+}
+
+// graphQLURL returns the GraphQL endpoint of the API whose REST base URL is baseURL.
+func graphQLURL(baseURL string) string {
+	if host, enterprise := strings.CutSuffix(baseURL, enterpriseRESTPath); enterprise {
+		return host + "/api/graphql"
+	}
+
+	return baseURL + "/graphql"
+}
+
+// SetHTTPClient replaces the HTTP client used for requests.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// rest sends a REST request with an optional JSON body and decodes the JSON response into out.
+func (c *Client) rest(ctx context.Context, method, path string, body, out any) error {
+	err := c.send(ctx, method, c.baseURL+path, body, out)
+	if err != nil && method != http.MethodGet {
+		return writeError(err)
+	}
+
+	return err
+}
+
+// graphql runs a GraphQL query and decodes its data into out.
+func (c *Client) graphql(ctx context.Context, query string, variables map[string]any, out any) error {
+	var resp graphQLResponse
+
+	err := c.send(ctx, http.MethodPost, c.graphQLURL, graphQLRequest{Query: query, Variables: variables}, &resp)
+	if err != nil && strings.HasPrefix(query, "mutation") {
+		return writeError(err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, gqlErr := range resp.Errors {
+			messages = append(messages, gqlErr.Message)
+		}
+
+		return newRequestError("GitHub GraphQL error: "+strings.Join(messages, "; "), http.StatusOK, false)
+	}
+
+	err = json.Unmarshal(resp.Data, out)
+	if err != nil {
+		return newBadResponseError(fmt.Sprintf("failed to decode GraphQL data: %v", err))
+	}
+
+	return nil
+}
+
+// send performs a request and decodes a successful JSON response into out.
+func (c *Client) send(ctx context.Context, method, url string, body, out any) error {
+	if c.token == "" {
+		return ErrMissingToken
+	}
+
+	var reader io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal GitHub request: %w", err)
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	req.Header.Set("User-Agent", "flow-test-go")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return newRequestError(fmt.Sprintf("GitHub request failed: %v", err), 0, true)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return statusError(resp)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return newBadResponseError(fmt.Sprintf("failed to decode GitHub response: %v", err))
+	}
+
+	return nil
+}
+
+// statusError converts an unsuccessful response into an ExecutionError.
+// Rate limits and server errors are recoverable; other client errors are not.
+func statusError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+
+	message := strings.TrimSpace(string(data))
+
+	var decoded errorResponse
+	if json.Unmarshal(data, &decoded) == nil && decoded.Message != "" {
+		message = decoded.Message
+	}
+
+	recoverable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+
+	return newRequestError(fmt.Sprintf("GitHub returned %d: %s", resp.StatusCode, message), resp.StatusCode, recoverable)
+}
+
+// writeError returns the error of a failed write, such as posting a comment.
+// Only a rate limit proves GitHub did not apply the write; after any other
+// failure, such as a timeout or a server error, retrying could apply it twice.
+func writeError(err error) error {
+	var execErr *types.ExecutionError
+	if !errors.As(err, &execErr) {
+		return err
+	}
+
+	details, ok := execErr.Details.(map[string]any)
+	if !ok || details["status"] != http.StatusTooManyRequests {
+		execErr.Recoverable = false
+	}
+
+	return err
+}
+
+// newRequestError creates the error reported for a failed GitHub request.
+func newRequestError(message string, status int, recoverable bool) *types.ExecutionError {
+	return &types.ExecutionError{
+		Code:        CodeGitHubRequestFailed,
+		Message:     message,
+		Details:     map[string]any{"status": status},
+		Recoverable: recoverable,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
+
+// newBadResponseError creates the error reported for an unusable GitHub response.
+func newBadResponseError(message string) *types.ExecutionError {
+	return &types.ExecutionError{
+		Code:        CodeGitHubBadResponse,
+		Message:     message,
+		Details:     nil,
+		Recoverable: false,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
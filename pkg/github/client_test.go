@@ -0,0 +1,315 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/github"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// graphQLBody is the decoded body of a GraphQL request.
+type graphQLBody struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+func TestClient_IssuesAndPullRequests(t *testing.T) {
+	t.Parallel()
+
+	var comment, labels map[string]any
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/acme/widgets/issues/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+
+		_, _ = w.Write([]byte(`{"number": 7, "title": "Crash on start", "body": "It crashes", "state": "open",
+			"user": {"login": "ada"}, "labels": [{"name": "bug"}], "html_url": "https://github.com/acme/widgets/issues/7"}`))
+	})
+	mux.HandleFunc("GET /repos/acme/widgets/pulls/8", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"number": 8, "title": "Fix crash", "state": "open", "draft": true,
+			"user": {"login": "grace"}, "head": {"ref": "fix-crash"}, "base": {"ref": "main"}}`))
+	})
+	mux.HandleFunc("POST /repos/acme/widgets/issues/8/comments", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 99, "body": "LGTM", "user": {"login": "bot"}, "html_url": "https://example/c/99"}`))
+	})
+	mux.HandleFunc("POST /repos/acme/widgets/issues/8/labels", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&labels))
+		_, _ = w.Write([]byte(`[{"name": "bug"}, {"name": "reviewed"}]`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := github.NewClient("test-token", server.URL+"/")
+	ctx := context.Background()
+
+	issue, err := client.GetIssue(ctx, "acme", "widgets", 7)
+	require.NoError(t, err)
+	assert.Equal(t, &github.Issue{
+		Number: 7, Title: "Crash on start", Body: "It crashes", State: "open", Author: "ada",
+		Labels: []string{"bug"}, URL: "https://github.com/acme/widgets/issues/7",
+	}, issue)
+
+	pull, err := client.GetPullRequest(ctx, "acme", "widgets", 8)
+	require.NoError(t, err)
+	assert.True(t, pull.Draft)
+	assert.Equal(t, "fix-crash", pull.Head)
+	assert.Equal(t, "main", pull.Base)
+	assert.Equal(t, "grace", pull.Author)
+
+	created, err := client.CreateComment(ctx, "acme", "widgets", 8, "LGTM")
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), created.ID)
+	assert.Equal(t, map[string]any{"body": "LGTM"}, comment)
+
+	all, err := client.AddLabels(ctx, "acme", "widgets", 8, []string{"reviewed"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bug", "reviewed"}, all)
+	assert.Equal(t, map[string]any{"labels": []any{"reviewed"}}, labels)
+}
+
+func TestClient_ReviewThreads(t *testing.T) {
+	t.Parallel()
+
+	var requests []graphQLBody
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+
+		var body graphQLBody
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		requests = append(requests, body)
+
+		switch {
+		case body.Variables["threadId"] != nil && body.Variables["body"] != nil:
+			_, _ = w.Write([]byte(`{"data": {"addPullRequestReviewThreadReply": {"comment": {"url": "https://example/r/1"}}}}`))
+		case body.Variables["threadId"] != nil:
+			_, _ = w.Write([]byte(`{"data": {"resolveReviewThread": {"thread": {"id": "T2", "isResolved": true}}}}`))
+		case body.Variables["cursor"] == nil:
+			_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequest": {"reviewThreads": {
+				"pageInfo": {"hasNextPage": true, "endCursor": "page-2"},
+				"nodes": [{"id": "T1", "isResolved": true, "path": "main.go", "line": 3,
+					"comments": {"nodes": [{"author": {"login": "ada"}, "body": "nit"}]}}]}}}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequest": {"reviewThreads": {
+				"pageInfo": {"hasNextPage": false, "endCursor": ""},
+				"nodes": [{"id": "T2", "isResolved": false, "isOutdated": true, "path": "engine.go", "line": 10,
+					"comments": {"nodes": [{"author": {"login": "grace"}, "body": "handle the error"}]}}]}}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient("test-token", server.URL)
+	ctx := context.Background()
+
+	threads, err := client.ListReviewThreads(ctx, "acme", "widgets", 8)
+	require.NoError(t, err)
+	require.Len(t, threads, 2)
+	assert.Equal(t, "T1", threads[0].ID)
+	assert.Equal(t, "ada", threads[0].Comments[0].Author)
+	assert.False(t, threads[1].IsResolved)
+	assert.True(t, threads[1].IsOutdated)
+	assert.Equal(t, "page-2", requests[1].Variables["cursor"])
+	assert.InDelta(t, 8, requests[0].Variables["number"], 0)
+	assert.InDelta(t, 100, requests[0].Variables["first"], 0)
+	assert.NotContains(t, requests[0].Query, "first: 100")
+
+	reply, err := client.ReplyToReviewThread(ctx, "T2", "Fixed in 1a2b3c")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example/r/1", reply.URL)
+
+	resolved, err := client.ResolveReviewThread(ctx, "T2")
+	require.NoError(t, err)
+	assert.True(t, resolved.IsResolved)
+	assert.Contains(t, requests[3].Query, "resolveReviewThread")
+}
+
+func TestClient_ReviewThreadComments(t *testing.T) {
+	t.Parallel()
+
+	var requests []graphQLBody
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLBody
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		requests = append(requests, body)
+
+		switch body.Variables["cursor"] {
+		case nil:
+			_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequest": {"reviewThreads": {
+				"pageInfo": {"hasNextPage": false, "endCursor": ""},
+				"nodes": [{"id": "T1", "path": "main.go", "line": 3, "comments": {
+					"pageInfo": {"hasNextPage": true, "endCursor": "comments-2"},
+					"nodes": [{"author": {"login": "ada"}, "body": "nit"}]}}]}}}}}`))
+		case "comments-2":
+			_, _ = w.Write([]byte(`{"data": {"node": {"comments": {
+				"pageInfo": {"hasNextPage": true, "endCursor": "comments-3"},
+				"nodes": [{"author": {"login": "grace"}, "body": "agreed"}]}}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data": {"node": {"comments": {
+				"pageInfo": {"hasNextPage": false, "endCursor": ""},
+				"nodes": [{"author": {"login": "ada"}, "body": "fixed"}]}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient("test-token", server.URL)
+
+	threads, err := client.ListReviewThreads(context.Background(), "acme", "widgets", 8)
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	require.Len(t, threads[0].Comments, 3)
+	assert.Equal(t, "nit", threads[0].Comments[0].Body)
+	assert.Equal(t, "agreed", threads[0].Comments[1].Body)
+	assert.Equal(t, "fixed", threads[0].Comments[2].Body)
+	require.Len(t, requests, 3)
+	assert.Equal(t, "T1", requests[1].Variables["threadId"])
+	assert.Equal(t, "comments-3", requests[2].Variables["cursor"])
+}
+
+func TestClient_EnterpriseGraphQLURL(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		if r.URL.Path == "/api/graphql" {
+			_, _ = w.Write([]byte(`{"data": {"resolveReviewThread": {"thread": {"id": "T1", "isResolved": true}}}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"number": 1, "title": "Crash on start"}`))
+	}))
+	defer server.Close()
+
+	client := github.NewClient("test-token", server.URL+"/api/v3/")
+
+	_, err := client.GetIssue(context.Background(), "acme", "widgets", 1)
+	require.NoError(t, err)
+
+	_, err = client.ResolveReviewThread(context.Background(), "T1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/api/v3/repos/acme/widgets/issues/1", "/api/graphql"}, paths)
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		message     string
+		recoverable bool
+	}{
+		{name: "not found", status: http.StatusNotFound, body: `{"message": "Not Found"}`,
+			message: "GitHub returned 404: Not Found"},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{"message": "slow down"}`,
+			message: "GitHub returned 429: slow down", recoverable: true},
+		{name: "graphql error", status: http.StatusOK, body: `{"errors": [{"message": "Could not resolve thread"}]}`,
+			message: "GitHub GraphQL error: Could not resolve thread"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := github.NewClient("test-token", server.URL).ResolveReviewThread(context.Background(), "T1")
+
+			var execErr *types.ExecutionError
+			require.ErrorAs(t, err, &execErr)
+			assert.Equal(t, github.CodeGitHubRequestFailed, execErr.Code)
+			assert.Equal(t, tt.message, execErr.Message)
+			assert.Equal(t, tt.recoverable, execErr.Recoverable)
+		})
+	}
+}
+
+func TestClient_WriteErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		status      int
+		call        func(ctx context.Context, client *github.Client) error
+		recoverable bool
+	}{
+		{name: "read after server error", status: http.StatusBadGateway, recoverable: true,
+			call: func(ctx context.Context, client *github.Client) error {
+				_, err := client.GetIssue(ctx, "acme", "widgets", 1)
+
+				return err
+			}},
+		{name: "comment after server error", status: http.StatusBadGateway,
+			call: func(ctx context.Context, client *github.Client) error {
+				_, err := client.CreateComment(ctx, "acme", "widgets", 1, "Thanks!")
+
+				return err
+			}},
+		{name: "labels after server error", status: http.StatusBadGateway,
+			call: func(ctx context.Context, client *github.Client) error {
+				_, err := client.AddLabels(ctx, "acme", "widgets", 1, []string{"bug"})
+
+				return err
+			}},
+		{name: "reply after server error", status: http.StatusBadGateway,
+			call: func(ctx context.Context, client *github.Client) error {
+				_, err := client.ReplyToReviewThread(ctx, "T1", "Fixed")
+
+				return err
+			}},
+		{name: "comment after rate limit", status: http.StatusTooManyRequests, recoverable: true,
+			call: func(ctx context.Context, client *github.Client) error {
+				_, err := client.CreateComment(ctx, "acme", "widgets", 1, "Thanks!")
+
+				return err
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := tt.call(context.Background(), github.NewClient("test-token", server.URL))
+
+			var execErr *types.ExecutionError
+			require.ErrorAs(t, err, &execErr)
+			assert.Equal(t, tt.recoverable, execErr.Recoverable)
+		})
+	}
+}
+
+func TestClient_MissingToken(t *testing.T) {
+	t.Parallel()
+
+	_, err := github.NewClient("", "").GetIssue(context.Background(), "acme", "widgets", 1)
+	require.ErrorIs(t, err, github.ErrMissingToken)
+}
@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Issue is an issue or pull request as seen through the issues API.
+type Issue struct {
+	Number int      `json:"number"`
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	State  string   `json:"state"`
+	Author string   `json:"author"`
+	Labels []string `json:"labels"`
+	URL    string   `json:"url"`
+}
+
+// PullRequest is a pull request with its branches and merge state.
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	Author string `json:"author"`
+	Draft  bool   `json:"draft"`
+	Merged bool   `json:"merged"`
+	Head   string `json:"head"`
+	Base   string `json:"base"`
+	URL    string `json:"url"`
+}
+
+// Comment is a comment on an issue or pull request.
+type Comment struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	Author string `json:"author"`
+	URL    string `json:"url"`
+}
+
+// restUser is the user object of the REST API.
+type restUser struct {
+	Login string `json:"login"`
+}
+
+// restLabel is the label object of the REST API.
+type restLabel struct {
+	Name string `json:"name"`
+}
+
+// restRef is a branch reference of a pull request in the REST API.
+type restRef struct {
+	Ref string `json:"ref"`
+}
+
+// restIssue is the issue object of the REST API.
+type restIssue struct {
+	Number  int         `json:"number"`
+	Title   string      `json:"title"`
+	Body    string      `json:"body"`
+	State   string      `json:"state"`
+	User    restUser    `json:"user"`
+	Labels  []restLabel `json:"labels"`
+	HTMLURL string      `json:"html_url"` //nolint:tagliatelle // GitHub wire format
+}
+
+// restPullRequest is the pull request object of the REST API.
+type restPullRequest struct {
+	Number  int      `json:"number"`
+	Title   string   `json:"title"`
+	Body    string   `json:"body"`
+	State   string   `json:"state"`
+	User    restUser `json:"user"`
+	Draft   bool     `json:"draft"`
+	Merged  bool     `json:"merged"`
+	Head    restRef  `json:"head"`
+	Base    restRef  `json:"base"`
+	HTMLURL string   `json:"html_url"` //nolint:tagliatelle // GitHub wire format
+}
+
+// restComment is the issue comment object of the REST API.
+type restComment struct {
+	ID      int64    `json:"id"`
+	Body    string   `json:"body"`
+	User    restUser `json:"user"`
+	HTMLURL string   `json:"html_url"` //nolint:tagliatelle // GitHub wire format
+}
+
+// GetIssue returns an issue. Pull requests can be read as issues too.
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	var issue restIssue
+
+	err := c.rest(ctx, http.MethodGet, fmt.Sprintf("%s/issues/%d", repoPath(owner, repo), number), nil, &issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Issue{
+		Number: issue.Number,
+		Title:  issue.Title,
+		Body:   issue.Body,
+		State:  issue.State,
+		Author: issue.User.Login,
+		Labels: labelNames(issue.Labels),
+		URL:    issue.HTMLURL,
+	}, nil
+}
+
+// GetPullRequest returns a pull request.
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	var pull restPullRequest
+
+	err := c.rest(ctx, http.MethodGet, fmt.Sprintf("%s/pulls/%d", repoPath(owner, repo), number), nil, &pull)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PullRequest{
+		Number: pull.Number,
+		Title:  pull.Title,
+		Body:   pull.Body,
+		State:  pull.State,
+		Author: pull.User.Login,
+		Draft:  pull.Draft,
+		Merged: pull.Merged,
+		Head:   pull.Head.Ref,
+		Base:   pull.Base.Ref,
+		URL:    pull.HTMLURL,
+	}, nil
+}
+
+// CreateComment posts a comment on an issue or pull request.
+func (c *Client) CreateComment(ctx context.Context, owner, repo string, number int, body string) (*Comment, error) {
+	var comment restComment
+
+	err := c.rest(ctx, http.MethodPost, fmt.Sprintf("%s/issues/%d/comments", repoPath(owner, repo), number),
+		map[string]string{"body": body}, &comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comment{
+		ID:     comment.ID,
+		Body:   comment.Body,
+		Author: comment.User.Login,
+		URL:    comment.HTMLURL,
+	}, nil
+}
+
+// AddLabels adds labels to an issue or pull request and returns all of its labels.
+func (c *Client) AddLabels(ctx context.Context, owner, repo string, number int, labels []string) ([]string, error) {
+	var added []restLabel
+
+	err := c.rest(ctx, http.MethodPost, fmt.Sprintf("%s/issues/%d/labels", repoPath(owner, repo), number),
+		map[string][]string{"labels": labels}, &added)
+	if err != nil {
+		return nil, err
+	}
+
+	return labelNames(added), nil
+}
+
+// repoPath returns the REST path of a repository.
+func repoPath(owner, repo string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// labelNames returns the names of labels.
+func labelNames(labels []restLabel) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,259 @@
+package github
+
+import (
+	"context"
+)
+
+// reviewThreadsPageSize is how many review threads and comments are requested per page.
+const reviewThreadsPageSize = 100
+
+const listReviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $first: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: $first, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          isResolved
+          isOutdated
+          path
+          line
+          comments(first: $first) {
+            pageInfo { hasNextPage endCursor }
+            nodes { author { login } body url createdAt }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const listReviewThreadCommentsQuery = `query($threadId: ID!, $first: Int!, $cursor: String) {
+  node(id: $threadId) {
+    ... on PullRequestReviewThread {
+      comments(first: $first, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes { author { login } body url createdAt }
+      }
+    }
+  }
+}`
+
+const resolveReviewThreadMutation = `mutation($threadId: ID!) {
+  resolveReviewThread(input: {threadId: $threadId}) {
+    thread { id isResolved }
+  }
+}`
+
+const replyToReviewThreadMutation = `mutation($threadId: ID!, $body: String!) {
+  addPullRequestReviewThreadReply(input: {pullRequestReviewThreadId: $threadId, body: $body}) {
+    comment { id url }
+  }
+}`
+
+// ReviewThread is a review conversation on a pull request.
+type ReviewThread struct {
+	ID         string          `json:"id"`
+	IsResolved bool            `json:"isResolved"`
+	IsOutdated bool            `json:"isOutdated"`
+	Path       string          `json:"path"`
+	Line       int             `json:"line"`
+	Comments   []ReviewComment `json:"comments"`
+}
+
+// ReviewComment is a comment in a review thread.
+type ReviewComment struct {
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// pageInfo tells whether a GraphQL connection has more pages.
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// reviewCommentsPage is a page of the comments of a review thread in the GraphQL API.
+type reviewCommentsPage struct {
+	PageInfo pageInfo `json:"pageInfo"`
+	Nodes    []struct {
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		Body      string `json:"body"`
+		URL       string `json:"url"`
+		CreatedAt string `json:"createdAt"`
+	} `json:"nodes"`
+}
+
+// reviewThreadNode is a review thread in the GraphQL API.
+type reviewThreadNode struct {
+	ID         string             `json:"id"`
+	IsResolved bool               `json:"isResolved"`
+	IsOutdated bool               `json:"isOutdated"`
+	Path       string             `json:"path"`
+	Line       int                `json:"line"`
+	Comments   reviewCommentsPage `json:"comments"`
+}
+
+// reviewThreadsData is the data of the review threads query.
+type reviewThreadsData struct {
+	Repository struct {
+		PullRequest struct {
+			ReviewThreads struct {
+				PageInfo pageInfo           `json:"pageInfo"`
+				Nodes    []reviewThreadNode `json:"nodes"`
+			} `json:"reviewThreads"`
+		} `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+// reviewThreadCommentsData is the data of the review thread comments query.
+type reviewThreadCommentsData struct {
+	Node struct {
+		Comments reviewCommentsPage `json:"comments"`
+	} `json:"node"`
+}
+
+// ListReviewThreads returns every review thread of a pull request with all of its comments.
+func (c *Client) ListReviewThreads(ctx context.Context, owner, repo string, number int) ([]ReviewThread, error) {
+	threads := make([]ReviewThread, 0, reviewThreadsPageSize)
+	variables := map[string]any{
+		"owner":  owner,
+		"repo":   repo,
+		"number": number,
+		"first":  reviewThreadsPageSize,
+		"cursor": nil,
+	}
+
+	for {
+		var data reviewThreadsData
+
+		err := c.graphql(ctx, listReviewThreadsQuery, variables, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		page := data.Repository.PullRequest.ReviewThreads
+		for _, node := range page.Nodes {
+			thread := node.thread()
+
+			if node.Comments.PageInfo.HasNextPage {
+				thread.Comments, err = c.listReviewComments(ctx, node.ID, thread.Comments, node.Comments.PageInfo.EndCursor)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			threads = append(threads, thread)
+		}
+
+		if !page.PageInfo.HasNextPage {
+			return threads, nil
+		}
+
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+}
+
+// listReviewComments appends the comments of a review thread after cursor to comments.
+func (c *Client) listReviewComments(
+	ctx context.Context,
+	threadID string,
+	comments []ReviewComment,
+	cursor string,
+) ([]ReviewComment, error) {
+	variables := map[string]any{
+		"threadId": threadID,
+		"first":    reviewThreadsPageSize,
+		"cursor":   cursor,
+	}
+
+	for {
+		var data reviewThreadCommentsData
+
+		err := c.graphql(ctx, listReviewThreadCommentsQuery, variables, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		page := data.Node.Comments
+		comments = append(comments, page.comments()...)
+
+		if !page.PageInfo.HasNextPage {
+			return comments, nil
+		}
+
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+}
+
+// ResolveReviewThread marks a review thread as resolved.
+func (c *Client) ResolveReviewThread(ctx context.Context, threadID string) (*ReviewThread, error) {
+	var data struct {
+		ResolveReviewThread struct {
+			Thread reviewThreadNode `json:"thread"`
+		} `json:"resolveReviewThread"`
+	}
+
+	err := c.graphql(ctx, resolveReviewThreadMutation, map[string]any{"threadId": threadID}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := data.ResolveReviewThread.Thread.thread()
+
+	return &thread, nil
+}
+
+// ReplyToReviewThread adds a comment to a review thread.
+func (c *Client) ReplyToReviewThread(ctx context.Context, threadID, body string) (*ReviewComment, error) {
+	var data struct {
+		AddPullRequestReviewThreadReply struct {
+			Comment struct {
+				URL string `json:"url"`
+			} `json:"comment"`
+		} `json:"addPullRequestReviewThreadReply"`
+	}
+
+	err := c.graphql(ctx, replyToReviewThreadMutation, map[string]any{"threadId": threadID, "body": body}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewComment{
+		Author:    "",
+		Body:      body,
+		URL:       data.AddPullRequestReviewThreadReply.Comment.URL,
+		CreatedAt: "",
+	}, nil
+}
+
+// thread converts a GraphQL review thread with the first page of its comments.
+func (n reviewThreadNode) thread() ReviewThread {
+	return ReviewThread{
+		ID:         n.ID,
+		IsResolved: n.IsResolved,
+		IsOutdated: n.IsOutdated,
+		Path:       n.Path,
+		Line:       n.Line,
+		Comments:   n.Comments.comments(),
+	}
+}
+
+// comments converts a page of GraphQL review comments.
+func (p reviewCommentsPage) comments() []ReviewComment {
+	comments := make([]ReviewComment, 0, len(p.Nodes))
+	for _, comment := range p.Nodes {
+		comments = append(comments, ReviewComment{
+			Author:    comment.Author.Login,
+			Body:      comment.Body,
+			URL:       comment.URL,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+
+	return comments
+}
@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Diagnostic codes reported by Analyze.
+const (
+	CodeUnconditionalCycle = "UNCONDITIONAL_CYCLE"
+	CodeUnboundedLoop      = "UNBOUNDED_LOOP"
+	CodeUnreachableStep    = "UNREACHABLE_STEP"
+	CodeMissingEndStep     = "MISSING_END_STEP"
+	CodeMissingFallback    = "MISSING_FALLBACK"
+	CodeInvalidReference   = "INVALID_REFERENCE"
+)
+
+// Analyze reports problems in the shape of a flow's step graph:
+//   - loops no step leads out of, which run until the step limit (errors);
+//   - other loops without a step that sets MaxVisits (warnings);
+//   - steps that cannot be reached from the initial step (warnings);
+//   - steps that end the run without reaching an end step (warnings);
+//   - condition steps without a fallback next step (warnings).
+//
+// Loops with a MaxVisits step are explicitly bounded and not reported.
+func Analyze(flow *types.FlowDefinition) []types.Diagnostic {
+	graph := New(flow)
+
+	var found []types.Diagnostic
+
+	_, initialExists := flow.Steps[flow.InitialStep]
+	if flow.InitialStep != "" && !initialExists {
+		found = append(found, types.Diagnostic{
+			Code:     CodeInvalidReference,
+			Message:  fmt.Sprintf("initial step %q does not exist", flow.InitialStep),
+			StepID:   "",
+			Path:     "$.initialStep",
+			Severity: types.SeverityError,
+			Details:  map[string]any{"initialStep": flow.InitialStep},
+			Line:     0,
+			Column:   0,
+		})
+	}
+
+	found = append(found, graph.loopDiagnostics()...)
+
+	reached := graph.Reachable(flow.InitialStep)
+	inBranch := graph.joinedBranchSteps()
+
+	for _, stepID := range graph.steps {
+		step := flow.Steps[stepID]
+
+		if initialExists && !reached[stepID] {
+			found = append(found, warning(CodeUnreachableStep, stepID, "",
+				fmt.Sprintf("step %s cannot be reached from the initial step %s", stepID, flow.InitialStep)))
+		}
+
+		if step.Type != types.StepTypeEnd && step.Type != types.StepTypeCondition &&
+			step.Next == "" && len(graph.successors[stepID]) == 0 && !inBranch[stepID] {
+			found = append(found, warning(CodeMissingEndStep, stepID, "next",
+				fmt.Sprintf("step %s ends the run without reaching an end step", stepID)))
+		}
+
+		if step.Type == types.StepTypeCondition && step.Next == "" && !hasCatchAll(step.Conditions) {
+			found = append(found, warning(CodeMissingFallback, stepID, "next",
+				"condition step has no fallback next step, so the run fails when no condition holds"))
+		}
+	}
+
+	return found
+}
+
+// loopDiagnostics reports loops that are not bounded by MaxVisits.
+func (g *Graph) loopDiagnostics() []types.Diagnostic {
+	var found []types.Diagnostic
+
+	for _, cycle := range g.Cycles() {
+		if slices.ContainsFunc(cycle, func(stepID string) bool { return g.flow.Steps[stepID].MaxVisits > 0 }) {
+			continue
+		}
+
+		steps := strings.Join(cycle, ", ")
+
+		if !g.leaves(cycle) {
+			found = append(found, types.Diagnostic{
+				Code: CodeUnconditionalCycle,
+				Message: fmt.Sprintf("steps %s loop forever: no step leads out of the loop "+
+					"and none sets maxVisits", steps),
+				StepID:   cycle[0],
+				Path:     types.StepPath(cycle[0], ""),
+				Severity: types.SeverityError,
+				Details:  map[string]any{"stepId": cycle[0], "steps": cycle},
+				Line:     0,
+				Column:   0,
+			})
+
+			continue
+		}
+
+		diagnostic := warning(CodeUnboundedLoop, cycle[0], "",
+			fmt.Sprintf("steps %s form a loop and none of them sets maxVisits", steps))
+		diagnostic.Details = map[string]any{"stepId": cycle[0], "steps": cycle}
+		found = append(found, diagnostic)
+	}
+
+	return found
+}
+
+// leaves reports whether any step of cycle can hand over to a step outside it.
+func (g *Graph) leaves(cycle []string) bool {
+	for _, stepID := range cycle {
+		for _, successor := range g.successors[stepID] {
+			if !slices.Contains(cycle, successor) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// joinedBranchSteps returns the steps inside the branches of parallel steps
+// with a join step. Branches end where their steps have no next step and
+// the run continues at the join, so those steps do not end the run.
+func (g *Graph) joinedBranchSteps() map[string]bool {
+	inBranch := make(map[string]bool)
+
+	for _, stepID := range g.steps {
+		step := g.flow.Steps[stepID]
+		if step.Type != types.StepTypeParallel || step.Parallel == nil || step.Next == "" {
+			continue
+		}
+
+		pending := slices.Clone(step.Parallel.Branches)
+		for len(pending) > 0 {
+			current := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			if current == step.Next || inBranch[current] {
+				continue
+			}
+
+			inBranch[current] = true
+			pending = append(pending, g.successors[current]...)
+		}
+	}
+
+	return inBranch
+}
+
+// hasCatchAll reports whether a condition always holds.
+func hasCatchAll(conditions []types.ConditionConfig) bool {
+	return slices.ContainsFunc(conditions, func(condition types.ConditionConfig) bool {
+		return strings.TrimSpace(condition.Expression) == "true"
+	})
+}
+
+// warning returns a warning diagnostic for field of a step.
+func warning(code, stepID, field, message string) types.Diagnostic {
+	return types.Diagnostic{
+		Code:     code,
+		Message:  message,
+		StepID:   stepID,
+		Path:     types.StepPath(stepID, field),
+		Severity: types.SeverityWarning,
+		Details:  map[string]any{"stepId": stepID},
+		Line:     0,
+		Column:   0,
+	}
+}
@@ -0,0 +1,217 @@
+// Package graph analyzes the step graph of a flow definition.
+package graph
+
+import (
+	"slices"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// EdgeKind is how a step hands over to the next one.
+type EdgeKind string
+
+const (
+	// EdgeNext follows a step's next reference; for a condition step it is
+	// the fallback taken when no condition holds.
+	EdgeNext EdgeKind = "next"
+	// EdgeCondition follows a condition of a condition step.
+	EdgeCondition EdgeKind = "condition"
+	// EdgeBranch starts a branch of a parallel step.
+	EdgeBranch EdgeKind = "branch"
+)
+
+// Edge is a possible transition between two steps.
+type Edge struct {
+	From string
+	To   string
+	Kind EdgeKind
+	// Label is the condition expression of EdgeCondition edges.
+	Label string
+}
+
+// Graph is the step graph of a flow. Edges to steps the flow does not
+// define are left out; validation reports them.
+type Graph struct {
+	flow       *types.FlowDefinition
+	steps      []string
+	edges      []Edge
+	successors map[string][]string
+}
+
+// New builds the step graph of flow.
+func New(flow *types.FlowDefinition) *Graph {
+	graph := &Graph{
+		flow:       flow,
+		steps:      make([]string, 0, len(flow.Steps)),
+		edges:      nil,
+		successors: make(map[string][]string, len(flow.Steps)),
+	}
+
+	for stepID := range flow.Steps {
+		graph.steps = append(graph.steps, stepID)
+	}
+
+	slices.Sort(graph.steps)
+
+	for _, stepID := range graph.steps {
+		graph.addEdges(stepID, flow.Steps[stepID])
+	}
+
+	return graph
+}
+
+// addEdges adds the outgoing edges of a step in the order the engine considers them.
+func (g *Graph) addEdges(stepID string, step types.Step) {
+	switch step.Type {
+	case types.StepTypeEnd:
+		return
+	case types.StepTypeCondition:
+		for _, condition := range step.Conditions {
+			g.addEdge(Edge{From: stepID, To: condition.Next, Kind: EdgeCondition, Label: condition.Expression})
+		}
+	case types.StepTypeParallel:
+		if step.Parallel != nil {
+			for _, branch := range step.Parallel.Branches {
+				g.addEdge(Edge{From: stepID, To: branch, Kind: EdgeBranch, Label: ""})
+			}
+		}
+	default:
+	}
+
+	g.addEdge(Edge{From: stepID, To: step.Next, Kind: EdgeNext, Label: ""})
+}
+
+// addEdge adds an edge whose target step exists.
+func (g *Graph) addEdge(edge Edge) {
+	if _, exists := g.flow.Steps[edge.To]; !exists {
+		return
+	}
+
+	g.edges = append(g.edges, edge)
+
+	if !slices.Contains(g.successors[edge.From], edge.To) {
+		g.successors[edge.From] = append(g.successors[edge.From], edge.To)
+	}
+}
+
+// Steps returns the step IDs in order.
+func (g *Graph) Steps() []string {
+	return g.steps
+}
+
+// Edges returns every edge, grouped by source step in step order.
+func (g *Graph) Edges() []Edge {
+	return g.edges
+}
+
+// Successors returns the steps a step can hand over to.
+func (g *Graph) Successors(stepID string) []string {
+	return g.successors[stepID]
+}
+
+// Reachable returns the steps reachable from the given steps, including them.
+func (g *Graph) Reachable(from ...string) map[string]bool {
+	reached := make(map[string]bool, len(g.steps))
+	pending := make([]string, 0, len(from))
+
+	for _, stepID := range from {
+		if _, exists := g.flow.Steps[stepID]; exists {
+			pending = append(pending, stepID)
+		}
+	}
+
+	for len(pending) > 0 {
+		stepID := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if reached[stepID] {
+			continue
+		}
+
+		reached[stepID] = true
+		pending = append(pending, g.successors[stepID]...)
+	}
+
+	return reached
+}
+
+// Cycles returns the strongly connected components of the graph that
+// contain a cycle: every step in one can reach every other. Steps within a
+// component and the components themselves are in step order.
+func (g *Graph) Cycles() [][]string {
+	search := &tarjan{
+		graph:   g,
+		index:   make(map[string]int, len(g.steps)),
+		lowLink: make(map[string]int, len(g.steps)),
+		onStack: make(map[string]bool, len(g.steps)),
+		stack:   nil,
+		next:    0,
+		cycles:  nil,
+	}
+
+	for _, stepID := range g.steps {
+		if _, visited := search.index[stepID]; !visited {
+			search.visit(stepID)
+		}
+	}
+
+	for _, cycle := range search.cycles {
+		slices.Sort(cycle)
+	}
+
+	slices.SortFunc(search.cycles, func(a, b []string) int {
+		return slices.Compare(a, b)
+	})
+
+	return search.cycles
+}
+
+// tarjan holds the state of Tarjan's strongly connected components algorithm.
+type tarjan struct {
+	graph   *Graph
+	index   map[string]int
+	lowLink map[string]int
+	onStack map[string]bool
+	stack   []string
+	next    int
+	cycles  [][]string
+}
+
+// visit assigns stepID its index and collects the component it roots, if any.
+func (t *tarjan) visit(stepID string) {
+	t.index[stepID] = t.next
+	t.lowLink[stepID] = t.next
+	t.next++
+	t.stack = append(t.stack, stepID)
+	t.onStack[stepID] = true
+
+	for _, successor := range t.graph.successors[stepID] {
+		if _, visited := t.index[successor]; !visited {
+			t.visit(successor)
+			t.lowLink[stepID] = min(t.lowLink[stepID], t.lowLink[successor])
+		} else if t.onStack[successor] {
+			t.lowLink[stepID] = min(t.lowLink[stepID], t.index[successor])
+		}
+	}
+
+	if t.lowLink[stepID] != t.index[stepID] {
+		return
+	}
+
+	var component []string
+
+	for {
+		top := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[top] = false
+		component = append(component, top)
+
+		if top == stepID {
+			break
+		}
+	}
+
+	if len(component) > 1 || slices.Contains(t.graph.successors[stepID], stepID) {
+		t.cycles = append(t.cycles, component)
+	}
+}
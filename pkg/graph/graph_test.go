@@ -0,0 +1,174 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package graph_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/graph"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+func parseFlow(t *testing.T, source string) *types.FlowDefinition {
+	t.Helper()
+
+	var flow types.FlowDefinition
+
+	require.NoError(t, json.Unmarshal([]byte(source), &flow))
+
+	return &flow
+}
+
+// codes returns the code and path of every diagnostic.
+func codes(diagnostics []types.Diagnostic) []string {
+	found := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		found = append(found, diagnostic.Code+" "+diagnostic.Path)
+	}
+
+	return found
+}
+
+func TestGraph_EdgesAndCycles(t *testing.T) {
+	t.Parallel()
+
+	flow := parseFlow(t, `{
+  "id": "review",
+  "name": "Review",
+  "initialStep": "fan",
+  "steps": {
+    "fan": {"type": "parallel", "parallel": {"branches": ["lint", "test"]}, "next": "check"},
+    "lint": {"type": "tool", "tool": {"name": "lint"}, "next": "check"},
+    "test": {"type": "tool", "tool": {"name": "test"}, "next": "missing"},
+    "check": {
+      "type": "condition",
+      "conditions": [{"expression": "steps.lint.output.ok", "next": "done"}],
+      "next": "fix"
+    },
+    "fix": {"type": "prompt", "prompt": {"template": "Fix it"}, "next": "check"},
+    "done": {"type": "end", "next": "fan"}
+  }
+}`)
+
+	g := graph.New(flow)
+
+	assert.Equal(t, []string{"check", "done", "fan", "fix", "lint", "test"}, g.Steps())
+	assert.Equal(t, []graph.Edge{
+		{From: "check", To: "done", Kind: graph.EdgeCondition, Label: "steps.lint.output.ok"},
+		{From: "check", To: "fix", Kind: graph.EdgeNext},
+		{From: "fan", To: "lint", Kind: graph.EdgeBranch},
+		{From: "fan", To: "test", Kind: graph.EdgeBranch},
+		{From: "fan", To: "check", Kind: graph.EdgeNext},
+		{From: "fix", To: "check", Kind: graph.EdgeNext},
+		{From: "lint", To: "check", Kind: graph.EdgeNext},
+	}, g.Edges())
+	assert.Equal(t, []string{"lint", "test", "check"}, g.Successors("fan"))
+	assert.Equal(t, [][]string{{"check", "fix"}}, g.Cycles())
+	assert.Equal(t, map[string]bool{"check": true, "done": true, "fix": true}, g.Reachable("fix"))
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		flow     string
+		expected []string
+	}{
+		{
+			name: "circular reference",
+			flow: `{"initialStep": "step1", "steps": {
+  "step1": {"type": "prompt", "next": "step2"},
+  "step2": {"type": "prompt", "next": "step1"}
+}}`,
+			expected: []string{"UNCONDITIONAL_CYCLE $.steps.step1"},
+		},
+		{
+			name: "condition that always loops back",
+			flow: `{"initialStep": "ask", "steps": {
+  "ask": {"type": "prompt", "next": "check"},
+  "check": {"type": "condition", "conditions": [{"expression": "true", "next": "ask"}]}
+}}`,
+			expected: []string{"UNCONDITIONAL_CYCLE $.steps.ask"},
+		},
+		{
+			name: "retry loop with an exit",
+			flow: `{"initialStep": "ask", "steps": {
+  "ask": {"type": "prompt", "next": "check"},
+  "check": {"type": "condition", "conditions": [{"expression": "steps.ask.output == 'ok'", "next": "done"}], "next": "ask"},
+  "done": {"type": "end"}
+}}`,
+			expected: []string{"UNBOUNDED_LOOP $.steps.ask"},
+		},
+		{
+			name: "bounded loops",
+			flow: `{"initialStep": "ask", "steps": {
+  "ask": {"type": "prompt", "next": "check", "maxVisits": 3},
+  "check": {"type": "condition", "conditions": [{"expression": "true", "next": "ask"}]}
+}}`,
+			expected: []string{},
+		},
+		{
+			name: "unreachable steps and missing end steps",
+			flow: `{"initialStep": "start", "steps": {
+  "start": {"type": "prompt"},
+  "orphan": {"type": "prompt", "next": "done"},
+  "done": {"type": "end"}
+}}`,
+			expected: []string{
+				"UNREACHABLE_STEP $.steps.done",
+				"UNREACHABLE_STEP $.steps.orphan",
+				"MISSING_END_STEP $.steps.start.next",
+			},
+		},
+		{
+			name: "condition without fallback",
+			flow: `{"initialStep": "check", "steps": {
+  "check": {"type": "condition", "conditions": [{"expression": "vars.env == 'prod'", "next": "done"}]},
+  "done": {"type": "end"}
+}}`,
+			expected: []string{"MISSING_FALLBACK $.steps.check.next"},
+		},
+		{
+			name: "branches end at the join",
+			flow: `{"initialStep": "fan", "steps": {
+  "fan": {"type": "parallel", "parallel": {"branches": ["a", "b"]}, "next": "done"},
+  "a": {"type": "prompt"},
+  "b": {"type": "prompt", "next": "done"},
+  "done": {"type": "end"}
+}}`,
+			expected: []string{},
+		},
+		{
+			name:     "missing initial step",
+			flow:     `{"initialStep": "nowhere", "steps": {"done": {"type": "end"}}}`,
+			expected: []string{"INVALID_REFERENCE $.initialStep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, codes(graph.Analyze(parseFlow(t, tt.flow))))
+		})
+	}
+}
+
+func TestAnalyze_CycleSeverity(t *testing.T) {
+	t.Parallel()
+
+	diagnostics := graph.Analyze(parseFlow(t, `{"initialStep": "a", "steps": {
+  "a": {"type": "prompt", "next": "b"},
+  "b": {"type": "prompt", "next": "a"}
+}}`))
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, types.SeverityError, diagnostics[0].Severity)
+	assert.Equal(t, "a", diagnostics[0].StepID)
+	assert.Equal(t, []string{"a", "b"}, diagnostics[0].Details.(map[string]any)["steps"])
+	require.Error(t, types.FirstError(diagnostics))
+}
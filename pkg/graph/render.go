@@ -0,0 +1,333 @@
+package graph
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Formats supported by Render.
+const (
+	FormatMermaid  = "mermaid"
+	FormatDOT      = "dot"
+	FormatPlantUML = "plantuml"
+)
+
+// ErrUnknownFormat is returned when rendering to a format Render does not support.
+var ErrUnknownFormat = errors.New("unknown graph format")
+
+// statusColors are the fill colors of steps by the status a run left them in.
+var statusColors = map[types.StepStatus]string{
+	types.StepStatusCompleted: "#d4edda",
+	types.StepStatusFailed:    "#f8d7da",
+	types.StepStatusPending:   "#fff3cd",
+	types.StepStatusRunning:   "#cce5ff",
+	types.StepStatusSkipped:   "#e2e3e5",
+	types.StepStatusCanceled:  "#ffe5d0",
+}
+
+// Overlay is a run drawn over the graph: the status every step it ran
+// ended with and the order the steps last ran in.
+type Overlay struct {
+	Status  map[string]types.StepStatus
+	Visited []string
+}
+
+// RunOverlay builds the overlay of a run from its step results.
+func RunOverlay(execCtx *types.ExecutionContext) *Overlay {
+	results := make([]types.StepResult, 0, len(execCtx.StepResults))
+	for stepID, result := range execCtx.StepResults {
+		result.StepID = stepID
+		results = append(results, result)
+	}
+
+	slices.SortFunc(results, func(a, b types.StepResult) int {
+		return cmp.Or(a.StartTime.Compare(b.StartTime), strings.Compare(a.StepID, b.StepID))
+	})
+
+	overlay := &Overlay{
+		Status:  make(map[string]types.StepStatus, len(results)),
+		Visited: make([]string, 0, len(results)),
+	}
+
+	for _, result := range results {
+		overlay.Status[result.StepID] = result.Status
+		overlay.Visited = append(overlay.Visited, result.StepID)
+	}
+
+	return overlay
+}
+
+// taken reports whether the run went from one step straight to the other.
+func (o *Overlay) taken(edge Edge) bool {
+	if o == nil {
+		return false
+	}
+
+	for index := 1; index < len(o.Visited); index++ {
+		if o.Visited[index-1] == edge.From && o.Visited[index] == edge.To {
+			return true
+		}
+	}
+
+	return false
+}
+
+// status returns the status of a step in the run, or "" when the run did not reach it.
+func (o *Overlay) status(stepID string) types.StepStatus {
+	if o == nil {
+		return ""
+	}
+
+	return o.Status[stepID]
+}
+
+// Render writes the step graph of flow as a diagram in format. The initial
+// step and end steps are highlighted; overlay, when not nil, colors the
+// steps of a run by their status and emphasizes the edges it took.
+func Render(w io.Writer, flow *types.FlowDefinition, format string, overlay *Overlay) error {
+	diagram := &diagram{
+		flow:    flow,
+		graph:   New(flow),
+		overlay: overlay,
+		out:     &strings.Builder{},
+	}
+
+	switch format {
+	case FormatMermaid:
+		diagram.mermaid()
+	case FormatDOT:
+		diagram.dot()
+	case FormatPlantUML:
+		diagram.plantUML()
+	default:
+		return fmt.Errorf("%w %q: use mermaid, dot or plantuml", ErrUnknownFormat, format)
+	}
+
+	_, err := io.WriteString(w, diagram.out.String())
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+
+	return nil
+}
+
+// diagram renders one graph.
+type diagram struct {
+	flow    *types.FlowDefinition
+	graph   *Graph
+	overlay *Overlay
+	out     *strings.Builder
+}
+
+// printf appends a formatted line to the diagram.
+func (d *diagram) printf(format string, args ...any) {
+	fmt.Fprintf(d.out, format+"\n", args...)
+}
+
+// alias returns the node name of a step, safe in every format.
+func (d *diagram) alias(stepID string) string {
+	return "s" + strconv.Itoa(slices.Index(d.graph.steps, stepID))
+}
+
+// label returns the text shown in the node of a step.
+func (d *diagram) label(stepID string) string {
+	return stepID + "\n" + string(d.flow.Steps[stepID].Type)
+}
+
+// edgeLabel returns the text shown on an edge.
+func (d *diagram) edgeLabel(edge Edge) string {
+	switch {
+	case edge.Kind == EdgeCondition:
+		return edge.Label
+	case edge.Kind == EdgeBranch:
+		return "branch"
+	case d.flow.Steps[edge.From].Type == types.StepTypeCondition:
+		return "else"
+	default:
+		return ""
+	}
+}
+
+// isEnd reports whether a step is an end step.
+func (d *diagram) isEnd(stepID string) bool {
+	return d.flow.Steps[stepID].Type == types.StepTypeEnd
+}
+
+// mermaid renders a Mermaid flowchart.
+func (d *diagram) mermaid() {
+	d.printf("flowchart TD")
+
+	for _, stepID := range d.graph.steps {
+		open, closing := "[", "]"
+
+		switch {
+		case d.isEnd(stepID):
+			open, closing = "([", "])"
+		case d.flow.Steps[stepID].Type == types.StepTypeCondition:
+			open, closing = "{", "}"
+		case d.flow.Steps[stepID].Type == types.StepTypeParallel:
+			open, closing = "[[", "]]"
+		}
+
+		d.printf("    %s%s\"%s\"%s", d.alias(stepID), open, mermaidText(d.label(stepID)), closing)
+	}
+
+	for _, edge := range d.graph.edges {
+		arrow := "-->"
+
+		switch {
+		case d.overlay.taken(edge):
+			arrow = "==>"
+		case edge.Kind == EdgeBranch:
+			arrow = "-.->"
+		}
+
+		if label := d.edgeLabel(edge); label != "" {
+			arrow += "|\"" + mermaidText(label) + "\"|"
+		}
+
+		d.printf("    %s %s %s", d.alias(edge.From), arrow, d.alias(edge.To))
+	}
+
+	d.printf("    classDef initial stroke-width:3px")
+	d.printf("    classDef terminal stroke-width:3px,stroke-dasharray:4")
+
+	for _, status := range slices.Sorted(maps.Keys(statusColors)) {
+		d.printf("    classDef %s fill:%s", status, statusColors[status])
+	}
+
+	for _, stepID := range d.graph.steps {
+		if stepID == d.flow.InitialStep {
+			d.printf("    class %s initial", d.alias(stepID))
+		}
+
+		if d.isEnd(stepID) {
+			d.printf("    class %s terminal", d.alias(stepID))
+		}
+
+		if status := d.overlay.status(stepID); statusColors[status] != "" {
+			d.printf("    class %s %s", d.alias(stepID), status)
+		}
+	}
+}
+
+// dot renders a Graphviz digraph.
+func (d *diagram) dot() {
+	d.printf("digraph %s {", quote(d.flow.ID))
+	d.printf("    rankdir=TB;")
+	d.printf("    node [shape=box, style=rounded];")
+
+	if _, exists := d.flow.Steps[d.flow.InitialStep]; exists {
+		d.printf("    start [shape=point, width=0.2];")
+		d.printf("    start -> %s;", d.alias(d.flow.InitialStep))
+	}
+
+	for _, stepID := range d.graph.steps {
+		attributes := []string{"label=" + quote(d.label(stepID))}
+
+		switch {
+		case d.isEnd(stepID):
+			attributes = append(attributes, "shape=oval", "peripheries=2")
+		case d.flow.Steps[stepID].Type == types.StepTypeCondition:
+			attributes = append(attributes, "shape=diamond", "style=solid")
+		}
+
+		if stepID == d.flow.InitialStep {
+			attributes = append(attributes, "penwidth=3")
+		}
+
+		if color := statusColors[d.overlay.status(stepID)]; color != "" {
+			attributes = append(attributes, `style="rounded,filled"`, "fillcolor="+quote(color))
+		}
+
+		d.printf("    %s [%s];", d.alias(stepID), strings.Join(attributes, ", "))
+	}
+
+	for _, edge := range d.graph.edges {
+		var attributes []string
+
+		if label := d.edgeLabel(edge); label != "" {
+			attributes = append(attributes, "label="+quote(label))
+		}
+
+		if edge.Kind == EdgeBranch {
+			attributes = append(attributes, "style=dashed")
+		}
+
+		if d.overlay.taken(edge) {
+			attributes = append(attributes, "penwidth=3")
+		}
+
+		if len(attributes) == 0 {
+			d.printf("    %s -> %s;", d.alias(edge.From), d.alias(edge.To))
+		} else {
+			d.printf("    %s -> %s [%s];", d.alias(edge.From), d.alias(edge.To), strings.Join(attributes, ", "))
+		}
+	}
+
+	d.printf("}")
+}
+
+// plantUML renders a PlantUML state diagram.
+func (d *diagram) plantUML() {
+	d.printf("@startuml")
+	d.printf("hide empty description")
+
+	for _, stepID := range d.graph.steps {
+		color := ""
+		if fill := statusColors[d.overlay.status(stepID)]; fill != "" {
+			color = " " + fill
+		}
+
+		d.printf("state %s as %s%s",
+			quote(d.label(stepID)), d.alias(stepID), color)
+	}
+
+	if _, exists := d.flow.Steps[d.flow.InitialStep]; exists {
+		d.printf("[*] --> %s", d.alias(d.flow.InitialStep))
+	}
+
+	for _, edge := range d.graph.edges {
+		arrow := "-->"
+
+		switch {
+		case d.overlay.taken(edge):
+			arrow = "-[bold]->"
+		case edge.Kind == EdgeBranch:
+			arrow = "-[dashed]->"
+		}
+
+		line := d.alias(edge.From) + " " + arrow + " " + d.alias(edge.To)
+		if label := d.edgeLabel(edge); label != "" {
+			line += " : " + label
+		}
+
+		d.printf("%s", line)
+	}
+
+	for _, stepID := range d.graph.steps {
+		if d.isEnd(stepID) {
+			d.printf("%s --> [*]", d.alias(stepID))
+		}
+	}
+
+	d.printf("@enduml")
+}
+
+// mermaidText escapes text for a quoted Mermaid label.
+func mermaidText(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(text)
+}
+
+// quote quotes text for DOT and PlantUML, where \n starts a new line.
+func quote(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text) + `"`
+}
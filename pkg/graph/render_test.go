@@ -0,0 +1,136 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package graph_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/graph"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const retryFlowJSON = `{
+  "id": "retry",
+  "name": "Retry",
+  "initialStep": "ask",
+  "steps": {
+    "ask": {"type": "prompt", "prompt": {"template": "Hi"}, "next": "check", "maxVisits": 3},
+    "check": {
+      "type": "condition",
+      "conditions": [{"expression": "steps.ask.output == \"ok\"", "next": "done"}],
+      "next": "ask"
+    },
+    "done": {"type": "end"}
+  }
+}`
+
+func render(t *testing.T, format string, overlay *graph.Overlay) string {
+	t.Helper()
+
+	var out strings.Builder
+
+	require.NoError(t, graph.Render(&out, parseFlow(t, retryFlowJSON), format, overlay))
+
+	return out.String()
+}
+
+func TestRender_Mermaid(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `flowchart TD
+    s0["ask<br/>prompt"]
+    s1{"check<br/>condition"}
+    s2(["done<br/>end"])
+    s0 --> s1
+    s1 -->|"steps.ask.output == #quot;ok#quot;"| s2
+    s1 -->|"else"| s0
+    classDef initial stroke-width:3px
+    classDef terminal stroke-width:3px,stroke-dasharray:4
+    classDef canceled fill:#ffe5d0
+    classDef completed fill:#d4edda
+    classDef failed fill:#f8d7da
+    classDef pending fill:#fff3cd
+    classDef running fill:#cce5ff
+    classDef skipped fill:#e2e3e5
+    class s0 initial
+    class s2 terminal
+`, render(t, graph.FormatMermaid, nil))
+}
+
+func TestRender_DOT(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `digraph "retry" {
+    rankdir=TB;
+    node [shape=box, style=rounded];
+    start [shape=point, width=0.2];
+    start -> s0;
+    s0 [label="ask\nprompt", penwidth=3];
+    s1 [label="check\ncondition", shape=diamond, style=solid];
+    s2 [label="done\nend", shape=oval, peripheries=2];
+    s0 -> s1;
+    s1 -> s2 [label="steps.ask.output == \"ok\""];
+    s1 -> s0 [label="else"];
+}
+`, render(t, graph.FormatDOT, nil))
+}
+
+func TestRender_PlantUML(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `@startuml
+hide empty description
+state "ask\nprompt" as s0
+state "check\ncondition" as s1
+state "done\nend" as s2
+[*] --> s0
+s0 --> s1
+s1 --> s2 : steps.ask.output == "ok"
+s1 --> s0 : else
+s2 --> [*]
+@enduml
+`, render(t, graph.FormatPlantUML, nil))
+}
+
+func TestRender_RunOverlay(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2025, 1, 10, 9, 30, 0, 0, time.UTC)
+	overlay := graph.RunOverlay(&types.ExecutionContext{
+		StepResults: map[string]types.StepResult{
+			"check": {Status: types.StepStatusFailed, StartTime: started.Add(time.Second)},
+			"ask":   {Status: types.StepStatusCompleted, StartTime: started},
+		},
+	})
+
+	assert.Equal(t, []string{"ask", "check"}, overlay.Visited)
+
+	mermaid := render(t, graph.FormatMermaid, overlay)
+	assert.Contains(t, mermaid, "    s0 ==> s1\n")
+	assert.Contains(t, mermaid, `    s1 -->|"else"| s0`)
+	assert.Contains(t, mermaid, "    class s0 completed\n")
+	assert.Contains(t, mermaid, "    class s1 failed\n")
+	assert.NotContains(t, mermaid, "class s2 completed")
+
+	dot := render(t, graph.FormatDOT, overlay)
+	assert.Contains(t, dot, `s1 [label="check\ncondition", shape=diamond, style=solid, style="rounded,filled", fillcolor="#f8d7da"];`)
+	assert.Contains(t, dot, "    s0 -> s1 [penwidth=3];\n")
+
+	plantUML := render(t, graph.FormatPlantUML, overlay)
+	assert.Contains(t, plantUML, `state "ask\nprompt" as s0 #d4edda`)
+	assert.Contains(t, plantUML, "s0 -[bold]-> s1\n")
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+
+	err := graph.Render(&out, parseFlow(t, retryFlowJSON), "svg", nil)
+	require.ErrorIs(t, err, graph.ErrUnknownFormat)
+	assert.Empty(t, out.String())
+}
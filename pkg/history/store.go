@@ -0,0 +1,247 @@
+// Package history keeps a record of every flow run, so past runs can be
+// listed and inspected without running them again.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const (
+	dirPerms      = 0o750
+	fileExtension = ".json"
+)
+
+var (
+	// ErrNotFound is returned when no run was recorded for a session.
+	ErrNotFound = errors.New("run not found")
+
+	// ErrInvalidSessionID is returned for session IDs that are empty or contain path separators.
+	ErrInvalidSessionID = errors.New("invalid session ID")
+
+	errIncompleteRun = errors.New("flow and execution are required")
+)
+
+// Run is the record of a run: a summary of its outcome, the flow as it was
+// executed and its final execution context with every step result.
+type Run struct {
+	SessionID  string                  `json:"sessionId"`
+	FlowID     string                  `json:"flowId"`
+	FlowName   string                  `json:"flowName"`
+	Status     types.ExecutionStatus   `json:"status"`
+	StartTime  time.Time               `json:"startTime"`
+	EndTime    time.Time               `json:"endTime"`
+	Duration   time.Duration           `json:"duration"`
+	Steps      int                     `json:"steps"`
+	TokensUsed int                     `json:"tokensUsed"`
+	Cost       float64                 `json:"cost"`
+	Flow       *types.FlowDefinition   `json:"flow,omitempty"`
+	Execution  *types.ExecutionContext `json:"execution,omitempty"`
+}
+
+// NewRun summarizes the execution context of a run of flow. Tokens and cost
+// are summed over the step results, which include the steps of parallel branches.
+func NewRun(flow *types.FlowDefinition, execCtx *types.ExecutionContext) *Run {
+	run := &Run{
+		SessionID:  execCtx.SessionID,
+		FlowID:     execCtx.FlowID,
+		FlowName:   flow.Name,
+		Status:     execCtx.Status,
+		StartTime:  execCtx.StartTime,
+		EndTime:    execCtx.LastUpdate,
+		Duration:   execCtx.LastUpdate.Sub(execCtx.StartTime),
+		Steps:      len(execCtx.StepResults),
+		TokensUsed: 0,
+		Cost:       0,
+		Flow:       flow,
+		Execution:  execCtx,
+	}
+
+	for _, result := range execCtx.StepResults {
+		run.TokensUsed += result.TokensUsed
+		run.Cost += result.Cost
+	}
+
+	return run
+}
+
+// Filter selects recorded runs. Zero fields match every run.
+type Filter struct {
+	FlowID string
+	Status types.ExecutionStatus
+	// Since and Until bound the start time of the runs.
+	Since time.Time
+	Until time.Time
+}
+
+// Matches reports whether run is selected by the filter.
+func (f Filter) Matches(run *Run) bool {
+	switch {
+	case f.FlowID != "" && run.FlowID != f.FlowID:
+		return false
+	case f.Status != "" && run.Status != f.Status:
+		return false
+	case !f.Since.IsZero() && run.StartTime.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !run.StartTime.Before(f.Until):
+		return false
+	default:
+		return true
+	}
+}
+
+// Store keeps one record file per session in a directory. Resuming a run
+// replaces its record.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store that records runs in dir.
+// The directory is created on the first record.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory runs are recorded in.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Record writes the record of a run, replacing the previous record of its
+// session. Like checkpoints, it is written to a temporary file first so a
+// crash never leaves a partially written record behind.
+func (s *Store) Record(flow *types.FlowDefinition, execCtx *types.ExecutionContext) error {
+	path, err := s.path(execCtx.SessionID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(NewRun(flow, execCtx), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	err = os.MkdirAll(s.dir, dirPerms)
+	if err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, execCtx.SessionID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create run file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write run: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to replace run: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the record of a session.
+func (s *Store) Load(sessionID string) (*Run, error) {
+	path, err := s.path(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := readRun(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, sessionID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run %s: %w", sessionID, err)
+	}
+
+	return run, nil
+}
+
+// List returns the recorded runs matching filter, most recent first. A
+// store whose directory does not exist yet has no runs.
+func (s *Store) List(filter Filter) ([]*Run, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []*Run{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run directory: %w", err)
+	}
+
+	runs := []*Run{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExtension {
+			continue
+		}
+
+		run, err := readRun(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read run %s: %w", strings.TrimSuffix(entry.Name(), fileExtension), err)
+		}
+
+		if filter.Matches(run) {
+			runs = append(runs, run)
+		}
+	}
+
+	slices.SortFunc(runs, func(a, b *Run) int {
+		return b.StartTime.Compare(a.StartTime)
+	})
+
+	return runs, nil
+}
+
+// readRun reads and decodes a record file.
+func readRun(path string) (*Run, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run file: %w", err)
+	}
+
+	var run Run
+
+	err = json.Unmarshal(data, &run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse run file: %w", err)
+	}
+
+	if run.Flow == nil || run.Execution == nil {
+		return nil, fmt.Errorf("failed to parse run file: %w", errIncompleteRun)
+	}
+
+	return &run, nil
+}
+
+// path returns the record file of a session.
+func (s *Store) path(sessionID string) (string, error) {
+	if sessionID == "" || strings.ContainsAny(sessionID, "/\\") || strings.Contains(sessionID, "..") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSessionID, sessionID)
+	}
+
+	return filepath.Join(s.dir, sessionID+fileExtension), nil
+}
@@ -0,0 +1,151 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/history"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var start = time.Date(2025, 1, 7, 9, 30, 0, 0, time.UTC)
+
+func newRun(flowID, sessionID string, status types.ExecutionStatus, startTime time.Time) (
+	*types.FlowDefinition, *types.ExecutionContext,
+) {
+	flow := &types.FlowDefinition{
+		ID:          flowID,
+		Name:        "Review",
+		InitialStep: "ask",
+		Steps: map[string]types.Step{
+			"ask":  {Type: types.StepTypePrompt, Prompt: &types.PromptConfig{Template: "Review"}, Next: "done"},
+			"done": {Type: types.StepTypeEnd},
+		},
+	}
+	execCtx := &types.ExecutionContext{
+		FlowID:    flowID,
+		SessionID: sessionID,
+		StepResults: map[string]types.StepResult{
+			"ask":  {StepID: "ask", Status: types.StepStatusCompleted, Output: "LGTM", TokensUsed: 120, Cost: 0.002},
+			"done": {StepID: "done", Status: types.StepStatusCompleted, TokensUsed: 30, Cost: 0.001},
+		},
+		StartTime:  startTime,
+		LastUpdate: startTime.Add(3 * time.Second),
+		Status:     status,
+	}
+
+	return flow, execCtx
+}
+
+func TestStore_RecordAndLoad(t *testing.T) {
+	t.Parallel()
+
+	store := history.NewStore(filepath.Join(t.TempDir(), "runs"))
+
+	flow, execCtx := newRun("review", "session-1", types.StatusCompleted, start)
+	require.NoError(t, store.Record(flow, execCtx))
+
+	run, err := store.Load("session-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "review", run.FlowID)
+	assert.Equal(t, "Review", run.FlowName)
+	assert.Equal(t, types.StatusCompleted, run.Status)
+	assert.True(t, run.StartTime.Equal(start))
+	assert.Equal(t, 3*time.Second, run.Duration)
+	assert.Equal(t, 2, run.Steps)
+	assert.Equal(t, 150, run.TokensUsed)
+	assert.InDelta(t, 0.003, run.Cost, 1e-9)
+	assert.Equal(t, "LGTM", run.Execution.StepResults["ask"].Output)
+	assert.Equal(t, types.StepTypePrompt, run.Flow.Steps["ask"].Type)
+
+	execCtx.Status = types.StatusFailed
+	require.NoError(t, store.Record(flow, execCtx))
+
+	run, err = store.Load("session-1")
+	require.NoError(t, err)
+	assert.Equal(t, types.StatusFailed, run.Status)
+}
+
+func TestStore_LoadErrors(t *testing.T) {
+	t.Parallel()
+
+	store := history.NewStore(t.TempDir())
+
+	_, err := store.Load("missing")
+	require.ErrorIs(t, err, history.ErrNotFound)
+
+	_, err = store.Load("../escape")
+	require.ErrorIs(t, err, history.ErrInvalidSessionID)
+
+	require.NoError(t, os.WriteFile(filepath.Join(store.Dir(), "broken.json"), []byte("{}"), 0o600))
+
+	_, err = store.Load("broken")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "flow and execution are required")
+}
+
+func TestStore_List(t *testing.T) {
+	t.Parallel()
+
+	store := history.NewStore(t.TempDir())
+
+	for _, run := range []struct {
+		flowID, sessionID string
+		status            types.ExecutionStatus
+		start             time.Time
+	}{
+		{"review", "monday", types.StatusCompleted, start.Add(-24 * time.Hour)},
+		{"review", "tuesday", types.StatusFailed, start},
+		{"triage", "tuesday-later", types.StatusCompleted, start.Add(time.Hour)},
+		{"review", "wednesday", types.StatusFailed, start.Add(24 * time.Hour)},
+	} {
+		flow, execCtx := newRun(run.flowID, run.sessionID, run.status, run.start)
+		require.NoError(t, store.Record(flow, execCtx))
+	}
+
+	tests := []struct {
+		name   string
+		filter history.Filter
+		want   []string
+	}{
+		{name: "all runs, most recent first", want: []string{"wednesday", "tuesday-later", "tuesday", "monday"}},
+		{name: "by flow", filter: history.Filter{FlowID: "triage"}, want: []string{"tuesday-later"}},
+		{name: "by status", filter: history.Filter{Status: types.StatusFailed}, want: []string{"wednesday", "tuesday"}},
+		{
+			name:   "by day",
+			filter: history.Filter{Since: start.Truncate(24 * time.Hour), Until: start.Truncate(24 * time.Hour).Add(24 * time.Hour)},
+			want:   []string{"tuesday-later", "tuesday"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runs, err := store.List(tt.filter)
+			require.NoError(t, err)
+
+			sessions := make([]string, 0, len(runs))
+			for _, run := range runs {
+				sessions = append(sessions, run.SessionID)
+			}
+
+			assert.Equal(t, tt.want, sessions)
+		})
+	}
+}
+
+func TestStore_ListWithoutDirectory(t *testing.T) {
+	t.Parallel()
+
+	runs, err := history.NewStore(filepath.Join(t.TempDir(), "runs")).List(history.Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
@@ -0,0 +1,78 @@
+// Package llm defines the language model provider interface used by prompt steps.
+package llm
+
+import (
+	"context"
+)
+
+// Message roles understood by chat completion providers.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// ToolCallTypeFunction is the only tool call type defined by the chat completions API.
+const ToolCallTypeFunction = "function"
+
+// Provider sends chat completion requests to a language model.
+type Provider interface {
+	Complete(ctx context.Context, req *Request) (*Response, error)
+}
+
+// Message is a single chat message. Assistant messages carry the tool calls
+// the model requested; tool messages answer one of them by ToolCallID.
+type Message struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   //nolint:tagliatelle // OpenAI wire format
+	ToolCallID string     `json:"tool_call_id,omitempty"` //nolint:tagliatelle // OpenAI wire format
+}
+
+// ToolCall is a tool invocation requested by the model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall names the tool to call and carries its arguments as a JSON object string.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Tool describes a tool the model may call. Parameters is a JSON Schema.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+}
+
+// Request is a chat completion request.
+type Request struct {
+	Model       string
+	Messages    []Message
+	Tools       []Tool
+	MaxTokens   int
+	Temperature float64
+}
+
+// Usage reports the tokens consumed by a completion.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// Response is the result of a chat completion.
+type Response struct {
+	Content      string
+	ToolCalls    []ToolCall
+	Model        string
+	FinishReason string
+	Usage        Usage
+	// Cost is the price of the request in USD as reported by the provider.
+	Cost float64
+}
@@ -0,0 +1,242 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// DefaultOpenRouterBaseURL is the OpenRouter API endpoint used when no base URL is configured.
+const DefaultOpenRouterBaseURL = "https://openrouter.ai/api/v1"
+
+// Error codes reported by the OpenRouter client.
+const (
+	CodeLLMRequestFailed = "LLM_REQUEST_FAILED"
+	CodeLLMBadResponse   = "LLM_BAD_RESPONSE"
+)
+
+var (
+	// ErrMissingAPIKey is returned when the client has no API key.
+	ErrMissingAPIKey = errors.New("OpenRouter API key is not configured")
+
+	// ErrNoChoices is returned when the provider answers without any completion choice.
+	ErrNoChoices = errors.New("completion response has no choices")
+)
+
+const maxErrorBodyBytes = 4096
+
+// OpenRouterClient is a Provider backed by the OpenRouter chat completions API,
+// which is compatible with the OpenAI API.
+type OpenRouterClient struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// chatRequest is the OpenAI-compatible request body.
+type chatRequest struct {
+	Model       string         `json:"model"`
+	Messages    []Message      `json:"messages"`
+	Tools       []chatTool     `json:"tools,omitempty"`
+	MaxTokens   int            `json:"max_tokens,omitempty"` //nolint:tagliatelle // OpenAI wire format
+	Temperature float64        `json:"temperature"`
+	Usage       map[string]any `json:"usage,omitempty"`
+}
+
+// chatResponse is the OpenAI-compatible response body.
+type chatResponse struct {
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   chatUsage    `json:"usage"`
+}
+
+// chatTool is the OpenAI-compatible tool declaration.
+type chatTool struct {
+	Type     string       `json:"type"`
+	Function chatFunction `json:"function"`
+}
+
+type chatFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+type chatChoice struct {
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finish_reason"` //nolint:tagliatelle // OpenAI wire format
+}
+
+type chatUsage struct {
+	PromptTokens     int     `json:"prompt_tokens"`     //nolint:tagliatelle // OpenAI wire format
+	CompletionTokens int     `json:"completion_tokens"` //nolint:tagliatelle // OpenAI wire format
+	TotalTokens      int     `json:"total_tokens"`      //nolint:tagliatelle // OpenAI wire format
+	Cost             float64 `json:"cost"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewOpenRouterClient creates a client for the OpenRouter API.
+// An empty baseURL selects DefaultOpenRouterBaseURL.
+func NewOpenRouterClient(apiKey, baseURL string) *OpenRouterClient {
+	if baseURL == "" {
+		baseURL = DefaultOpenRouterBaseURL
+	}
+
+	return &OpenRouterClient{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{}, //nolint:exhaustruct // zero values are the documented defaults
+	}
+}
+
+// SetHTTPClient replaces the HTTP client used for requests.
+func (c *OpenRouterClient) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// Complete sends a chat completion request.
+func (c *OpenRouterClient) Complete(ctx context.Context, req *Request) (*Response, error) {
+	if c.apiKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+
+	body, err := json.Marshal(chatRequest{
+		Model:       req.Model,
+		Messages:    req.Messages,
+		Tools:       chatTools(req.Tools),
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Usage:       map[string]any{"include": true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal completion request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create completion request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Title", "flow-test-go")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		// A request whose context was canceled or timed out is given up on, not retried.
+		if ctx.Err() != nil {
+			return nil, newRequestError(fmt.Sprintf("completion request canceled: %v", ctx.Err()), 0, false)
+		}
+
+		return nil, newRequestError(fmt.Sprintf("completion request failed: %v", err), 0, true)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+
+	var decoded chatResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&decoded)
+	if err != nil {
+		return nil, newBadResponseError(fmt.Sprintf("failed to decode completion response: %v", err))
+	}
+
+	if len(decoded.Choices) == 0 {
+		return nil, newBadResponseError(ErrNoChoices.Error())
+	}
+
+	return &Response{
+		Content:      decoded.Choices[0].Message.Content,
+		ToolCalls:    decoded.Choices[0].Message.ToolCalls,
+		Model:        decoded.Model,
+		FinishReason: decoded.Choices[0].FinishReason,
+		Usage: Usage{
+			PromptTokens:     decoded.Usage.PromptTokens,
+			CompletionTokens: decoded.Usage.CompletionTokens,
+			TotalTokens:      decoded.Usage.TotalTokens,
+		},
+		Cost: decoded.Usage.Cost,
+	}, nil
+}
+
+// chatTools converts tool declarations to the wire format.
+func chatTools(tools []Tool) []chatTool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	converted := make([]chatTool, 0, len(tools))
+	for _, tool := range tools {
+		parameters := tool.Parameters
+		if parameters == nil {
+			parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+
+		converted = append(converted, chatTool{
+			Type: ToolCallTypeFunction,
+			Function: chatFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  parameters,
+			},
+		})
+	}
+
+	return converted
+}
+
+// statusError converts a non-200 response into an ExecutionError.
+// Rate limits and server errors are recoverable; other client errors are not.
+func statusError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+
+	message := strings.TrimSpace(string(data))
+
+	var decoded errorResponse
+	if json.Unmarshal(data, &decoded) == nil && decoded.Error.Message != "" {
+		message = decoded.Error.Message
+	}
+
+	recoverable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+
+	return newRequestError(fmt.Sprintf("OpenRouter returned %d: %s", resp.StatusCode, message), resp.StatusCode, recoverable)
+}
+
+// newRequestError creates the error reported for a failed completion request.
+func newRequestError(message string, status int, recoverable bool) *types.ExecutionError {
+	return &types.ExecutionError{
+		Code:        CodeLLMRequestFailed,
+		Message:     message,
+		Details:     map[string]any{"status": status},
+		Recoverable: recoverable,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
+
+// newBadResponseError creates the error reported for an unusable completion response.
+func newBadResponseError(message string) *types.ExecutionError {
+	return &types.ExecutionError{
+		Code:        CodeLLMBadResponse,
+		Message:     message,
+		Details:     nil,
+		Recoverable: true,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
@@ -0,0 +1,202 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/llm"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+func TestOpenRouterClient_Complete(t *testing.T) {
+	t.Parallel()
+
+	var received map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"model": "openai/gpt-4o-mini",
+			"choices": [{"message": {"role": "assistant", "content": "Hello!"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 3, "total_tokens": 15, "cost": 0.00042}
+		}`))
+	}))
+	defer server.Close()
+
+	client := llm.NewOpenRouterClient("test-key", server.URL+"/")
+
+	resp, err := client.Complete(context.Background(), &llm.Request{
+		Model: "openai/gpt-4o-mini",
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: "Be brief."},
+			{Role: llm.RoleUser, Content: "Say hello"},
+		},
+		MaxTokens:   64,
+		Temperature: 0.2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello!", resp.Content)
+	assert.Equal(t, "openai/gpt-4o-mini", resp.Model)
+	assert.Equal(t, "stop", resp.FinishReason)
+	assert.Equal(t, 15, resp.Usage.TotalTokens)
+	assert.Equal(t, 12, resp.Usage.PromptTokens)
+	assert.InDelta(t, 0.00042, resp.Cost, 1e-9)
+
+	assert.Equal(t, "openai/gpt-4o-mini", received["model"])
+	assert.InDelta(t, 64, received["max_tokens"], 0)
+	assert.Equal(t, map[string]any{"include": true}, received["usage"])
+	assert.Len(t, received["messages"], 2)
+}
+
+func TestOpenRouterClient_Complete_ToolCalls(t *testing.T) {
+	t.Parallel()
+
+	var received map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		_, _ = w.Write([]byte(`{
+			"choices": [{
+				"message": {
+					"role": "assistant",
+					"content": "",
+					"tool_calls": [{"id": "call-1", "type": "function",
+						"function": {"name": "search", "arguments": "{\"query\": \"bugs\"}"}}]
+				},
+				"finish_reason": "tool_calls"
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	resp, err := llm.NewOpenRouterClient("test-key", server.URL).Complete(context.Background(), &llm.Request{
+		Model: "m",
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: "find bugs"},
+			{Role: llm.RoleTool, Content: "[]", ToolCallID: "call-0"},
+		},
+		Tools: []llm.Tool{
+			{Name: "search", Description: "Search issues", Parameters: map[string]any{"type": "object"}},
+			{Name: "noop"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "call-1", resp.ToolCalls[0].ID)
+	assert.Equal(t, "search", resp.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"query": "bugs"}`, resp.ToolCalls[0].Function.Arguments)
+
+	assert.Equal(t, []any{
+		map[string]any{"type": "function", "function": map[string]any{
+			"name": "search", "description": "Search issues", "parameters": map[string]any{"type": "object"},
+		}},
+		map[string]any{"type": "function", "function": map[string]any{
+			"name": "noop", "parameters": map[string]any{"type": "object", "properties": map[string]any{}},
+		}},
+	}, received["tools"])
+
+	messages, ok := received["messages"].([]any)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"role": "tool", "content": "[]", "tool_call_id": "call-0"}, messages[1])
+}
+
+func TestOpenRouterClient_Complete_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		recoverable bool
+		message     string
+	}{
+		{"rate limited", http.StatusTooManyRequests, `{"error": {"message": "slow down"}}`, true, "slow down"},
+		{"server error", http.StatusBadGateway, "upstream failed", true, "upstream failed"},
+		{"unauthorized", http.StatusUnauthorized, `{"error": {"message": "invalid key"}}`, false, "invalid key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := llm.NewOpenRouterClient("test-key", server.URL).Complete(context.Background(), &llm.Request{
+				Model:    "m",
+				Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}},
+			})
+			require.Error(t, err)
+
+			var execErr *types.ExecutionError
+			require.ErrorAs(t, err, &execErr)
+			assert.Equal(t, llm.CodeLLMRequestFailed, execErr.Code)
+			assert.Equal(t, tt.recoverable, execErr.Recoverable)
+			assert.Contains(t, execErr.Message, tt.message)
+		})
+	}
+}
+
+func TestOpenRouterClient_Complete_Canceled(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "late"}}]}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := llm.NewOpenRouterClient("test-key", server.URL).Complete(ctx, &llm.Request{})
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, llm.CodeLLMRequestFailed, execErr.Code)
+	assert.False(t, execErr.Recoverable, "a canceled request is not retried")
+	assert.Contains(t, execErr.Message, context.Canceled.Error())
+
+	server.Close()
+
+	_, err = llm.NewOpenRouterClient("test-key", server.URL).Complete(context.Background(), &llm.Request{})
+	require.ErrorAs(t, err, &execErr)
+	assert.True(t, execErr.Recoverable, "a transport failure is retried")
+}
+
+func TestOpenRouterClient_Complete_NoChoices(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"choices": []}`))
+	}))
+	defer server.Close()
+
+	_, err := llm.NewOpenRouterClient("test-key", server.URL).Complete(context.Background(), &llm.Request{})
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, llm.CodeLLMBadResponse, execErr.Code)
+}
+
+func TestOpenRouterClient_Complete_MissingAPIKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := llm.NewOpenRouterClient("", "").Complete(context.Background(), &llm.Request{})
+	require.ErrorIs(t, err, llm.ErrMissingAPIKey)
+}
@@ -0,0 +1,184 @@
+// Package logging builds the structured logger of flow runs on log/slog.
+// Records of a run carry the flow, session and step they belong to under
+// FlowIDKey, SessionIDKey and StepIDKey.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Attribute keys identifying where a record comes from.
+const (
+	FlowIDKey    = "flowId"
+	SessionIDKey = "sessionId"
+	StepIDKey    = "stepId"
+)
+
+// redacted replaces the values of attributes and map entries with sensitive keys.
+const redacted = "[REDACTED]"
+
+var (
+	// ErrUnknownLevel is returned for log levels other than debug, info, warn and error.
+	ErrUnknownLevel = errors.New("unknown log level")
+
+	// ErrUnknownFormat is returned for log formats other than text and json.
+	ErrUnknownFormat = errors.New("unknown log format")
+)
+
+// sensitiveSuffixes end the normalized keys whose values are never logged,
+// such as token, githubToken, apiKey and X-Api-Key.
+//
+//nolint:gochecknoglobals // read-only list of key suffixes
+var sensitiveSuffixes = []string{"token", "apikey", "password", "secret", "authorization"}
+
+// Options configures a logger.
+type Options struct {
+	// Level is the lowest level logged: debug, info, warn or error.
+	Level string
+	// Format is FormatText or FormatJSON.
+	Format string
+	// Console receives the records when it is not nil.
+	Console io.Writer
+	// File receives the records when it is not empty. It is rotated when
+	// it would grow beyond MaxSize bytes, keeping MaxBackups old files.
+	File       string
+	MaxSize    int64
+	MaxBackups int
+}
+
+// New returns a logger writing to the console and file of options. The
+// returned closer closes the log file. A logger without a console or file
+// discards every record.
+func New(options Options) (*slog.Logger, io.Closer, error) {
+	var level slog.Level
+
+	err := level.UnmarshalText([]byte(options.Level))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w %q: use debug, info, warn or error", ErrUnknownLevel, options.Level)
+	}
+
+	format := strings.ToLower(options.Format)
+	if format != FormatText && format != FormatJSON {
+		return nil, nil, fmt.Errorf("%w %q: use text or json", ErrUnknownFormat, options.Format)
+	}
+
+	writers := []io.Writer{}
+	closer := io.Closer(nopCloser{})
+
+	if options.Console != nil {
+		writers = append(writers, options.Console)
+	}
+
+	if options.File != "" {
+		file, err := OpenRotatingFile(options.File, options.MaxSize, options.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		writers = append(writers, file)
+		closer = file
+	}
+
+	if len(writers) == 0 {
+		return Discard(), closer, nil
+	}
+
+	handlerOptions := &slog.HandlerOptions{AddSource: false, Level: level, ReplaceAttr: redact}
+	output := io.MultiWriter(writers...)
+
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(output, handlerOptions)), closer, nil
+	}
+
+	return slog.New(slog.NewTextHandler(output, handlerOptions)), closer, nil
+}
+
+// Discard returns a logger that drops every record.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// ForStep returns logger with the attributes identifying a step of a run.
+func ForStep(logger *slog.Logger, flowID, sessionID, stepID string) *slog.Logger {
+	return logger.With(FlowIDKey, flowID, SessionIDKey, sessionID, StepIDKey, stepID)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or a logger discarding
+// every record when ctx carries none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return Discard()
+}
+
+// redact hides the values of attributes with sensitive keys, including
+// entries of map values such as tool arguments.
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if sensitive(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	if attr.Value.Kind() == slog.KindAny {
+		if values, ok := attr.Value.Any().(map[string]any); ok {
+			return slog.Any(attr.Key, redactMap(values))
+		}
+	}
+
+	return attr
+}
+
+// redactMap returns a copy of values with the values of sensitive keys hidden.
+func redactMap(values map[string]any) map[string]any {
+	clean := make(map[string]any, len(values))
+
+	for key, value := range values {
+		if nested, ok := value.(map[string]any); ok {
+			value = redactMap(nested)
+		}
+
+		if sensitive(key) {
+			value = redacted
+		}
+
+		clean[key] = value
+	}
+
+	return clean
+}
+
+// sensitive reports whether values under key must not be logged.
+func sensitive(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
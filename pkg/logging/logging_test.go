@@ -0,0 +1,105 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+)
+
+func TestNew_JSON(t *testing.T) {
+	t.Parallel()
+
+	var console bytes.Buffer
+
+	logger, closer, err := logging.New(logging.Options{Level: "info", Format: "json", Console: &console})
+	require.NoError(t, err)
+	require.NoError(t, closer.Close())
+
+	logger = logging.ForStep(logger, "review", "session-1", "fetch")
+	logger.Debug("hidden")
+	logger.Info("calling tool", "arguments", map[string]any{
+		"path":    "README.md",
+		"headers": map[string]any{"Authorization": "Bearer abc"},
+		"api_key": "abc",
+	}, "githubToken", "abc", "tokensUsed", 12)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(console.Bytes(), &record))
+
+	assert.Equal(t, "calling tool", record["msg"])
+	assert.Equal(t, "review", record[logging.FlowIDKey])
+	assert.Equal(t, "session-1", record[logging.SessionIDKey])
+	assert.Equal(t, "fetch", record[logging.StepIDKey])
+	assert.Equal(t, "[REDACTED]", record["githubToken"])
+	assert.InDelta(t, 12, record["tokensUsed"], 0)
+	assert.Equal(t, map[string]any{
+		"path":    "README.md",
+		"headers": map[string]any{"Authorization": "[REDACTED]"},
+		"api_key": "[REDACTED]",
+	}, record["arguments"])
+}
+
+func TestNew_Text(t *testing.T) {
+	t.Parallel()
+
+	var console bytes.Buffer
+
+	logger, _, err := logging.New(logging.Options{Level: "DEBUG", Format: "text", Console: &console})
+	require.NoError(t, err)
+
+	logger.Debug("step started", logging.StepIDKey, "fetch")
+	assert.Contains(t, console.String(), `level=DEBUG msg="step started" stepId=fetch`)
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := logging.New(logging.Options{Level: "loud", Format: "text"})
+	require.ErrorIs(t, err, logging.ErrUnknownLevel)
+
+	_, _, err = logging.New(logging.Options{Level: "info", Format: "xml"})
+	require.ErrorIs(t, err, logging.ErrUnknownFormat)
+}
+
+func TestNew_File(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "flow.log")
+
+	logger, closer, err := logging.New(logging.Options{Level: "warn", Format: "text", File: path})
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("step failed")
+	require.NoError(t, closer.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hidden")
+	assert.Contains(t, string(data), `msg="step failed"`)
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	var console bytes.Buffer
+
+	logger, _, err := logging.New(logging.Options{Level: "info", Format: "text", Console: &console})
+	require.NoError(t, err)
+
+	logging.FromContext(context.Background()).Info("dropped")
+	logging.FromContext(logging.NewContext(context.Background(), logger)).Info("kept")
+
+	assert.NotContains(t, console.String(), "dropped")
+	assert.Equal(t, 1, strings.Count(console.String(), "msg=kept"))
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	dirPerms  = 0o750
+	filePerms = 0o600
+)
+
+// RotatingFile is a log file that is renamed to File.1 when a write would
+// grow it beyond its size limit; older backups shift to File.2 and so on,
+// and backups beyond the limit are removed.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// OpenRotatingFile opens path for appending, creating it and its directory
+// when needed. A maxSize of zero or less never rotates the file.
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	err := os.MkdirAll(filepath.Dir(path), dirPerms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	rotating := &RotatingFile{
+		mu:         sync.Mutex{},
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		file:       nil,
+		size:       0,
+	}
+
+	err = rotating.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return rotating, nil
+}
+
+// Write appends data to the file, rotating it first when data would not fit.
+func (r *RotatingFile) Write(data []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(data)) > r.maxSize {
+		err := r.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	written, err := r.file.Write(data)
+	r.size += int64(written)
+
+	if err != nil {
+		return written, fmt.Errorf("failed to write log file: %w", err)
+	}
+
+	return written, nil
+}
+
+// Close closes the file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	return nil
+}
+
+// open opens the file at path for appending and records its size.
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerms)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+
+	return nil
+}
+
+// rotate shifts the backups, moves the file to the first backup and opens
+// a new file. Without backups the file is truncated instead.
+func (r *RotatingFile) rotate() error {
+	err := r.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	err = os.Remove(r.backup(r.maxBackups))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove old log file: %w", err)
+	}
+
+	for index := r.maxBackups - 1; index >= 0; index-- {
+		err = os.Rename(r.backup(index), r.backup(index+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+
+	return r.open()
+}
+
+// backup returns the path of the index-th backup; index 0 is the file itself.
+func (r *RotatingFile) backup(index int) string {
+	if index == 0 {
+		return r.path
+	}
+
+	return fmt.Sprintf("%s.%d", r.path, index)
+}
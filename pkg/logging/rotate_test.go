@@ -0,0 +1,54 @@
+package logging_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/logging"
+)
+
+func TestRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "flow.log")
+
+	file, err := logging.OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, file.Close())
+
+	for file, want := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data), file)
+	}
+
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestRotatingFile_Appends(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "flow.log")
+	require.NoError(t, os.WriteFile(path, []byte("earlier\n"), 0o600))
+
+	file, err := logging.OpenRotatingFile(path, 100, 1)
+	require.NoError(t, err)
+
+	_, err = file.Write([]byte("later\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "earlier\nlater\n", string(data))
+}
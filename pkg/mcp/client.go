@@ -0,0 +1,776 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Error codes reported by MCP tool calls.
+const (
+	CodeMCPToolFailed = "MCP_TOOL_FAILED"
+	CodeMCPCallFailed = "MCP_CALL_FAILED"
+)
+
+// DefaultRequestTimeout bounds a request when neither the context nor the
+// server configuration sets a deadline.
+const DefaultRequestTimeout = 30 * time.Second
+
+const (
+	clientName      = "flow-test-go"
+	clientVersion   = "1.0.0"
+	stopGracePeriod = 2 * time.Second
+	stderrTailSize  = 4096
+	maxMessageSize  = 16 * 1024 * 1024
+)
+
+var (
+	// ErrUnsupportedTransport is returned when a server is not configured for stdio.
+	ErrUnsupportedTransport = errors.New("only the stdio transport is supported")
+
+	// ErrNotStarted is returned when a request is sent before Start.
+	ErrNotStarted = errors.New("MCP client is not started")
+
+	// ErrAlreadyStarted is returned when Start is called twice.
+	ErrAlreadyStarted = errors.New("MCP client is already started")
+
+	// ErrServerExited is returned for requests pending when the server process exits.
+	ErrServerExited = errors.New("MCP server exited")
+
+	// ErrToolNotFound is returned when calling a tool the server did not list.
+	ErrToolNotFound = errors.New("tool not found on MCP server")
+)
+
+// Client is a connection to a single MCP server launched over stdio.
+// It is safe for concurrent use.
+type Client struct {
+	config types.MCPServerConfig
+
+	// writeMu guards stdin and serializes writes to it.
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	pending map[string]chan *rpcMessage
+	nextID  int64
+	status  types.MCPServerStatus
+	done    chan struct{}
+	exitErr error
+	stderr  *tailBuffer
+	server  serverCapabilities
+}
+
+// NewClient creates a client for the server described by config.
+// The server is not launched until Start is called.
+func NewClient(config types.MCPServerConfig) *Client {
+	return &Client{
+		config:  config,
+		writeMu: sync.Mutex{},
+		stdin:   nil,
+		mu:      sync.Mutex{},
+		cmd:     nil,
+		pending: make(map[string]chan *rpcMessage),
+		nextID:  0,
+		status: types.MCPServerStatus{
+			Name:         config.Name,
+			Status:       types.MCPStateStopped,
+			PID:          0,
+			StartTime:    time.Time{},
+			LastPing:     time.Time{},
+			RestartCount: 0,
+			Tools:        nil,
+			Resources:    nil,
+			Error:        nil,
+			Metadata:     nil,
+		},
+		done:    nil,
+		exitErr: nil,
+		stderr:  nil,
+		server:  serverCapabilities{Tools: nil, Resources: nil, Prompts: nil, Logging: nil},
+	}
+}
+
+// Name returns the configured server name.
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+// Start launches the server process, performs the initialize handshake and
+// discovers the tools and resources the server offers.
+func (c *Client) Start(ctx context.Context) error {
+	err := c.config.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid MCP server %s: %w", c.config.Name, err)
+	}
+
+	if c.config.TransportType != types.TransportStdio {
+		return fmt.Errorf("MCP server %s: %w (got %s)", c.config.Name, ErrUnsupportedTransport, c.config.TransportType)
+	}
+
+	err = c.launch()
+	if err != nil {
+		c.setFailed(err)
+
+		return err
+	}
+
+	err = c.initialize(ctx)
+	if err == nil {
+		err = c.Refresh(ctx)
+	}
+
+	if err != nil {
+		_ = c.Close()
+		c.setFailed(err)
+
+		return fmt.Errorf("MCP server %s failed to start: %w", c.config.Name, err)
+	}
+
+	c.mu.Lock()
+	c.status.Status = types.MCPStateRunning
+	c.status.Error = nil
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Refresh lists the server's tools and resources again.
+func (c *Client) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	listTools := c.config.Capabilities.Tools && c.server.Tools != nil
+	listResources := c.config.Capabilities.Resources && c.server.Resources != nil
+	c.mu.Unlock()
+
+	var (
+		tools     []types.MCPTool
+		resources []types.MCPResource
+		err       error
+	)
+
+	if listTools {
+		tools, err = c.listTools(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if listResources {
+		resources, err = c.listResources(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.status.Tools = tools
+	c.status.Resources = resources
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Status returns a snapshot of the server's runtime status.
+func (c *Client) Status() types.MCPServerStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := c.status
+	status.Tools = append([]types.MCPTool(nil), c.status.Tools...)
+	status.Resources = append([]types.MCPResource(nil), c.status.Resources...)
+	status.Metadata = maps.Clone(c.status.Metadata)
+
+	return status
+}
+
+// Tools returns the tools discovered on the server.
+func (c *Client) Tools() []types.MCPTool {
+	return c.Status().Tools
+}
+
+// Resources returns the resources discovered on the server.
+func (c *Client) Resources() []types.MCPResource {
+	return c.Status().Resources
+}
+
+// Done returns a channel that is closed when the server process exits.
+// It returns nil before Start.
+func (c *Client) Done() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.done
+}
+
+// CallTool executes a tool on the server. Protocol and transport failures
+// are returned as errors; a tool that reports an error produces a result
+// with Success false.
+func (c *Client) CallTool(ctx context.Context, call types.MCPToolCall) (*types.MCPToolResult, error) {
+	if !c.hasTool(call.ToolName) {
+		return nil, fmt.Errorf("%w: %s on %s", ErrToolNotFound, call.ToolName, c.config.Name)
+	}
+
+	start := time.Now()
+
+	var result toolCallResult
+
+	err := c.request(ctx, methodToolsCall, toolCallParams{Name: call.ToolName, Arguments: call.Arguments}, &result)
+	if err != nil {
+		return nil, &types.ExecutionError{
+			Code:        CodeMCPCallFailed,
+			Message:     fmt.Sprintf("tool %s on %s failed: %v", call.ToolName, c.config.Name, err),
+			Details:     map[string]any{"tool": call.ToolName, "server": c.config.Name},
+			Recoverable: errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrServerExited),
+			Timestamp:   time.Now(),
+			StackTrace:  "",
+		}
+	}
+
+	toolResult := &types.MCPToolResult{
+		CallID:    call.ID,
+		Success:   !result.IsError,
+		Result:    contentValue(result.Content),
+		Error:     nil,
+		Duration:  time.Since(start),
+		Timestamp: time.Now(),
+		Metadata:  map[string]any{"content": result.Content, "server": c.config.Name},
+	}
+
+	if result.IsError {
+		toolResult.Error = &types.ExecutionError{
+			Code:        CodeMCPToolFailed,
+			Message:     fmt.Sprintf("tool %s reported an error: %v", call.ToolName, toolResult.Result),
+			Details:     map[string]any{"tool": call.ToolName, "server": c.config.Name},
+			Recoverable: false,
+			Timestamp:   time.Now(),
+			StackTrace:  "",
+		}
+	}
+
+	return toolResult, nil
+}
+
+// Ping checks that the server answers and records the time in LastPing.
+// An empty method sends the standard MCP ping request.
+func (c *Client) Ping(ctx context.Context, method string) error {
+	if method == "" {
+		method = methodPing
+	}
+
+	err := c.request(ctx, method, nil, nil)
+	if err != nil {
+		return fmt.Errorf("ping of MCP server %s failed: %w", c.config.Name, err)
+	}
+
+	c.mu.Lock()
+	c.status.LastPing = time.Now()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Close stops the server process. It closes stdin first so the server can
+// exit on its own and kills it after a short grace period.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	cmd, done := c.cmd, c.done
+
+	if cmd == nil {
+		c.mu.Unlock()
+
+		return nil
+	}
+
+	c.status.Status = types.MCPStateStopped
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	_ = c.stdin.Close()
+	c.writeMu.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(stopGracePeriod):
+		_ = cmd.Process.Kill()
+		<-done
+	}
+
+	c.mu.Lock()
+	c.cmd = nil
+	c.status.PID = 0
+	c.mu.Unlock()
+
+	return nil
+}
+
+// launch starts the server process and its reader goroutine.
+func (c *Client) launch() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cmd != nil && !c.exited() {
+		return ErrAlreadyStarted
+	}
+
+	//nolint:gosec // launching the configured server command is the purpose of this client
+	cmd := exec.Command(c.config.Command, c.config.Args...)
+	cmd.Env = os.Environ()
+
+	for name, value := range c.config.Env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open stdin of MCP server %s: %w", c.config.Name, err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open stdout of MCP server %s: %w", c.config.Name, err)
+	}
+
+	c.stderr = newTailBuffer(stderrTailSize)
+	cmd.Stderr = c.stderr
+
+	c.status.Status = types.MCPStateStarting
+
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("failed to launch MCP server %s: %w", c.config.Name, err)
+	}
+
+	c.cmd = cmd
+	c.done = make(chan struct{})
+	c.exitErr = nil
+	c.status.PID = cmd.Process.Pid
+	c.status.StartTime = time.Now()
+
+	// Writers read stdin under writeMu only.
+	c.writeMu.Lock()
+	c.stdin = stdin
+	c.writeMu.Unlock()
+
+	go c.readLoop(cmd, stdout, c.done)
+
+	return nil
+}
+
+// exited reports whether the last launched process has exited.
+// The caller must hold c.mu.
+func (c *Client) exited() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// initialize performs the MCP handshake.
+func (c *Client) initialize(ctx context.Context) error {
+	var result initializeResult
+
+	err := c.request(ctx, methodInitialize, initializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      implementation{Name: clientName, Version: clientVersion},
+	}, &result)
+	if err != nil {
+		return fmt.Errorf("initialize failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.server = result.Capabilities
+	c.status.Metadata = map[string]any{
+		"protocolVersion": result.ProtocolVersion,
+		"serverName":      result.ServerInfo.Name,
+		"serverVersion":   result.ServerInfo.Version,
+	}
+	c.mu.Unlock()
+
+	return c.send(&rpcMessage{
+		JSONRPC: jsonRPCVersion,
+		ID:      nil,
+		Method:  methodInitialized,
+		Params:  nil,
+		Result:  nil,
+		Error:   nil,
+	})
+}
+
+func (c *Client) listTools(ctx context.Context) ([]types.MCPTool, error) {
+	var (
+		tools  []types.MCPTool
+		cursor string
+	)
+
+	for {
+		var result toolsListResult
+
+		err := c.request(ctx, methodToolsList, listParams{Cursor: cursor}, &result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+
+		for _, tool := range result.Tools {
+			tools = append(tools, types.MCPTool{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Schema:      tool.InputSchema,
+				ServerName:  c.config.Name,
+				Metadata:    nil,
+			})
+		}
+
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+
+		cursor = result.NextCursor
+	}
+}
+
+func (c *Client) listResources(ctx context.Context) ([]types.MCPResource, error) {
+	var (
+		resources []types.MCPResource
+		cursor    string
+	)
+
+	for {
+		var result resourcesListResult
+
+		err := c.request(ctx, methodResourcesList, listParams{Cursor: cursor}, &result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources: %w", err)
+		}
+
+		for _, resource := range result.Resources {
+			resources = append(resources, types.MCPResource{
+				Name:        resource.Name,
+				Description: resource.Description,
+				URI:         resource.URI,
+				MimeType:    resource.MimeType,
+				ServerName:  c.config.Name,
+				Metadata:    nil,
+			})
+		}
+
+		if result.NextCursor == "" {
+			return resources, nil
+		}
+
+		cursor = result.NextCursor
+	}
+}
+
+func (c *Client) hasTool(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tool := range c.status.Tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// request sends a JSON-RPC request and decodes its result into out.
+func (c *Client) request(ctx context.Context, method string, params, out any) error {
+	c.mu.Lock()
+	if c.cmd == nil {
+		c.mu.Unlock()
+
+		return ErrNotStarted
+	}
+
+	c.nextID++
+	id := json.RawMessage(strconv.FormatInt(c.nextID, 10))
+	key := string(id)
+	reply := make(chan *rpcMessage, 1)
+	c.pending[key] = reply
+	done := c.done
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+	}()
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		timeout := c.config.Timeout
+		if timeout <= 0 {
+			timeout = DefaultRequestTimeout
+		}
+
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := c.send(&rpcMessage{
+		JSONRPC: jsonRPCVersion,
+		ID:      &id,
+		Method:  method,
+		Params:  params,
+		Result:  nil,
+		Error:   nil,
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case msg := <-reply:
+		if msg.Error != nil {
+			return fmt.Errorf("%s: %w", method, msg.Error)
+		}
+
+		if out == nil || len(msg.Result) == 0 {
+			return nil
+		}
+
+		err = json.Unmarshal(msg.Result, out)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+
+		return nil
+	case <-done:
+		return c.exitError()
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", method, ctx.Err())
+	}
+}
+
+// send writes one newline-delimited JSON-RPC message to the server.
+func (c *Client) send(msg *rpcMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s message: %w", msg.Method, err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err = c.stdin.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write to MCP server %s: %w", c.config.Name, err)
+	}
+
+	return nil
+}
+
+// readLoop dispatches messages from the server until its stdout closes.
+func (c *Client) readLoop(cmd *exec.Cmd, stdout io.Reader, done chan struct{}) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxMessageSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var msg rpcMessage
+
+		err := json.Unmarshal(line, &msg)
+		if err != nil {
+			continue
+		}
+
+		c.dispatch(&msg)
+	}
+
+	waitErr := cmd.Wait()
+
+	c.mu.Lock()
+	c.exitErr = fmt.Errorf("%w: %s", ErrServerExited, c.describeExit(waitErr))
+
+	if c.status.Status != types.MCPStateStopped {
+		c.status.Status = types.MCPStateFailed
+		c.status.Error = &types.ExecutionError{
+			Code:        CodeMCPCallFailed,
+			Message:     c.exitErr.Error(),
+			Details:     map[string]any{"server": c.config.Name},
+			Recoverable: true,
+			Timestamp:   time.Now(),
+			StackTrace:  "",
+		}
+	}
+	c.mu.Unlock()
+
+	close(done)
+}
+
+// dispatch routes a response to its pending request and answers server requests.
+func (c *Client) dispatch(msg *rpcMessage) {
+	if msg.Method == "" {
+		if msg.ID == nil {
+			return
+		}
+
+		c.mu.Lock()
+		reply, exists := c.pending[string(*msg.ID)]
+		c.mu.Unlock()
+
+		if exists {
+			reply <- msg
+		}
+
+		return
+	}
+
+	if msg.ID == nil || strings.HasPrefix(msg.Method, notificationPrefix) {
+		return
+	}
+
+	response := &rpcMessage{
+		JSONRPC: jsonRPCVersion,
+		ID:      msg.ID,
+		Method:  "",
+		Params:  nil,
+		Result:  nil,
+		Error:   nil,
+	}
+
+	if msg.Method == methodPing {
+		response.Result = json.RawMessage("{}")
+	} else {
+		response.Error = &types.MCPError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method, Data: nil}
+	}
+
+	_ = c.send(response)
+}
+
+// describeExit summarizes why the server exited, including its last stderr output.
+func (c *Client) describeExit(waitErr error) string {
+	description := "exited"
+	if waitErr != nil {
+		description = waitErr.Error()
+	}
+
+	if tail := strings.TrimSpace(c.stderr.String()); tail != "" {
+		description += ": " + tail
+	}
+
+	return description
+}
+
+func (c *Client) exitError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.exitErr != nil {
+		return c.exitErr
+	}
+
+	return ErrServerExited
+}
+
+// kill terminates the server process without marking the client stopped,
+// so the exit is reported like a crash.
+func (c *Client) kill() {
+	c.mu.Lock()
+	cmd := c.cmd
+	c.mu.Unlock()
+
+	if cmd != nil && cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+}
+
+// setState updates the reported server state.
+func (c *Client) setState(state types.MCPServerState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.status.Status = state
+}
+
+// recordRestart increments the restart counter and returns the new count.
+func (c *Client) recordRestart() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.status.RestartCount++
+
+	return c.status.RestartCount
+}
+
+func (c *Client) setFailed(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.status.Status = types.MCPStateFailed
+	c.status.Error = &types.ExecutionError{
+		Code:        CodeMCPCallFailed,
+		Message:     err.Error(),
+		Details:     map[string]any{"server": c.config.Name},
+		Recoverable: true,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
+
+// contentValue returns the text of a tool result when every block is text,
+// and the raw content blocks otherwise.
+func contentValue(content []contentItem) any {
+	texts := make([]string, 0, len(content))
+
+	for _, item := range content {
+		if item.Type != "text" {
+			return content
+		}
+
+		texts = append(texts, item.Text)
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+// tailBuffer keeps the last size bytes written to it.
+type tailBuffer struct {
+	mu   sync.Mutex
+	size int
+	data []byte
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{mu: sync.Mutex{}, size: size, data: nil}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data = append(b.data, p...)
+	if len(b.data) > b.size {
+		b.data = b.data[len(b.data)-b.size:]
+	}
+
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return string(b.data)
+}
@@ -0,0 +1,206 @@
+package mcp_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/mcp"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// fakeServerPath is the fake MCP server binary built once for all tests.
+var fakeServerPath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "mcp-fakeserver")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fakeServerPath = filepath.Join(dir, "fakeserver")
+	if runtime.GOOS == "windows" {
+		fakeServerPath += ".exe"
+	}
+
+	build := exec.Command("go", "build", "-o", fakeServerPath, "./testdata/fakeserver")
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+
+	err = build.Run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to build fake MCP server:", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	_ = os.RemoveAll(dir)
+
+	os.Exit(code)
+}
+
+func fakeServerConfig() types.MCPServerConfig {
+	return types.MCPServerConfig{
+		Schema:           "",
+		Name:             "fake",
+		Command:          fakeServerPath,
+		Args:             []string{},
+		Env:              map[string]string{"FAKE_GREETING": "hello from env"},
+		TransportType:    types.TransportStdio,
+		TransportOptions: map[string]any{},
+		Capabilities: types.MCPCapabilities{
+			Tools:     true,
+			Resources: true,
+			Prompts:   false,
+			Logging:   false,
+		},
+		Timeout:     5 * time.Second,
+		HealthCheck: nil,
+		AutoRestart: false,
+		MaxRestarts: 0,
+		Metadata:    map[string]any{},
+	}
+}
+
+func startClient(t *testing.T) *mcp.Client {
+	t.Helper()
+
+	client := mcp.NewClient(fakeServerConfig())
+	require.NoError(t, client.Start(context.Background()))
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func toolCall(name string, arguments map[string]any) types.MCPToolCall {
+	return types.MCPToolCall{
+		ID:         "call-" + name,
+		ToolName:   name,
+		ServerName: "fake",
+		Arguments:  arguments,
+		Timestamp:  time.Now(),
+		Metadata:   nil,
+	}
+}
+
+func TestClient_StartDiscoversToolsAndResources(t *testing.T) {
+	t.Parallel()
+
+	client := startClient(t)
+	status := client.Status()
+
+	assert.Equal(t, types.MCPStateRunning, status.Status)
+	assert.NotZero(t, status.PID)
+	assert.Equal(t, "fake", status.Metadata["serverName"])
+	assert.Equal(t, mcp.ProtocolVersion, status.Metadata["protocolVersion"])
+
+	names := make([]string, 0, len(status.Tools))
+	for _, tool := range status.Tools {
+		names = append(names, tool.Name)
+		assert.Equal(t, "fake", tool.ServerName)
+		assert.Equal(t, "object", tool.Schema["type"])
+	}
+
+	assert.Equal(t, []string{"echo", "greet", "fail", "crash", "freeze"}, names)
+
+	require.Len(t, status.Resources, 1)
+	assert.Equal(t, "file:///readme.md", status.Resources[0].URI)
+	assert.Equal(t, "text/markdown", status.Resources[0].MimeType)
+}
+
+func TestClient_CallTool(t *testing.T) {
+	t.Parallel()
+
+	client := startClient(t)
+
+	result, err := client.CallTool(context.Background(), toolCall("echo", map[string]any{"text": "ping"}))
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "call-echo", result.CallID)
+	assert.Equal(t, "ping", result.Result)
+	assert.Nil(t, result.Error)
+
+	result, err = client.CallTool(context.Background(), toolCall("greet", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "hello from env", result.Result)
+}
+
+func TestClient_CallToolReportsToolError(t *testing.T) {
+	t.Parallel()
+
+	client := startClient(t)
+
+	result, err := client.CallTool(context.Background(), toolCall("fail", nil))
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, mcp.CodeMCPToolFailed, result.Error.Code)
+	assert.Contains(t, result.Error.Message, "something went wrong")
+}
+
+func TestClient_CallUnknownTool(t *testing.T) {
+	t.Parallel()
+
+	client := startClient(t)
+
+	_, err := client.CallTool(context.Background(), toolCall("missing", nil))
+	require.ErrorIs(t, err, mcp.ErrToolNotFound)
+}
+
+func TestClient_ServerExit(t *testing.T) {
+	t.Parallel()
+
+	client := startClient(t)
+
+	_, err := client.CallTool(context.Background(), toolCall("crash", nil))
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, mcp.CodeMCPCallFailed, execErr.Code)
+	assert.True(t, execErr.Recoverable)
+	assert.Contains(t, execErr.Message, "crashing on request")
+
+	<-client.Done()
+	assert.Equal(t, types.MCPStateFailed, client.Status().Status)
+}
+
+func TestClient_Close(t *testing.T) {
+	t.Parallel()
+
+	client := startClient(t)
+	require.NoError(t, client.Close())
+
+	assert.Equal(t, types.MCPStateStopped, client.Status().Status)
+
+	_, err := client.CallTool(context.Background(), toolCall("echo", nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), mcp.ErrNotStarted.Error())
+}
+
+func TestClient_StartErrors(t *testing.T) {
+	t.Parallel()
+
+	config := fakeServerConfig()
+	config.Command = filepath.Join(t.TempDir(), "does-not-exist")
+
+	client := mcp.NewClient(config)
+	require.Error(t, client.Start(context.Background()))
+	assert.Equal(t, types.MCPStateFailed, client.Status().Status)
+
+	config = fakeServerConfig()
+	config.TransportType = types.TransportHTTP
+	config.TransportOptions = map[string]any{"port": 8080}
+
+	err := mcp.NewClient(config).Start(context.Background())
+	require.ErrorIs(t, err, mcp.ErrUnsupportedTransport)
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// ErrServerNotConfigured is returned when a step references a server with no configuration.
+var ErrServerNotConfigured = errors.New("MCP server is not configured")
+
+// ConfigLoader returns the MCP server configurations keyed by server name.
+type ConfigLoader func() (map[string]*types.MCPServerConfig, error)
+
+// Pool starts configured MCP servers under a supervisor the first time a
+// flow uses them, so runs that never call a server tool launch nothing.
+type Pool struct {
+	supervisor *Supervisor
+	load       ConfigLoader
+
+	mu      sync.Mutex
+	configs map[string]*types.MCPServerConfig
+	loadErr error
+	loaded  bool
+	starts  map[string]*serverStart
+}
+
+// serverStart is a server start in progress. Steps that need the server
+// while it starts wait for done and share its outcome.
+type serverStart struct {
+	done   chan struct{}
+	client *Client
+	err    error
+}
+
+// NewPool creates a pool that reads server configurations with load on first use.
+func NewPool(supervisor *Supervisor, load ConfigLoader) *Pool {
+	return &Pool{
+		supervisor: supervisor,
+		load:       load,
+		mu:         sync.Mutex{},
+		configs:    nil,
+		loadErr:    nil,
+		loaded:     false,
+		starts:     make(map[string]*serverStart),
+	}
+}
+
+// Client returns the client of a configured server, starting the server if
+// needed. Concurrent calls for a server that is not running yet share one
+// start and all wait for its handshake to finish.
+func (p *Pool) Client(ctx context.Context, name string) (*Client, error) {
+	client, err := p.supervisor.Client(name)
+	if err == nil {
+		return client, nil
+	}
+
+	config, err := p.config(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+
+	start, starting := p.starts[name]
+	if !starting {
+		start = &serverStart{done: make(chan struct{}), client: nil, err: nil}
+		p.starts[name] = start
+	}
+	p.mu.Unlock()
+
+	if starting {
+		select {
+		case <-start.done:
+			return start.client, start.err
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for MCP server %s to start: %w", name, ctx.Err())
+		}
+	}
+
+	start.client, start.err = p.supervisor.Start(ctx, *config)
+	if errors.Is(start.err, ErrDuplicateServer) {
+		// The server was started outside the pool.
+		start.client, start.err = p.supervisor.Client(name)
+	}
+
+	p.mu.Lock()
+	delete(p.starts, name)
+	p.mu.Unlock()
+	close(start.done)
+
+	return start.client, start.err
+}
+
+// CallTool calls a tool on the server named by call.ServerName.
+func (p *Pool) CallTool(ctx context.Context, call types.MCPToolCall) (*types.MCPToolResult, error) {
+	client, err := p.Client(ctx, call.ServerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CallTool(ctx, call)
+}
+
+// ListTools returns the tools offered by a configured server, starting it if needed.
+func (p *Pool) ListTools(ctx context.Context, server string) ([]types.MCPTool, error) {
+	client, err := p.Client(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Tools(), nil
+}
+
+// config returns the configuration of a server, loading every configuration once.
+func (p *Pool) config(name string) (*types.MCPServerConfig, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.loaded {
+		p.configs, p.loadErr = p.load()
+		p.loaded = true
+	}
+
+	if p.loadErr != nil {
+		return nil, fmt.Errorf("failed to load MCP server configurations: %w", p.loadErr)
+	}
+
+	config, exists := p.configs[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrServerNotConfigured, name)
+	}
+
+	return config, nil
+}
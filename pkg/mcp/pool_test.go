@@ -0,0 +1,128 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/mcp"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var errNoServers = errors.New("servers directory is unreadable")
+
+func TestPool_StartsServerOnFirstCall(t *testing.T) {
+	t.Parallel()
+
+	supervisor := newSupervisor(t)
+	loads := 0
+
+	pool := mcp.NewPool(supervisor, func() (map[string]*types.MCPServerConfig, error) {
+		loads++
+		config := fakeServerConfig()
+
+		return map[string]*types.MCPServerConfig{config.Name: &config}, nil
+	})
+
+	assert.Empty(t, supervisor.Statuses())
+
+	call := toolCall("echo", map[string]any{"text": "lazy"})
+
+	result, err := pool.CallTool(context.Background(), call)
+	require.NoError(t, err)
+	assert.Equal(t, "lazy", result.Result)
+
+	first, err := pool.Client(context.Background(), "fake")
+	require.NoError(t, err)
+
+	second, err := pool.Client(context.Background(), "fake")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, loads)
+
+	call.ServerName = "other"
+	_, err = pool.CallTool(context.Background(), call)
+	require.ErrorIs(t, err, mcp.ErrServerNotConfigured)
+}
+
+func TestPool_ConcurrentColdStart(t *testing.T) {
+	t.Parallel()
+
+	config := fakeServerConfig()
+	config.Env["FAKE_LIST_DELAY"] = "200ms"
+
+	supervisor := newSupervisor(t)
+	pool := mcp.NewPool(supervisor, func() (map[string]*types.MCPServerConfig, error) {
+		return map[string]*types.MCPServerConfig{config.Name: &config}, nil
+	})
+
+	const calls = 8
+
+	var wg sync.WaitGroup
+
+	results := make([]*types.MCPToolResult, calls)
+	errs := make([]error, calls)
+
+	for i := range calls {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i], errs[i] = pool.CallTool(context.Background(), toolCall("echo", map[string]any{"text": "cold"}))
+		}()
+	}
+
+	wg.Wait()
+
+	for i := range calls {
+		require.NoError(t, errs[i], "call %d", i)
+		assert.True(t, results[i].Success, "call %d", i)
+		assert.Equal(t, "cold", results[i].Result)
+	}
+
+	statuses := supervisor.Statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, 0, statuses[0].RestartCount, "the server is started once")
+}
+
+func TestPool_LoadError(t *testing.T) {
+	t.Parallel()
+
+	pool := mcp.NewPool(newSupervisor(t), func() (map[string]*types.MCPServerConfig, error) {
+		return nil, errNoServers
+	})
+
+	_, err := pool.Client(context.Background(), "fake")
+	require.ErrorIs(t, err, errNoServers)
+}
+
+func TestPool_StartsServerThatStayedDownAgain(t *testing.T) {
+	t.Parallel()
+
+	config := fakeServerConfig()
+
+	supervisor := newSupervisor(t)
+	pool := mcp.NewPool(supervisor, func() (map[string]*types.MCPServerConfig, error) {
+		return map[string]*types.MCPServerConfig{config.Name: &config}, nil
+	})
+
+	crashed, err := pool.Client(context.Background(), config.Name)
+	require.NoError(t, err)
+
+	_, _ = crashed.CallTool(context.Background(), toolCall("crash", nil))
+	waitForRelease(t, supervisor, config.Name)
+
+	result, err := pool.CallTool(context.Background(), toolCall("echo", map[string]any{"text": "again"}))
+	require.NoError(t, err)
+	assert.Equal(t, "again", result.Result)
+
+	client, err := pool.Client(context.Background(), config.Name)
+	require.NoError(t, err)
+	assert.NotSame(t, crashed, client, "the pool does not hand out the dead client")
+	assert.Equal(t, types.MCPStateFailed, crashed.Status().Status)
+}
@@ -0,0 +1,106 @@
+// Package mcp implements a Model Context Protocol client that launches MCP
+// servers over stdio and calls their tools.
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// ProtocolVersion is the MCP protocol revision requested during initialize.
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC version and error codes used on the wire.
+const (
+	jsonRPCVersion      = "2.0"
+	codeMethodNotFound  = -32601
+	notificationPrefix  = "notifications/"
+	methodInitialize    = "initialize"
+	methodInitialized   = "notifications/initialized"
+	methodPing          = "ping"
+	methodToolsList     = "tools/list"
+	methodToolsCall     = "tools/call"
+	methodResourcesList = "resources/list"
+)
+
+// rpcMessage is a JSON-RPC 2.0 request, notification or response.
+type rpcMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  any              `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *types.MCPError  `json:"error,omitempty"`
+}
+
+// initializeParams is sent by the client to open a session.
+type initializeParams struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ClientInfo      implementation `json:"clientInfo"`
+}
+
+// initializeResult is the server's answer to initialize.
+type initializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    serverCapabilities `json:"capabilities"`
+	ServerInfo      implementation     `json:"serverInfo"`
+}
+
+type implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// serverCapabilities lists the features a server advertises. Only presence matters.
+type serverCapabilities struct {
+	Tools     *json.RawMessage `json:"tools,omitempty"`
+	Resources *json.RawMessage `json:"resources,omitempty"`
+	Prompts   *json.RawMessage `json:"prompts,omitempty"`
+	Logging   *json.RawMessage `json:"logging,omitempty"`
+}
+
+type listParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type toolsListResult struct {
+	Tools      []toolDescriptor `json:"tools"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+type toolDescriptor struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema,omitempty"`
+}
+
+type resourcesListResult struct {
+	Resources  []resourceDescriptor `json:"resources"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+}
+
+type resourceDescriptor struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type toolCallParams struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+type toolCallResult struct {
+	Content []contentItem `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// contentItem is one block of tool output. Text blocks carry Text; other
+// kinds (image, resource) are kept as-is in the result metadata.
+type contentItem struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
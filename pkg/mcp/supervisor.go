@@ -0,0 +1,292 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// DefaultMaxRestarts is used when a server enables AutoRestart without
+// setting MaxRestarts.
+const DefaultMaxRestarts = 3
+
+// DefaultRestartDelay is the pause before the first restart of a crashed
+// server. Each further restart waits one more delay.
+const DefaultRestartDelay = 500 * time.Millisecond
+
+var (
+	// ErrDuplicateServer is returned when starting a server name twice.
+	ErrDuplicateServer = errors.New("MCP server is already supervised")
+
+	// ErrUnknownServer is returned when looking up a server that is not supervised.
+	ErrUnknownServer = errors.New("MCP server is not supervised")
+
+	// ErrSupervisorClosed is returned when starting a server after Close.
+	ErrSupervisorClosed = errors.New("MCP supervisor is closed")
+)
+
+// Supervisor owns the MCP server processes of a flow run. It pings each
+// server on its health check interval and restarts servers that crash or
+// fail a health check, up to MaxRestarts when AutoRestart is enabled.
+// Servers that stay down are no longer supervised.
+type Supervisor struct {
+	mu           sync.Mutex
+	clients      map[string]*Client
+	starting     map[string]bool
+	restartDelay time.Duration
+	ctx          context.Context //nolint:containedctx // bounds the lifetime of the monitor goroutines
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	closed       bool
+}
+
+// NewSupervisor creates an empty supervisor.
+func NewSupervisor() *Supervisor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Supervisor{
+		mu:           sync.Mutex{},
+		clients:      make(map[string]*Client),
+		starting:     make(map[string]bool),
+		restartDelay: DefaultRestartDelay,
+		ctx:          ctx,
+		cancel:       cancel,
+		wg:           sync.WaitGroup{},
+		closed:       false,
+	}
+}
+
+// SetRestartDelay sets the pause before restarting a crashed server.
+func (s *Supervisor) SetRestartDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.restartDelay = delay
+}
+
+// Start launches a server and begins monitoring it. The server only becomes
+// visible to Client once its handshake and tool listing succeeded, so callers
+// never get a client that is still starting.
+func (s *Supervisor) Start(ctx context.Context, config types.MCPServerConfig) (*Client, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+
+		return nil, ErrSupervisorClosed
+	}
+
+	if _, exists := s.clients[config.Name]; exists || s.starting[config.Name] {
+		s.mu.Unlock()
+
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateServer, config.Name)
+	}
+
+	s.starting[config.Name] = true
+	s.mu.Unlock()
+
+	client := NewClient(config)
+	err := client.Start(ctx)
+
+	s.mu.Lock()
+	delete(s.starting, config.Name)
+
+	if err != nil {
+		s.mu.Unlock()
+
+		return nil, err
+	}
+
+	if s.closed {
+		s.mu.Unlock()
+
+		return nil, errors.Join(ErrSupervisorClosed, client.Close())
+	}
+
+	s.clients[config.Name] = client
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go s.monitor(client)
+
+	return client, nil
+}
+
+// Client returns the client of a supervised server.
+func (s *Supervisor) Client(name string) (*Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, exists := s.clients[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownServer, name)
+	}
+
+	return client, nil
+}
+
+// Statuses returns the status of every supervised server, sorted by name.
+func (s *Supervisor) Statuses() []types.MCPServerStatus {
+	s.mu.Lock()
+	clients := make([]*Client, 0, len(s.clients))
+
+	for _, client := range s.clients {
+		clients = append(clients, client)
+	}
+	s.mu.Unlock()
+
+	statuses := make([]types.MCPServerStatus, 0, len(clients))
+	for _, client := range clients {
+		statuses = append(statuses, client.Status())
+	}
+
+	slices.SortFunc(statuses, func(a, b types.MCPServerStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses
+}
+
+// Close stops monitoring and shuts down every supervised server.
+func (s *Supervisor) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	s.closed = true
+	clients := make([]*Client, 0, len(s.clients))
+
+	for _, client := range s.clients {
+		clients = append(clients, client)
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+
+	var errs []error
+
+	for _, client := range clients {
+		err := client.Close()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// monitor watches one server until the supervisor closes or the server
+// can no longer be restarted.
+func (s *Supervisor) monitor(client *Client) {
+	defer s.wg.Done()
+
+	var ticks <-chan time.Time
+
+	healthCheck := client.config.HealthCheck
+	if healthCheck != nil && healthCheck.Enabled && healthCheck.Interval > 0 {
+		ticker := time.NewTicker(healthCheck.Interval)
+		defer ticker.Stop()
+
+		ticks = ticker.C
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticks:
+			s.checkHealth(client)
+		case <-client.Done():
+			if !s.restart(client) {
+				s.release(client)
+
+				return
+			}
+		}
+	}
+}
+
+// release stops supervising a server that stays down, so that Client no
+// longer returns it and the next Start launches the server again. The
+// released client keeps reporting why it failed.
+func (s *Supervisor) release(client *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.clients[client.Name()] == client {
+		delete(s.clients, client.Name())
+	}
+}
+
+// checkHealth pings a server and kills it when it does not answer so the
+// crash path restarts it.
+func (s *Supervisor) checkHealth(client *Client) {
+	healthCheck := client.config.HealthCheck
+
+	timeout := healthCheck.Timeout
+	if timeout <= 0 {
+		timeout = client.config.Timeout
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+
+	err := client.Ping(ctx, healthCheck.Command)
+	if err != nil && s.ctx.Err() == nil {
+		client.setFailed(err)
+		client.kill()
+	}
+}
+
+// restart relaunches a crashed server. It reports false when the server
+// must stay down: AutoRestart is off, the restart budget is spent or the
+// supervisor is closing.
+func (s *Supervisor) restart(client *Client) bool {
+	maxRestarts := client.config.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = DefaultMaxRestarts
+	}
+
+	if !client.config.AutoRestart {
+		return false
+	}
+
+	s.mu.Lock()
+	delay := s.restartDelay
+	s.mu.Unlock()
+
+	for {
+		if client.Status().RestartCount >= maxRestarts {
+			client.setState(types.MCPStateFailed)
+
+			return false
+		}
+
+		attempt := client.recordRestart()
+		client.setState(types.MCPStateRestarting)
+
+		select {
+		case <-s.ctx.Done():
+			return false
+		case <-time.After(delay * time.Duration(attempt)):
+		}
+
+		err := client.Start(s.ctx)
+		if err == nil {
+			return true
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/mcp"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+const (
+	waitTimeout  = 5 * time.Second
+	pollInterval = 10 * time.Millisecond
+)
+
+func newSupervisor(t *testing.T) *mcp.Supervisor {
+	t.Helper()
+
+	supervisor := mcp.NewSupervisor()
+	supervisor.SetRestartDelay(10 * time.Millisecond)
+	t.Cleanup(func() { _ = supervisor.Close() })
+
+	return supervisor
+}
+
+func waitForStatus(t *testing.T, client *mcp.Client, check func(types.MCPServerStatus) bool) types.MCPServerStatus {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return check(client.Status()) }, waitTimeout, pollInterval)
+
+	return client.Status()
+}
+
+// waitForRelease waits until the supervisor no longer supervises the server name.
+func waitForRelease(t *testing.T, supervisor *mcp.Supervisor, name string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		_, err := supervisor.Client(name)
+
+		return errors.Is(err, mcp.ErrUnknownServer)
+	}, waitTimeout, pollInterval)
+}
+
+func TestSupervisor_RestartsCrashedServer(t *testing.T) {
+	t.Parallel()
+
+	config := fakeServerConfig()
+	config.AutoRestart = true
+	config.MaxRestarts = 2
+
+	supervisor := newSupervisor(t)
+
+	client, err := supervisor.Start(context.Background(), config)
+	require.NoError(t, err)
+
+	firstPID := client.Status().PID
+
+	_, err = client.CallTool(context.Background(), toolCall("crash", nil))
+	require.Error(t, err)
+
+	status := waitForStatus(t, client, func(s types.MCPServerStatus) bool {
+		return s.RestartCount == 1 && s.Status == types.MCPStateRunning
+	})
+	assert.NotEqual(t, firstPID, status.PID)
+
+	result, err := client.CallTool(context.Background(), toolCall("echo", map[string]any{"text": "back"}))
+	require.NoError(t, err)
+	assert.Equal(t, "back", result.Result)
+}
+
+func TestSupervisor_StopsAfterMaxRestarts(t *testing.T) {
+	t.Parallel()
+
+	config := fakeServerConfig()
+	config.AutoRestart = true
+	config.MaxRestarts = 1
+
+	supervisor := newSupervisor(t)
+
+	client, err := supervisor.Start(context.Background(), config)
+	require.NoError(t, err)
+
+	_, _ = client.CallTool(context.Background(), toolCall("crash", nil))
+	waitForStatus(t, client, func(s types.MCPServerStatus) bool {
+		return s.RestartCount == 1 && s.Status == types.MCPStateRunning
+	})
+
+	_, _ = client.CallTool(context.Background(), toolCall("crash", nil))
+	status := waitForStatus(t, client, func(s types.MCPServerStatus) bool {
+		return s.Status == types.MCPStateFailed
+	})
+	assert.Equal(t, 1, status.RestartCount)
+	waitForRelease(t, supervisor, config.Name)
+}
+
+func TestSupervisor_NoAutoRestart(t *testing.T) {
+	t.Parallel()
+
+	supervisor := newSupervisor(t)
+
+	client, err := supervisor.Start(context.Background(), fakeServerConfig())
+	require.NoError(t, err)
+
+	_, _ = client.CallTool(context.Background(), toolCall("crash", nil))
+	<-client.Done()
+
+	status := waitForStatus(t, client, func(s types.MCPServerStatus) bool {
+		return s.Status == types.MCPStateFailed
+	})
+	assert.Equal(t, 0, status.RestartCount)
+	waitForRelease(t, supervisor, "fake")
+}
+
+func TestSupervisor_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	config := fakeServerConfig()
+	config.AutoRestart = true
+	config.HealthCheck = &types.MCPHealthCheck{
+		Enabled:  true,
+		Interval: 20 * time.Millisecond,
+		Timeout:  100 * time.Millisecond,
+		Command:  "",
+	}
+
+	supervisor := newSupervisor(t)
+
+	client, err := supervisor.Start(context.Background(), config)
+	require.NoError(t, err)
+
+	waitForStatus(t, client, func(s types.MCPServerStatus) bool { return !s.LastPing.IsZero() })
+
+	_, err = client.CallTool(context.Background(), toolCall("freeze", nil))
+	require.NoError(t, err)
+
+	status := waitForStatus(t, client, func(s types.MCPServerStatus) bool {
+		return s.RestartCount == 1 && s.Status == types.MCPStateRunning
+	})
+	assert.Equal(t, 1, status.RestartCount)
+
+	result, err := client.CallTool(context.Background(), toolCall("echo", map[string]any{"text": "alive"}))
+	require.NoError(t, err)
+	assert.Equal(t, "alive", result.Result)
+}
+
+func TestSupervisor_Lookup(t *testing.T) {
+	t.Parallel()
+
+	supervisor := newSupervisor(t)
+
+	_, err := supervisor.Start(context.Background(), fakeServerConfig())
+	require.NoError(t, err)
+
+	_, err = supervisor.Start(context.Background(), fakeServerConfig())
+	require.ErrorIs(t, err, mcp.ErrDuplicateServer)
+
+	client, err := supervisor.Client("fake")
+	require.NoError(t, err)
+	assert.Equal(t, "fake", client.Name())
+
+	_, err = supervisor.Client("other")
+	require.ErrorIs(t, err, mcp.ErrUnknownServer)
+
+	statuses := supervisor.Statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, types.MCPStateRunning, statuses[0].Status)
+
+	require.NoError(t, supervisor.Close())
+	assert.Equal(t, types.MCPStateStopped, client.Status().Status)
+
+	_, err = supervisor.Start(context.Background(), fakeServerConfig())
+	require.ErrorIs(t, err, mcp.ErrSupervisorClosed)
+}
@@ -0,0 +1,140 @@
+// Command fakeserver is a minimal MCP server used by the mcp package tests.
+// It speaks newline-delimited JSON-RPC on stdio and offers a few tools:
+// echo returns its text argument, greet returns FAKE_GREETING from the
+// environment, fail reports a tool error, crash exits the process and
+// freeze stops answering every later request. FAKE_LIST_DELAY, a duration,
+// slows down tool listing to widen the window in which the server starts.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// frozen is set by the freeze tool to simulate a hung server.
+var frozen bool
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   any             `json:"error,omitempty"`
+}
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+
+	for scanner.Scan() {
+		var msg message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			fmt.Fprintln(os.Stderr, "bad message:", err)
+			os.Exit(1)
+		}
+
+		if msg.ID == nil || frozen {
+			continue
+		}
+
+		result, rpcErr := handle(msg)
+		_ = encoder.Encode(message{JSONRPC: "2.0", ID: msg.ID, Result: result, Error: rpcErr})
+	}
+}
+
+func handle(msg message) (any, any) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": "2024-11-05",
+			"capabilities":    map[string]any{"tools": map[string]any{}, "resources": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "fake", "version": "0.1.0"},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		if delay, err := time.ParseDuration(os.Getenv("FAKE_LIST_DELAY")); err == nil {
+			time.Sleep(delay)
+		}
+
+		return toolsPage(msg.Params), nil
+	case "resources/list":
+		return map[string]any{"resources": []any{
+			map[string]any{"uri": "file:///readme.md", "name": "readme", "mimeType": "text/markdown"},
+		}}, nil
+	case "tools/call":
+		return callTool(msg.Params), nil
+	default:
+		return nil, map[string]any{"code": -32601, "message": "method not found: " + msg.Method}
+	}
+}
+
+// toolsPage splits the tool list over two pages to exercise pagination.
+func toolsPage(params json.RawMessage) any {
+	var list struct {
+		Cursor string `json:"cursor"`
+	}
+	_ = json.Unmarshal(params, &list)
+
+	if list.Cursor == "" {
+		return map[string]any{
+			"tools": []any{
+				tool("echo", "Echo the text argument"),
+				tool("greet", "Return the configured greeting"),
+			},
+			"nextCursor": "page-2",
+		}
+	}
+
+	return map[string]any{"tools": []any{
+		tool("fail", "Always fails"),
+		tool("crash", "Exit the server"),
+		tool("freeze", "Stop answering requests"),
+	}}
+}
+
+func tool(name, description string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"description": description,
+		"inputSchema": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"text": map[string]any{"type": "string"}},
+		},
+	}
+}
+
+func callTool(params json.RawMessage) any {
+	var call struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	}
+	_ = json.Unmarshal(params, &call)
+
+	switch call.Name {
+	case "echo":
+		return text(fmt.Sprint(call.Arguments["text"]), false)
+	case "greet":
+		return text(os.Getenv("FAKE_GREETING"), false)
+	case "freeze":
+		frozen = true
+
+		return text("frozen", false)
+	case "crash":
+		fmt.Fprintln(os.Stderr, "crashing on request")
+		os.Exit(3)
+	}
+
+	return text("something went wrong", true)
+}
+
+func text(value string, isError bool) any {
+	return map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": value}},
+		"isError": isError,
+	}
+}
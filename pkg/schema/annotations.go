@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// annotate adds what validation requires of the fields of a struct type to
+// its schema: required fields, allowed values and descriptions.
+func annotate(typ reflect.Type, object *Schema) {
+	switch typ {
+	case reflect.TypeFor[types.FlowDefinition]():
+		annotateFlow(object)
+	case reflect.TypeFor[types.Step]():
+		annotateStep(object)
+	case reflect.TypeFor[types.PromptConfig]():
+		require(object, "template")
+		describe(object, "template", "Prompt template, rendered with Go text/template.")
+		describe(object, "system", "System message, rendered like the template.")
+	case reflect.TypeFor[types.ToolConfig]():
+		require(object, "name")
+		nonEmpty(object, "name")
+		describe(object, "arguments", "Tool arguments; string values are rendered as templates.")
+	case reflect.TypeFor[types.ApprovalConfig]():
+		require(object, "question")
+		nonEmpty(object, "question")
+	case reflect.TypeFor[types.ParallelConfig]():
+		require(object, "branches")
+		nonEmpty(object, "branches")
+		describe(object, "branches", "Entry step of every branch.")
+		describe(object, "wait", "Number of branches that must complete before the join; 0 waits for all.")
+		object.Properties["wait"].Minimum = intPtr(0)
+		allow(object, "merge", types.MergeUnique, types.MergeOrdered)
+	case reflect.TypeFor[types.GitHubConfig]():
+		require(object, "operation")
+		allow(object, "operation", types.GitHubGetIssue, types.GitHubGetPullRequest, types.GitHubComment,
+			types.GitHubAddLabels, types.GitHubListReviewThreads, types.GitHubResolveReviewThread)
+	case reflect.TypeFor[types.ConditionConfig]():
+		require(object, "expression", "next")
+		describe(object, "expression", "Expression over vars, context and steps; the first that holds picks the next step.")
+	case reflect.TypeFor[types.RetryConfig]():
+		object.Properties["maxAttempts"].Minimum = intPtr(0)
+		allow(object, "backoff", types.BackoffFixed, types.BackoffLinear, types.BackoffExponential)
+		describe(object, "maxDelay", "Longest wait before a retry, in nanoseconds; 0 is 5 minutes.")
+	case reflect.TypeFor[types.BudgetConfig]():
+		describe(object, "maxTokens", "Tokens that may be spent; 0 is unlimited.")
+		describe(object, "maxCost", "Cost in dollars that may be spent; 0 is unlimited.")
+		describe(object, "warnAt", "Share of a limit, between 0 and 1, at which a warning is logged; 0 is 0.8.")
+		object.Properties["maxTokens"].Minimum = intPtr(0)
+		object.Properties["maxCost"].Minimum = intPtr(0)
+		object.Properties["warnAt"].Minimum = intPtr(0)
+	case reflect.TypeFor[types.MCPServerConfig]():
+		require(object, "name", "command", "transportType", "capabilities")
+		nonEmpty(object, "name")
+		nonEmpty(object, "command")
+		describe(object, "$schema", "URL of this schema.")
+		allow(object, "transportType", string(types.TransportStdio), string(types.TransportHTTP))
+	}
+}
+
+// annotateFlow annotates the schema of flow definitions.
+func annotateFlow(object *Schema) {
+	require(object, "id", "name", "steps")
+	nonEmpty(object, "id")
+	nonEmpty(object, "name")
+	nonEmpty(object, "steps")
+	describe(object, "$schema", "URL of this schema.")
+	describe(object, "version", "Flow format version, major.minor. Flows in an older version, or without one, "+
+		"are migrated when they are loaded; the migrate command rewrites them.")
+	describe(object, "id", "Flow ID; flows are stored as <id>.json, <id>.yaml or <id>.yml.")
+	describe(object, "variables", "Variables available to templates and expressions as vars.")
+	describe(object, "steps", "Steps of the flow by step ID.")
+	describe(object, "initialStep", "ID of the step the flow starts at.")
+	describe(object, "budget", "Tokens and cost the whole run may spend.")
+}
+
+// annotateStep annotates the schema of steps.
+func annotateStep(object *Schema) {
+	require(object, "type")
+
+	builtin := []string{
+		string(types.StepTypePrompt), string(types.StepTypeCondition), string(types.StepTypeEnd),
+		string(types.StepTypeGitHub), string(types.StepTypeTool), string(types.StepTypeApproval),
+		string(types.StepTypeParallel),
+	}
+
+	// Step types are extensible, so the built-in ones are documented rather than enforced.
+	describe(object, "type", "Step type: "+strings.Join(builtin, ", ")+", or a custom step type.")
+	describe(object, "model", "Model of a prompt step in provider/name form, such as openai/gpt-4o.")
+	describe(object, "tools", "Embedded tools and MCP tools a prompt step may call.")
+	describe(object, "mcpServer", "MCP server that provides the tool of a tool step.")
+	describe(object, "next", "ID of the step to run next; for condition steps, the fallback.")
+	describe(object, "maxVisits", "Times the step may run in one run; 0 is unlimited.")
+	object.Properties["maxVisits"].Minimum = intPtr(0)
+	describe(object, "budget", "Tokens and cost the step may spend.")
+}
+
+// require marks properties as required.
+func require(object *Schema, properties ...string) {
+	object.Required = append(object.Required, properties...)
+}
+
+// describe sets the description of a property.
+func describe(object *Schema, property, description string) {
+	object.Properties[property].Description = description
+}
+
+// allow restricts a string property to values.
+func allow(object *Schema, property string, values ...string) {
+	for _, value := range values {
+		object.Properties[property].Enum = append(object.Properties[property].Enum, value)
+	}
+}
+
+// nonEmpty rejects empty strings, lists and objects for a property.
+func nonEmpty(object *Schema, property string) {
+	schema := object.Properties[property]
+
+	switch schema.Type {
+	case "array":
+		schema.MinItems = intPtr(1)
+	case "object":
+		schema.MinProperties = intPtr(1)
+	default:
+		schema.MinLength = intPtr(1)
+	}
+}
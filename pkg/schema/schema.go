@@ -0,0 +1,201 @@
+// Package schema generates the JSON Schemas of flow and MCP server files
+// from the Go types they decode into.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// IDs of the published schemas, to reference from the $schema field of flow
+// and MCP server files.
+const (
+	FlowID      = "https://raw.githubusercontent.com/ondatra-ai/flow-test-go/main/schemas/flow.schema.json"
+	MCPServerID = "https://raw.githubusercontent.com/ondatra-ai/flow-test-go/main/schemas/mcp-server.schema.json"
+)
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Minimum     *int   `json:"minimum,omitempty"`
+	// MinLength, MinItems and MinProperties are set for values that must not be empty.
+	MinLength     *int               `json:"minLength,omitempty"`
+	MinItems      *int               `json:"minItems,omitempty"`
+	MinProperties *int               `json:"minProperties,omitempty"`
+	Properties    map[string]*Schema `json:"properties,omitempty"`
+	Required      []string           `json:"required,omitempty"`
+	// AdditionalProperties is false for structs, whose fields are all
+	// known, and the schema of the values for maps.
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Flow returns the JSON Schema of flow files.
+func Flow() *Schema {
+	return generate(reflect.TypeFor[types.FlowDefinition](), FlowID, "Flow definition")
+}
+
+// MCPServer returns the JSON Schema of MCP server files.
+func MCPServer() *Schema {
+	return generate(reflect.TypeFor[types.MCPServerConfig](), MCPServerID, "MCP server configuration")
+}
+
+// Marshal formats a schema as indented JSON ending with a newline, as the
+// published schema files are written.
+func Marshal(schema *Schema) ([]byte, error) {
+	var out bytes.Buffer
+
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// generate returns the schema of a struct type as a document with its
+// nested struct types in $defs.
+func generate(typ reflect.Type, id, title string) *Schema {
+	gen := &generator{defs: make(map[string]*Schema)}
+
+	document := gen.structSchema(typ)
+	document.Schema = Draft
+	document.ID = id
+	document.Title = title
+	document.Defs = gen.defs
+
+	return document
+}
+
+// generator builds schemas and collects the definitions they reference.
+type generator struct {
+	defs map[string]*Schema
+}
+
+// durationType is encoded as integer nanoseconds.
+var durationType = reflect.TypeFor[time.Duration]()
+
+// schemaOf returns the schema of values of a type.
+func (g *generator) schemaOf(typ reflect.Type) *Schema {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == durationType:
+		duration := newSchema("integer")
+		duration.Description = "Duration in nanoseconds."
+		duration.Minimum = intPtr(0)
+
+		return duration
+	case typ.Kind() == reflect.Struct:
+		if _, defined := g.defs[typ.Name()]; !defined {
+			g.defs[typ.Name()] = nil // placeholder for recursive types
+			g.defs[typ.Name()] = g.structSchema(typ)
+		}
+
+		ref := newSchema("")
+		ref.Ref = "#/$defs/" + typ.Name()
+
+		return ref
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return newSchema("string")
+	case reflect.Bool:
+		return newSchema("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return newSchema("integer")
+	case reflect.Float32, reflect.Float64:
+		return newSchema("number")
+	case reflect.Slice, reflect.Array:
+		list := newSchema("array")
+		list.Items = g.schemaOf(typ.Elem())
+
+		return list
+	case reflect.Map:
+		object := newSchema("object")
+		if typ.Elem().Kind() != reflect.Interface {
+			object.AdditionalProperties = g.schemaOf(typ.Elem())
+		}
+
+		return object
+	default:
+		return newSchema("")
+	}
+}
+
+// structSchema returns the schema of a struct type with its annotations applied.
+func (g *generator) structSchema(typ reflect.Type) *Schema {
+	object := newSchema("object")
+	object.Properties = make(map[string]*Schema, typ.NumField())
+	object.AdditionalProperties = false
+
+	for index := range typ.NumField() {
+		field := typ.Field(index)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		object.Properties[name] = g.schemaOf(field.Type)
+	}
+
+	annotate(typ, object)
+
+	return object
+}
+
+// newSchema returns a schema of a JSON type; an empty type accepts any value.
+func newSchema(schemaType string) *Schema {
+	return &Schema{
+		Schema:               "",
+		ID:                   "",
+		Ref:                  "",
+		Title:                "",
+		Description:          "",
+		Type:                 schemaType,
+		Enum:                 nil,
+		Minimum:              nil,
+		MinLength:            nil,
+		MinItems:             nil,
+		MinProperties:        nil,
+		Properties:           nil,
+		Required:             nil,
+		AdditionalProperties: nil,
+		Items:                nil,
+		Defs:                 nil,
+	}
+}
+
+// intPtr returns a pointer to value.
+func intPtr(value int) *int {
+	return &value
+}
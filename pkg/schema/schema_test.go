@@ -0,0 +1,67 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package schema_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/schema"
+)
+
+func TestFlow(t *testing.T) {
+	t.Parallel()
+
+	flow := schema.Flow()
+
+	assert.Equal(t, schema.Draft, flow.Schema)
+	assert.Equal(t, schema.FlowID, flow.ID)
+	assert.Equal(t, false, flow.AdditionalProperties)
+	assert.Equal(t, []string{"id", "name", "steps"}, flow.Required)
+	assert.Contains(t, flow.Properties, "$schema")
+	assert.Equal(t, "#/$defs/Step", flow.Properties["steps"].AdditionalProperties.(*schema.Schema).Ref)
+
+	step := flow.Defs["Step"]
+	require.NotNil(t, step)
+	assert.Equal(t, []string{"type"}, step.Required)
+	assert.Equal(t, false, step.AdditionalProperties)
+	assert.Equal(t, "#/$defs/PromptConfig", step.Properties["prompt"].Ref)
+	assert.Equal(t, "#/$defs/ConditionConfig", step.Properties["conditions"].Items.Ref)
+	assert.Equal(t, "integer", step.Properties["timeout"].Type)
+	assert.Equal(t, []any{"fixed", "linear", "exponential"}, flow.Defs["RetryConfig"].Properties["backoff"].Enum)
+	assert.Equal(t, "object", step.Properties["metadata"].Type)
+	assert.Nil(t, step.Properties["metadata"].AdditionalProperties)
+}
+
+func TestMCPServer(t *testing.T) {
+	t.Parallel()
+
+	server := schema.MCPServer()
+
+	assert.Equal(t, schema.MCPServerID, server.ID)
+	assert.Equal(t, []string{"name", "command", "transportType", "capabilities"}, server.Required)
+	assert.Equal(t, []any{"stdio", "http"}, server.Properties["transportType"].Enum)
+	assert.Equal(t, "#/$defs/MCPCapabilities", server.Properties["capabilities"].Ref)
+	assert.Contains(t, server.Defs, "MCPHealthCheck")
+}
+
+func TestPublishedSchemas(t *testing.T) {
+	t.Parallel()
+
+	for name, document := range map[string]*schema.Schema{
+		"flow.schema.json":       schema.Flow(),
+		"mcp-server.schema.json": schema.MCPServer(),
+	} {
+		generated, err := schema.Marshal(document)
+		require.NoError(t, err)
+		require.True(t, json.Valid(generated))
+
+		published, err := os.ReadFile(filepath.Join("..", "..", "schemas", name))
+		require.NoError(t, err)
+		assert.Equal(t, string(generated), string(published), "%s is out of date: run make schemas", name)
+	}
+}
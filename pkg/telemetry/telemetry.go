@@ -0,0 +1,190 @@
+// Package telemetry exports OpenTelemetry traces of flow runs. A run is a
+// span with a child span per step; prompt and tool steps add child spans
+// for every LLM request and tool call they make.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the instrumentation scope of the spans of flow runs.
+const TracerName = "github.com/ondatra-ai/flow-test-go"
+
+// tracesPath is the OTLP/HTTP path of traces, used when an endpoint has no path.
+const tracesPath = "/v1/traces"
+
+const (
+	dirPerms  = 0o750
+	filePerms = 0o600
+)
+
+// Span attributes of runs and steps.
+const (
+	FlowIDKey     = attribute.Key("flow.id")
+	SessionIDKey  = attribute.Key("flow.session_id")
+	RunStatusKey  = attribute.Key("flow.status")
+	StepIDKey     = attribute.Key("flow.step.id")
+	StepTypeKey   = attribute.Key("flow.step.type")
+	StepStatusKey = attribute.Key("flow.step.status")
+	AttemptsKey   = attribute.Key("flow.step.attempts")
+	StepsKey      = attribute.Key("flow.steps")
+	TokensUsedKey = attribute.Key("flow.tokens_used")
+	CostKey       = attribute.Key("flow.cost")
+	ErrorCodeKey  = attribute.Key("error.type")
+)
+
+// Span attributes of LLM requests, following the OpenTelemetry GenAI conventions.
+const (
+	GenAIOperationKey     = attribute.Key("gen_ai.operation.name")
+	GenAIRequestModelKey  = attribute.Key("gen_ai.request.model")
+	GenAIResponseModelKey = attribute.Key("gen_ai.response.model")
+	GenAIInputTokensKey   = attribute.Key("gen_ai.usage.input_tokens")
+	GenAIOutputTokensKey  = attribute.Key("gen_ai.usage.output_tokens")
+	GenAIFinishReasonKey  = attribute.Key("gen_ai.response.finish_reasons")
+	IterationKey          = attribute.Key("flow.prompt.iteration")
+)
+
+// Span attributes of tool calls.
+const (
+	ToolNameKey    = attribute.Key("mcp.tool.name")
+	ToolServerKey  = attribute.Key("mcp.server.name")
+	ToolCallIDKey  = attribute.Key("mcp.tool.call_id")
+	ToolSuccessKey = attribute.Key("mcp.tool.success")
+)
+
+// ErrInvalidEndpoint is returned for OTLP endpoints that are not http or https URLs.
+var ErrInvalidEndpoint = errors.New("invalid OTLP endpoint")
+
+// Options configures where traces are exported.
+type Options struct {
+	// ServiceName and ServiceVersion identify the exporting service.
+	ServiceName    string
+	ServiceVersion string
+	// Endpoint is the OTLP/HTTP URL traces are sent to, such as
+	// http://localhost:4318. An endpoint without a path gets /v1/traces.
+	Endpoint string
+	// Headers are sent with every export, such as authentication headers.
+	Headers map[string]string
+	// File receives the spans as JSON lines, for offline use.
+	File string
+}
+
+// Enabled reports whether options export traces anywhere.
+func (o Options) Enabled() bool {
+	return o.Endpoint != "" || o.File != ""
+}
+
+// Shutdown flushes the spans that were not exported yet and releases the exporters.
+type Shutdown func(ctx context.Context) error
+
+// NewTracerProvider returns the tracer provider exporting to the endpoint
+// and file of options. Without either, spans are not recorded at all.
+func NewTracerProvider(ctx context.Context, options Options) (trace.TracerProvider, Shutdown, error) {
+	if !options.Enabled() {
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	}
+
+	providerOptions := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", options.ServiceName),
+			attribute.String("service.version", options.ServiceVersion),
+		)),
+	}
+
+	if options.Endpoint != "" {
+		exporter, err := newOTLPExporter(ctx, options)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		providerOptions = append(providerOptions, sdktrace.WithBatcher(exporter))
+	}
+
+	var file *os.File
+
+	if options.File != "" {
+		var (
+			exporter sdktrace.SpanExporter
+			err      error
+		)
+
+		file, exporter, err = newFileExporter(options.File)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		providerOptions = append(providerOptions, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(providerOptions...)
+
+	return provider, func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to flush traces: %w", err)
+		}
+
+		return nil
+	}, nil
+}
+
+// newOTLPExporter creates the exporter sending spans to the OTLP/HTTP endpoint of options.
+func newOTLPExporter(ctx context.Context, options Options) (*otlptrace.Exporter, error) {
+	endpoint, err := url.Parse(options.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("%w %q: use an http or https URL", ErrInvalidEndpoint, options.Endpoint)
+	}
+
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = tracesPath
+	}
+
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(endpoint.String()),
+		otlptracehttp.WithHeaders(options.Headers))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// newFileExporter creates the exporter appending spans to path as JSON lines.
+func newFileExporter(path string) (*os.File, sdktrace.SpanExporter, error) {
+	err := os.MkdirAll(filepath.Dir(path), dirPerms)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create trace directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerms) // #nosec G304
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		_ = file.Close()
+
+		return nil, nil, fmt.Errorf("failed to create trace file exporter: %w", err)
+	}
+
+	return file, exporter, nil
+}
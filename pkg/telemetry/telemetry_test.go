@@ -0,0 +1,86 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/telemetry"
+)
+
+func TestNewTracerProvider_Disabled(t *testing.T) {
+	t.Parallel()
+
+	provider, shutdown, err := telemetry.NewTracerProvider(context.Background(), telemetry.Options{ServiceName: "flow-test-go"})
+	require.NoError(t, err)
+
+	_, span := provider.Tracer(telemetry.TracerName).Start(context.Background(), "step fetch")
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	require.NoError(t, shutdown(context.Background()))
+}
+
+func TestNewTracerProvider_File(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "traces", "spans.jsonl")
+
+	provider, shutdown, err := telemetry.NewTracerProvider(context.Background(), telemetry.Options{
+		ServiceName:    "flow-test-go",
+		ServiceVersion: "1.0.0",
+		File:           path,
+	})
+	require.NoError(t, err)
+
+	ctx, run := provider.Tracer(telemetry.TracerName).Start(context.Background(), "flow review")
+	_, step := provider.Tracer(telemetry.TracerName).Start(ctx, "step fetch")
+	step.SetAttributes(telemetry.StepIDKey.String("fetch"))
+	step.End()
+	run.End()
+
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var span struct {
+		Name   string
+		Parent struct{ SpanID string }
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &span))
+	assert.Equal(t, "step fetch", span.Name)
+	assert.Equal(t, run.SpanContext().SpanID().String(), span.Parent.SpanID)
+	assert.Contains(t, lines[0], `"Key":"flow.step.id"`)
+	assert.Contains(t, lines[0], `"Value":"flow-test-go"`)
+}
+
+func TestNewTracerProvider_InvalidEndpoint(t *testing.T) {
+	t.Parallel()
+
+	for _, endpoint := range []string{"localhost:4318", "grpc://collector:4317", "http://"} {
+		_, _, err := telemetry.NewTracerProvider(context.Background(), telemetry.Options{Endpoint: endpoint})
+		require.ErrorIs(t, err, telemetry.ErrInvalidEndpoint, endpoint)
+	}
+}
+
+func TestNewTracerProvider_Endpoint(t *testing.T) {
+	t.Parallel()
+
+	_, shutdown, err := telemetry.NewTracerProvider(context.Background(), telemetry.Options{
+		Endpoint: "http://localhost:4318",
+		Headers:  map[string]string{"Authorization": "Bearer abc"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
@@ -0,0 +1,178 @@
+// Package tools provides the registry of embedded tools that flow steps can
+// call in-process, alongside the tools offered by MCP servers.
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+// EmbeddedServer is the server name reported for embedded tools.
+const EmbeddedServer = "embedded"
+
+// CodeToolFailed is reported when an embedded tool returns an error.
+const CodeToolFailed = "TOOL_FAILED"
+
+var (
+	// ErrDuplicateTool is returned when a tool name is already registered.
+	ErrDuplicateTool = errors.New("tool is already registered")
+
+	// ErrInvalidTool is returned when registering a nil tool or one without a name.
+	ErrInvalidTool = errors.New("tool and tool name are required")
+
+	// ErrToolNotFound is returned when calling a tool that is not registered.
+	ErrToolNotFound = errors.New("embedded tool not found")
+)
+
+// Tool is an embedded tool. Definition describes the tool and the JSON
+// Schema of its arguments; Call runs it and returns its result.
+type Tool interface {
+	Definition() types.MCPTool
+	Call(ctx context.Context, arguments map[string]any) (any, error)
+}
+
+// Func adapts an ordinary function to the Tool interface.
+type Func struct {
+	Name        string
+	Description string
+	Schema      map[string]any
+	Fn          func(ctx context.Context, arguments map[string]any) (any, error)
+}
+
+// Registry holds the embedded tools available to flows.
+// It is safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+}
+
+// NewRegistry creates an empty tool registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		mu:    sync.RWMutex{},
+		tools: make(map[string]Tool),
+	}
+}
+
+// Register adds tool under the name from its definition.
+func (r *Registry) Register(tool Tool) error {
+	if tool == nil {
+		return ErrInvalidTool
+	}
+
+	name := tool.Definition().Name
+	if name == "" {
+		return ErrInvalidTool
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateTool, name)
+	}
+
+	r.tools[name] = tool
+
+	return nil
+}
+
+// Lookup returns the tool registered under name.
+func (r *Registry) Lookup(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tool, exists := r.tools[name]
+
+	return tool, exists
+}
+
+// Definitions returns the definitions of every registered tool, sorted by name.
+func (r *Registry) Definitions() []types.MCPTool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]types.MCPTool, 0, len(r.tools))
+	for _, tool := range r.tools {
+		definitions = append(definitions, definition(tool))
+	}
+
+	slices.SortFunc(definitions, func(a, b types.MCPTool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return definitions
+}
+
+// ListTools returns the definitions of every registered tool. Embedded
+// tools belong to no server, so server is ignored.
+func (r *Registry) ListTools(_ context.Context, _ string) ([]types.MCPTool, error) {
+	return r.Definitions(), nil
+}
+
+// CallTool runs an embedded tool. An unknown tool is returned as an error;
+// a tool that fails produces a result with Success false, matching how MCP
+// servers report tool errors.
+func (r *Registry) CallTool(ctx context.Context, call types.MCPToolCall) (*types.MCPToolResult, error) {
+	tool, exists := r.Lookup(call.ToolName)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, call.ToolName)
+	}
+
+	start := time.Now()
+	value, err := tool.Call(ctx, call.Arguments)
+
+	result := &types.MCPToolResult{
+		CallID:    call.ID,
+		Success:   err == nil,
+		Result:    value,
+		Error:     nil,
+		Duration:  time.Since(start),
+		Timestamp: time.Now(),
+		Metadata:  map[string]any{"server": EmbeddedServer},
+	}
+
+	if err != nil {
+		result.Error = &types.ExecutionError{
+			Code:        CodeToolFailed,
+			Message:     fmt.Sprintf("tool %s failed: %v", call.ToolName, err),
+			Details:     map[string]any{"tool": call.ToolName, "server": EmbeddedServer},
+			Recoverable: false,
+			Timestamp:   time.Now(),
+			StackTrace:  "",
+		}
+	}
+
+	return result, nil
+}
+
+// Definition describes the function tool.
+func (f Func) Definition() types.MCPTool {
+	return types.MCPTool{
+		Name:        f.Name,
+		Description: f.Description,
+		Schema:      f.Schema,
+		ServerName:  EmbeddedServer,
+		Metadata:    nil,
+	}
+}
+
+// Call calls f.Fn(ctx, arguments).
+func (f Func) Call(ctx context.Context, arguments map[string]any) (any, error) {
+	return f.Fn(ctx, arguments)
+}
+
+// definition returns the tool definition with its server name set.
+func definition(tool Tool) types.MCPTool {
+	def := tool.Definition()
+	def.ServerName = EmbeddedServer
+
+	return def
+}
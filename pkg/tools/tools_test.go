@@ -0,0 +1,76 @@
+//nolint:exhaustruct // Test files don't need to initialize all struct fields
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/tools"
+	"github.com/ondatra-ai/flow-test-go/pkg/types"
+)
+
+var errBoom = errors.New("boom")
+
+func upperTool() tools.Func {
+	return tools.Func{
+		Name:        "upper",
+		Description: "Upper-case the text argument",
+		Schema:      map[string]any{"type": "object"},
+		Fn: func(_ context.Context, arguments map[string]any) (any, error) {
+			text, _ := arguments["text"].(string)
+			if text == "" {
+				return nil, errBoom
+			}
+
+			return map[string]any{"text": text + "!"}, nil
+		},
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	registry := tools.NewRegistry()
+	require.NoError(t, registry.Register(upperTool()))
+	require.NoError(t, registry.Register(tools.Func{Name: "alpha"}))
+
+	require.ErrorIs(t, registry.Register(upperTool()), tools.ErrDuplicateTool)
+	require.ErrorIs(t, registry.Register(nil), tools.ErrInvalidTool)
+	require.ErrorIs(t, registry.Register(tools.Func{}), tools.ErrInvalidTool)
+
+	definitions := registry.Definitions()
+	require.Len(t, definitions, 2)
+	assert.Equal(t, "alpha", definitions[0].Name)
+	assert.Equal(t, "upper", definitions[1].Name)
+	assert.Equal(t, tools.EmbeddedServer, definitions[1].ServerName)
+}
+
+func TestRegistry_CallTool(t *testing.T) {
+	t.Parallel()
+
+	registry := tools.NewRegistry()
+	require.NoError(t, registry.Register(upperTool()))
+
+	result, err := registry.CallTool(context.Background(), types.MCPToolCall{
+		ID:        "call-1",
+		ToolName:  "upper",
+		Arguments: map[string]any{"text": "hi"},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "call-1", result.CallID)
+	assert.Equal(t, map[string]any{"text": "hi!"}, result.Result)
+
+	result, err = registry.CallTool(context.Background(), types.MCPToolCall{ID: "call-2", ToolName: "upper"})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, tools.CodeToolFailed, result.Error.Code)
+
+	_, err = registry.CallTool(context.Background(), types.MCPToolCall{ToolName: "missing"})
+	require.ErrorIs(t, err, tools.ErrToolNotFound)
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Severity is how serious a diagnostic is.
+type Severity string
+
+const (
+	// SeverityError marks a problem that keeps the flow from loading or running.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a problem that the flow can run with but is likely a mistake.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in a flow definition.
+type Diagnostic struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// StepID is the step the problem was found in; empty for flow-level problems.
+	StepID string `json:"stepId,omitempty"`
+	// Path is the JSON path of the offending value, such as $.steps.review.next.
+	Path     string   `json:"path"`
+	Severity Severity `json:"severity"`
+	Details  any      `json:"details,omitempty"`
+	// Line and Column locate the offending value in the flow file, starting
+	// at 1; both are 0 when the position is not known.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// identifierPattern matches keys that can be written in dot notation in a JSON path.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// ExecutionError converts the diagnostic into the error Validate returns.
+// The message ends with the position of the problem when it is known.
+func (d Diagnostic) ExecutionError() *ExecutionError {
+	message := d.Message
+	if d.Line > 0 {
+		message += fmt.Sprintf(" (line %d, column %d)", d.Line, d.Column)
+	}
+
+	return &ExecutionError{
+		Code:        d.Code,
+		Message:     message,
+		Details:     d.Details,
+		Recoverable: false,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
+
+// FirstError returns the first error-severity diagnostic as an error, or
+// nil when there is none.
+func FirstError(diagnostics []Diagnostic) error {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			return diagnostic.ExecutionError()
+		}
+	}
+
+	return nil
+}
+
+// StepPath returns the JSON path of field within a step, or of the step
+// itself when field is empty.
+func StepPath(stepID, field string) string {
+	path := JSONPath("$.steps", stepID)
+	if field == "" {
+		return path
+	}
+
+	return path + "." + field
+}
+
+// JSONPath appends key to a JSON path, in bracket notation when it cannot
+// be written in dot notation.
+func JSONPath(parent, key string) string {
+	if identifierPattern.MatchString(key) {
+		return parent + "." + key
+	}
+
+	return parent + "[" + strconv.Quote(key) + "]"
+}
+
+// diagnostics collects the problems found while validating a flow.
+type diagnostics struct {
+	list []Diagnostic
+}
+
+// add records a diagnostic.
+func (d *diagnostics) add(diagnostic Diagnostic) {
+	d.list = append(d.list, diagnostic)
+}
+
+// flowError records an INVALID_FLOW error for a flow-level field.
+func (d *diagnostics) flowError(path, message string) {
+	d.add(Diagnostic{
+		Code:     "INVALID_FLOW",
+		Message:  message,
+		StepID:   "",
+		Path:     path,
+		Severity: SeverityError,
+		Details:  nil,
+		Line:     0,
+		Column:   0,
+	})
+}
+
+// stepError records an INVALID_STEP error for field of a step.
+func (d *diagnostics) stepError(stepID, field, message string) {
+	d.add(Diagnostic{
+		Code:     "INVALID_STEP",
+		Message:  message,
+		StepID:   stepID,
+		Path:     StepPath(stepID, field),
+		Severity: SeverityError,
+		Details:  map[string]any{"stepId": stepID},
+		Line:     0,
+		Column:   0,
+	})
+}
+
+// reference records an INVALID_REFERENCE error for field of a step.
+func (d *diagnostics) reference(stepID, field, message string, details map[string]any) {
+	d.add(Diagnostic{
+		Code:     "INVALID_REFERENCE",
+		Message:  message,
+		StepID:   stepID,
+		Path:     StepPath(stepID, field),
+		Severity: SeverityError,
+		Details:  details,
+		Line:     0,
+		Column:   0,
+	})
+}
@@ -2,7 +2,11 @@
 package types
 
 import (
+	"fmt"
+	"slices"
 	"time"
+
+	"github.com/ondatra-ai/flow-test-go/pkg/expr"
 )
 
 // FlowDefinition represents a complete flow configuration.
@@ -15,19 +19,33 @@ type FlowDefinition struct {
 	Variables   map[string]string `json:"variables,omitempty"   yaml:"variables,omitempty"`
 	Steps       map[string]Step   `json:"steps"                 yaml:"steps"`
 	InitialStep string            `json:"initialStep,omitempty" yaml:"initialStep,omitempty"`
+	Budget      *BudgetConfig     `json:"budget,omitempty"      yaml:"budget,omitempty"`
 }
 
 // Step represents a single step in a flow.
+//
+// MaxVisits bounds how many times the step may complete in one run; a run
+// that reaches the step once more fails. It marks loops through the step as
+// intentional. Zero leaves the step unbounded.
+//
+// Budget bounds the tokens and cost of the step itself; the flow's Budget
+// bounds the whole run.
 type Step struct {
 	Type       StepType          `json:"type"                 yaml:"type"`
 	Prompt     *PromptConfig     `json:"prompt,omitempty"     yaml:"prompt,omitempty"`
 	Model      string            `json:"model,omitempty"      yaml:"model,omitempty"`
 	Tools      []string          `json:"tools,omitempty"      yaml:"tools,omitempty"`
 	MCPServer  string            `json:"mcpServer,omitempty"  yaml:"mcpServer,omitempty"`
+	Tool       *ToolConfig       `json:"tool,omitempty"       yaml:"tool,omitempty"`
+	Approval   *ApprovalConfig   `json:"approval,omitempty"   yaml:"approval,omitempty"`
+	Parallel   *ParallelConfig   `json:"parallel,omitempty"   yaml:"parallel,omitempty"`
+	GitHub     *GitHubConfig     `json:"github,omitempty"     yaml:"github,omitempty"`
 	Next       string            `json:"next,omitempty"       yaml:"next,omitempty"`
 	Conditions []ConditionConfig `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 	Timeout    *time.Duration    `json:"timeout,omitempty"    yaml:"timeout,omitempty"`
 	Retry      *RetryConfig      `json:"retry,omitempty"      yaml:"retry,omitempty"`
+	MaxVisits  int               `json:"maxVisits,omitempty"  yaml:"maxVisits,omitempty"`
+	Budget     *BudgetConfig     `json:"budget,omitempty"     yaml:"budget,omitempty"`
 	Metadata   map[string]any    `json:"metadata,omitempty"   yaml:"metadata,omitempty"`
 }
 
@@ -45,6 +63,10 @@ const (
 	StepTypeGitHub StepType = "github"
 	// StepTypeTool represents a tool step type.
 	StepTypeTool StepType = "tool"
+	// StepTypeApproval represents a human approval step type.
+	StepTypeApproval StepType = "approval"
+	// StepTypeParallel represents a step type that runs branches concurrently.
+	StepTypeParallel StepType = "parallel"
 )
 
 // PromptConfig defines the configuration for a prompt step.
@@ -54,6 +76,64 @@ type PromptConfig struct {
 	Context  map[string]any `json:"context,omitempty" yaml:"context,omitempty"`
 }
 
+// ToolConfig defines the tool a tool step calls and the arguments it passes.
+// String arguments are rendered as templates before the call.
+type ToolConfig struct {
+	Name      string         `json:"name"                yaml:"name"`
+	Arguments map[string]any `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+// ApprovalConfig defines the question an approval step asks before the run continues.
+// The question is rendered as a template.
+type ApprovalConfig struct {
+	Question string `json:"question" yaml:"question"`
+}
+
+// ParallelConfig defines the branches a parallel step starts. Every branch
+// runs from its entry step until it reaches the parallel step's next step,
+// which joins the branches, or until the flow ends.
+type ParallelConfig struct {
+	// Branches lists the entry step of every branch.
+	Branches []string `json:"branches"        yaml:"branches"`
+	// Wait is how many branches must complete before the join; 0 waits for all.
+	Wait int `json:"wait,omitempty"  yaml:"wait,omitempty"`
+	// Merge is the strategy for merging branch step results; empty is MergeUnique.
+	Merge string `json:"merge,omitempty" yaml:"merge,omitempty"`
+}
+
+// Merge strategies for ParallelConfig.Merge.
+const (
+	// MergeUnique fails the parallel step when more than one branch ran the same step.
+	MergeUnique = "unique"
+	// MergeOrdered keeps the result of the branch listed last when branches ran the same step.
+	MergeOrdered = "ordered"
+)
+
+// GitHubConfig defines the GitHub operation a github step performs.
+// String arguments are rendered as templates before the call; owner and
+// repository default to the configured repository.
+type GitHubConfig struct {
+	Operation string         `json:"operation"           yaml:"operation"`
+	Arguments map[string]any `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+// Operations for GitHubConfig.Operation.
+const (
+	// GitHubGetIssue reads the issue given by the number argument.
+	GitHubGetIssue = "get_issue"
+	// GitHubGetPullRequest reads the pull request given by the number argument.
+	GitHubGetPullRequest = "get_pull_request"
+	// GitHubComment posts the body argument as a comment on an issue or pull request.
+	GitHubComment = "comment"
+	// GitHubAddLabels adds the labels argument to an issue or pull request.
+	GitHubAddLabels = "add_labels"
+	// GitHubListReviewThreads lists the review threads of a pull request.
+	GitHubListReviewThreads = "list_review_threads"
+	// GitHubResolveReviewThread resolves the thread given by the threadId
+	// argument, replying with the optional body argument first.
+	GitHubResolveReviewThread = "resolve_review_thread"
+)
+
 // ConditionConfig defines a condition for conditional step execution.
 type ConditionConfig struct {
 	Expression string `json:"expression" yaml:"expression"`
@@ -62,11 +142,41 @@ type ConditionConfig struct {
 
 // RetryConfig defines retry behavior.
 type RetryConfig struct {
-	MaxAttempts int           `json:"maxAttempts"       yaml:"maxAttempts"`
-	Delay       time.Duration `json:"delay"             yaml:"delay"`
-	Backoff     string        `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	MaxAttempts int           `json:"maxAttempts"        yaml:"maxAttempts"`
+	Delay       time.Duration `json:"delay"              yaml:"delay"`
+	Backoff     string        `json:"backoff,omitempty"  yaml:"backoff,omitempty"`
+	// MaxDelay caps the wait before a retry; 0 is DefaultMaxRetryDelay.
+	MaxDelay time.Duration `json:"maxDelay,omitempty" yaml:"maxDelay,omitempty"`
 }
 
+// DefaultMaxRetryDelay caps the wait before a retry of steps whose retry
+// configuration sets no MaxDelay.
+const DefaultMaxRetryDelay = 5 * time.Minute
+
+// Backoff strategies for RetryConfig.Backoff. An empty backoff is fixed.
+const (
+	// BackoffFixed waits Delay before every retry.
+	BackoffFixed = "fixed"
+	// BackoffLinear waits Delay multiplied by the number of failed attempts.
+	BackoffLinear = "linear"
+	// BackoffExponential doubles the wait after every failed attempt.
+	BackoffExponential = "exponential"
+)
+
+// BudgetConfig caps the tokens and cost a step or a run may spend. A run
+// that spends more than a limit fails; zero limits are unlimited.
+type BudgetConfig struct {
+	MaxTokens int     `json:"maxTokens,omitempty" yaml:"maxTokens,omitempty"`
+	MaxCost   float64 `json:"maxCost,omitempty"   yaml:"maxCost,omitempty"`
+	// WarnAt is the share of a limit, between 0 and 1, at which a warning is
+	// logged; 0 is DefaultBudgetWarnAt.
+	WarnAt float64 `json:"warnAt,omitempty"    yaml:"warnAt,omitempty"`
+}
+
+// DefaultBudgetWarnAt is the share of a budget limit at which a warning is
+// logged when the budget does not set WarnAt.
+const DefaultBudgetWarnAt = 0.8
+
 // ExecutionContext represents the runtime context of a flow execution.
 type ExecutionContext struct {
 	FlowID      string                `json:"flowId"`
@@ -127,6 +237,8 @@ const (
 	StepStatusFailed StepStatus = "failed"
 	// StepStatusSkipped represents a skipped step status.
 	StepStatusSkipped StepStatus = "skipped"
+	// StepStatusCanceled represents a step stopped by canceling its run.
+	StepStatusCanceled StepStatus = "canceled"
 )
 
 // ExecutionError represents an error during execution.
@@ -139,137 +251,319 @@ type ExecutionError struct {
 	StackTrace  string    `json:"stackTrace,omitempty"`
 }
 
-// Validate validates the flow definition.
-func (f *FlowDefinition) Validate() error {
-	err := f.validateBasicFields()
-	if err != nil {
-		return err
+// StepTypeRegistry reports whether a step type has an executor that can run it.
+type StepTypeRegistry interface {
+	Supports(stepType StepType) bool
+}
+
+// ValidateOption customizes flow validation.
+type ValidateOption func(*validateOptions)
+
+// validateOptions holds the settings applied by ValidateOption values.
+type validateOptions struct {
+	stepTypes StepTypeRegistry
+}
+
+// builtinStepTypes accepts the step types defined by this package.
+type builtinStepTypes struct{}
+
+// Supports reports whether stepType is one of the built-in step types.
+func (builtinStepTypes) Supports(stepType StepType) bool {
+	switch stepType {
+	case StepTypePrompt, StepTypeCondition, StepTypeEnd, StepTypeGitHub, StepTypeTool, StepTypeApproval,
+		StepTypeParallel:
+		return true
+	default:
+		return false
 	}
+}
 
-	// Validate step references
-	for stepID, step := range f.Steps {
-		err := f.validateStep(stepID, step)
-		if err != nil {
-			return err
+// WithStepTypes validates step types against registry instead of the built-in step types.
+func WithStepTypes(registry StepTypeRegistry) ValidateOption {
+	return func(opts *validateOptions) {
+		if registry != nil {
+			opts.stepTypes = registry
 		}
 	}
+}
 
-	return nil
+// Validate validates the flow definition and returns the first error Diagnose reports.
+func (f *FlowDefinition) Validate(opts ...ValidateOption) error {
+	return FirstError(f.Diagnose(opts...))
+}
+
+// Diagnose checks the whole flow definition and reports every problem it
+// finds, with steps checked in ID order.
+func (f *FlowDefinition) Diagnose(opts ...ValidateOption) []Diagnostic {
+	options := &validateOptions{stepTypes: builtinStepTypes{}}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	found := &diagnostics{list: nil}
+
+	f.validateBasicFields(found)
+
+	stepIDs := make([]string, 0, len(f.Steps))
+	for stepID := range f.Steps {
+		stepIDs = append(stepIDs, stepID)
+	}
+
+	slices.Sort(stepIDs)
+
+	for _, stepID := range stepIDs {
+		f.validateStep(found, stepID, f.Steps[stepID], options)
+	}
+
+	return found.list
 }
 
 // validateBasicFields validates the basic flow fields.
-func (f *FlowDefinition) validateBasicFields() error {
+func (f *FlowDefinition) validateBasicFields(found *diagnostics) {
 	if f.ID == "" {
-		return &ExecutionError{
-			Code:        "INVALID_FLOW",
-			Message:     "flow ID is required",
-			Details:     nil,
-			Recoverable: false,
-			Timestamp:   time.Now(),
-			StackTrace:  "",
-		}
+		found.flowError("$.id", "flow ID is required")
 	}
 
 	if f.Name == "" {
-		return &ExecutionError{
-			Code:        "INVALID_FLOW",
-			Message:     "flow name is required",
-			Details:     nil,
-			Recoverable: false,
-			Timestamp:   time.Now(),
-			StackTrace:  "",
-		}
+		found.flowError("$.name", "flow name is required")
 	}
 
 	if len(f.Steps) == 0 {
-		return &ExecutionError{
-			Code:        "INVALID_FLOW",
-			Message:     "flow must have at least one step",
-			Details:     nil,
-			Recoverable: false,
-			Timestamp:   time.Now(),
-			StackTrace:  "",
+		found.flowError("$.steps", "flow must have at least one step")
+	}
+
+	if problem := budgetProblem(f.Budget); problem != "" {
+		found.flowError("$.budget", problem)
+	}
+}
+
+// validateStep validates a single step and its references. The configuration
+// of a step whose type has no executor is not checked.
+func (f *FlowDefinition) validateStep(found *diagnostics, stepID string, step Step, options *validateOptions) {
+	if options.stepTypes.Supports(step.Type) {
+		f.validateStepConfiguration(found, stepID, step)
+	} else {
+		found.add(Diagnostic{
+			Code:     "UNKNOWN_STEP_TYPE",
+			Message:  "no executor is registered for step type",
+			StepID:   stepID,
+			Path:     StepPath(stepID, "type"),
+			Severity: SeverityError,
+			Details:  map[string]any{"stepId": stepID, "type": string(step.Type)},
+			Line:     0,
+			Column:   0,
+		})
+	}
+
+	f.validateStepReferences(found, stepID, step)
+}
+
+// validateStepConfiguration validates step-specific configuration.
+func (f *FlowDefinition) validateStepConfiguration(found *diagnostics, stepID string, step Step) {
+	if step.Type == StepTypePrompt && step.Prompt == nil {
+		found.stepError(stepID, "prompt", "prompt step must have prompt configuration")
+	}
+
+	if step.Type == StepTypeTool && (step.Tool == nil || step.Tool.Name == "") {
+		found.stepError(stepID, "tool.name", "tool step must have a tool name")
+	}
+
+	if step.Type == StepTypeApproval && (step.Approval == nil || step.Approval.Question == "") {
+		found.stepError(stepID, "approval.question", "approval step must have a question")
+	}
+
+	if step.Type == StepTypeGitHub && (step.GitHub == nil || !isGitHubOperation(step.GitHub.Operation)) {
+		found.stepError(stepID, "github.operation", "github step must have a known operation")
+	}
+
+	if step.Type == StepTypeCondition && len(step.Conditions) == 0 {
+		found.stepError(stepID, "conditions", "condition step must have at least one condition")
+	}
+
+	validateRetry(found, stepID, step.Retry)
+
+	if step.MaxVisits < 0 {
+		found.stepError(stepID, "maxVisits", "maxVisits must not be negative")
+	}
+
+	if problem := budgetProblem(step.Budget); problem != "" {
+		found.stepError(stepID, "budget", problem)
+	}
+
+	if step.Type == StepTypeParallel {
+		validateParallel(found, stepID, step.Parallel)
+	}
+
+	for index, condition := range step.Conditions {
+		err := f.validateExpression(condition.Expression)
+		if err != nil {
+			found.add(Diagnostic{
+				Code:     "INVALID_EXPRESSION",
+				Message:  fmt.Sprintf("invalid condition expression %q: %v", condition.Expression, err),
+				StepID:   stepID,
+				Path:     StepPath(stepID, fmt.Sprintf("conditions[%d].expression", index)),
+				Severity: SeverityError,
+				Details:  map[string]any{"stepId": stepID, "expression": condition.Expression},
+				Line:     0,
+				Column:   0,
+			})
 		}
 	}
+}
 
-	return nil
+// validateRetry validates the retry configuration of a step.
+func validateRetry(found *diagnostics, stepID string, retry *RetryConfig) {
+	if retry == nil {
+		return
+	}
+
+	switch retry.Backoff {
+	case "", BackoffFixed, BackoffLinear, BackoffExponential:
+	default:
+		found.add(Diagnostic{
+			Code:     "INVALID_STEP",
+			Message:  fmt.Sprintf("unknown retry backoff %q", retry.Backoff),
+			StepID:   stepID,
+			Path:     StepPath(stepID, "retry.backoff"),
+			Severity: SeverityError,
+			Details:  map[string]any{"stepId": stepID, "backoff": retry.Backoff},
+			Line:     0,
+			Column:   0,
+		})
+	}
+
+	if retry.MaxAttempts < 0 || retry.Delay < 0 {
+		found.stepError(stepID, "retry", "retry attempts and delay must not be negative")
+	}
+}
+
+// Validate validates the budget limits. A nil budget is valid.
+func (b *BudgetConfig) Validate() error {
+	problem := budgetProblem(b)
+	if problem == "" {
+		return nil
+	}
+
+	return &ExecutionError{
+		Code:        "INVALID_BUDGET",
+		Message:     problem,
+		Details:     nil,
+		Recoverable: false,
+		Timestamp:   time.Now(),
+		StackTrace:  "",
+	}
+}
+
+// budgetProblem describes what is wrong with a budget, or returns "" when it is valid.
+func budgetProblem(budget *BudgetConfig) string {
+	switch {
+	case budget == nil:
+		return ""
+	case budget.MaxTokens < 0 || budget.MaxCost < 0:
+		return "budget limits must not be negative"
+	case budget.WarnAt < 0 || budget.WarnAt > 1:
+		return "budget warnAt must be between 0 and 1"
+	default:
+		return ""
+	}
+}
+
+// isGitHubOperation reports whether operation is one of the GitHub operations.
+func isGitHubOperation(operation string) bool {
+	switch operation {
+	case GitHubGetIssue, GitHubGetPullRequest, GitHubComment, GitHubAddLabels,
+		GitHubListReviewThreads, GitHubResolveReviewThread:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateParallel checks that a parallel step has branches, a reachable
+// wait count and a known merge strategy.
+func validateParallel(found *diagnostics, stepID string, parallel *ParallelConfig) {
+	if parallel == nil || len(parallel.Branches) == 0 {
+		found.stepError(stepID, "parallel.branches", "parallel step must have at least one branch")
+
+		return
+	}
+
+	if parallel.Wait < 0 || parallel.Wait > len(parallel.Branches) {
+		found.stepError(stepID, "parallel.wait",
+			fmt.Sprintf("parallel wait must be between 0 and %d", len(parallel.Branches)))
+	}
+
+	if parallel.Merge != "" && parallel.Merge != MergeUnique && parallel.Merge != MergeOrdered {
+		found.stepError(stepID, "parallel.merge", fmt.Sprintf("unknown parallel merge strategy %q", parallel.Merge))
+	}
 }
 
-// validateStep validates a single step and its references.
-func (f *FlowDefinition) validateStep(stepID string, step Step) error {
-	err := f.validateStepConfiguration(stepID, step)
+// validateExpression parses a condition and type-checks it against the flow's
+// declared variables and step IDs.
+func (f *FlowDefinition) validateExpression(source string) error {
+	expression, err := expr.Parse(source)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to parse expression: %w", err)
 	}
 
-	err = f.validateStepReferences(stepID, step)
+	err = expression.CheckBool(f.ExpressionSchema())
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to check expression: %w", err)
 	}
 
 	return nil
 }
 
-// validateStepConfiguration validates step-specific configuration.
-func (f *FlowDefinition) validateStepConfiguration(stepID string, step Step) error {
-	if step.Type == StepTypePrompt && step.Prompt == nil {
-		return &ExecutionError{
-			Code:        "INVALID_STEP",
-			Message:     "prompt step must have prompt configuration",
-			Details:     map[string]any{"stepId": stepID},
-			Recoverable: false,
-			Timestamp:   time.Now(),
-			StackTrace:  "",
-		}
+// ExpressionSchema returns the variable names and step IDs expressions in this flow may reference.
+// Expressions read only the variables the flow declares, so a misspelled name
+// fails Validate; a value given only when the run starts, such as an execute
+// --context value, must be declared, with a default, for conditions to read it.
+func (f *FlowDefinition) ExpressionSchema() expr.Schema {
+	variables := make([]string, 0, len(f.Variables))
+	for name := range f.Variables {
+		variables = append(variables, name)
 	}
 
-	if step.Type == StepTypeCondition && len(step.Conditions) == 0 {
-		return &ExecutionError{
-			Code:        "INVALID_STEP",
-			Message:     "condition step must have at least one condition",
-			Details:     map[string]any{"stepId": stepID},
-			Recoverable: false,
-			Timestamp:   time.Now(),
-			StackTrace:  "",
-		}
+	steps := make([]string, 0, len(f.Steps))
+	for stepID := range f.Steps {
+		steps = append(steps, stepID)
 	}
 
-	return nil
+	slices.Sort(variables)
+	slices.Sort(steps)
+
+	return expr.Schema{Variables: variables, Steps: steps}
 }
 
 // validateStepReferences validates step references to other steps.
-func (f *FlowDefinition) validateStepReferences(stepID string, step Step) error {
+func (f *FlowDefinition) validateStepReferences(found *diagnostics, stepID string, step Step) {
 	// Validate next step references
 	if step.Next != "" && step.Type != StepTypeEnd {
 		if _, exists := f.Steps[step.Next]; !exists {
-			return &ExecutionError{
-				Code:        "INVALID_REFERENCE",
-				Message:     "step references non-existent next step",
-				Details:     map[string]any{"stepId": stepID, "nextStep": step.Next},
-				Recoverable: false,
-				Timestamp:   time.Now(),
-				StackTrace:  "",
+			found.reference(stepID, "next", "step references non-existent next step",
+				map[string]any{"stepId": stepID, "nextStep": step.Next})
+		}
+	}
+
+	// Validate parallel branch references
+	if step.Parallel != nil {
+		for index, branch := range step.Parallel.Branches {
+			if _, exists := f.Steps[branch]; !exists {
+				found.reference(stepID, fmt.Sprintf("parallel.branches[%d]", index),
+					"parallel step references non-existent branch step", map[string]any{"stepId": stepID, "branch": branch})
 			}
 		}
 	}
 
 	// Validate condition references
-	for _, condition := range step.Conditions {
+	for index, condition := range step.Conditions {
 		if condition.Next != "" {
 			if _, exists := f.Steps[condition.Next]; !exists {
-				return &ExecutionError{
-					Code:        "INVALID_REFERENCE",
-					Message:     "condition references non-existent step",
-					Details:     map[string]any{"stepId": stepID, "conditionNext": condition.Next},
-					Recoverable: false,
-					Timestamp:   time.Now(),
-					StackTrace:  "",
-				}
+				found.reference(stepID, fmt.Sprintf("conditions[%d].next", index),
+					"condition references non-existent step", map[string]any{"stepId": stepID, "conditionNext": condition.Next})
 			}
 		}
 	}
-
-	return nil
 }
 
 // Error implements the error interface for ExecutionError.
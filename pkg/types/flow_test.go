@@ -1,6 +1,7 @@
 package types_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -39,10 +40,16 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:      "",
 						Tools:      []string{},
 						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
 						Next:       "step2",
 						Conditions: []types.ConditionConfig{},
 						Timeout:    nil,
 						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
 						Metadata:   make(map[string]any),
 					},
 					"step2": {
@@ -51,14 +58,21 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:      "",
 						Tools:      []string{},
 						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
 						Next:       "",
 						Conditions: []types.ConditionConfig{},
 						Timeout:    nil,
 						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
 						Metadata:   make(map[string]any),
 					},
 				},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: false,
 			errMsg:  "",
@@ -79,14 +93,21 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:      "",
 						Tools:      []string{},
 						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
 						Next:       "",
 						Conditions: []types.ConditionConfig{},
 						Timeout:    nil,
 						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
 						Metadata:   make(map[string]any),
 					},
 				},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: true,
 			errMsg:  "flow ID is required",
@@ -107,14 +128,21 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:      "",
 						Tools:      []string{},
 						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
 						Next:       "",
 						Conditions: []types.ConditionConfig{},
 						Timeout:    nil,
 						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
 						Metadata:   make(map[string]any),
 					},
 				},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: true,
 			errMsg:  "flow name is required",
@@ -130,6 +158,7 @@ func TestFlowDefinition_Validate(t *testing.T) {
 				Variables:   make(map[string]string),
 				Steps:       map[string]types.Step{},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: true,
 			errMsg:  "flow must have at least one step",
@@ -150,14 +179,21 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:      "",
 						Tools:      []string{},
 						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
 						Next:       "",
 						Conditions: []types.ConditionConfig{},
 						Timeout:    nil,
 						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
 						Metadata:   make(map[string]any),
 					},
 				},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: true,
 			errMsg:  "prompt step must have prompt configuration",
@@ -178,14 +214,21 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:      "",
 						Tools:      []string{},
 						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
 						Next:       "",
 						Conditions: []types.ConditionConfig{}, // Missing Conditions
 						Timeout:    nil,
 						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
 						Metadata:   make(map[string]any),
 					},
 				},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: true,
 			errMsg:  "condition step must have at least one condition",
@@ -210,14 +253,21 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:      "",
 						Tools:      []string{},
 						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
 						Next:       "nonexistent", // Invalid reference
 						Conditions: []types.ConditionConfig{},
 						Timeout:    nil,
 						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
 						Metadata:   make(map[string]any),
 					},
 				},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: true,
 			errMsg:  "step references non-existent next step",
@@ -238,6 +288,10 @@ func TestFlowDefinition_Validate(t *testing.T) {
 						Model:     "",
 						Tools:     []string{},
 						MCPServer: "",
+						Tool:      nil,
+						Approval:  nil,
+						Parallel:  nil,
+						GitHub:    nil,
 						Next:      "",
 						Conditions: []types.ConditionConfig{
 							{
@@ -245,16 +299,209 @@ func TestFlowDefinition_Validate(t *testing.T) {
 								Next:       "nonexistent", // Invalid reference
 							},
 						},
-						Timeout:  nil,
-						Retry:    nil,
-						Metadata: make(map[string]any),
+						Timeout:   nil,
+						Retry:     nil,
+						MaxVisits: 0,
+						Budget:    nil,
+						Metadata:  make(map[string]any),
 					},
 				},
 				InitialStep: "",
+				Budget:      nil,
 			},
 			wantErr: true,
 			errMsg:  "condition references non-existent step",
 		},
+		{
+			name: "tool step without tool name",
+			flow: types.FlowDefinition{
+				Schema:      "",
+				Version:     "1.0",
+				ID:          "test-flow",
+				Name:        "Test Flow",
+				Description: "A test flow",
+				Variables:   make(map[string]string),
+				Steps: map[string]types.Step{
+					"step1": {
+						Type:       types.StepTypeTool,
+						Prompt:     nil,
+						Model:      "",
+						Tools:      []string{},
+						MCPServer:  "github",
+						Tool:       &types.ToolConfig{Name: "", Arguments: map[string]any{"query": "bugs"}},
+						Next:       "",
+						Conditions: []types.ConditionConfig{},
+						Timeout:    nil,
+						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
+						Metadata:   make(map[string]any),
+					},
+				},
+				InitialStep: "",
+				Budget:      nil,
+			},
+			wantErr: true,
+			errMsg:  "tool step must have a tool name",
+		},
+		{
+			name: "approval step without question",
+			flow: types.FlowDefinition{
+				Schema:      "",
+				Version:     "1.0",
+				ID:          "test-flow",
+				Name:        "Test Flow",
+				Description: "A test flow",
+				Variables:   make(map[string]string),
+				Steps: map[string]types.Step{
+					"step1": {
+						Type:       types.StepTypeApproval,
+						Prompt:     nil,
+						Model:      "",
+						Tools:      []string{},
+						MCPServer:  "",
+						Tool:       nil,
+						Approval:   &types.ApprovalConfig{Question: ""},
+						Parallel:   nil,
+						GitHub:     nil,
+						Next:       "",
+						Conditions: []types.ConditionConfig{},
+						Timeout:    nil,
+						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
+						Metadata:   make(map[string]any),
+					},
+				},
+				InitialStep: "",
+				Budget:      nil,
+			},
+			wantErr: true,
+			errMsg:  "approval step must have a question",
+		},
+		{
+			name: "github step with unknown operation",
+			flow: types.FlowDefinition{
+				Schema:      "",
+				Version:     "1.0",
+				ID:          "test-flow",
+				Name:        "Test Flow",
+				Description: "A test flow",
+				Variables:   make(map[string]string),
+				Steps: map[string]types.Step{
+					"step1": {
+						Type:       types.StepTypeGitHub,
+						Prompt:     nil,
+						Model:      "",
+						Tools:      []string{},
+						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     &types.GitHubConfig{Operation: "merge", Arguments: nil},
+						Next:       "",
+						Conditions: []types.ConditionConfig{},
+						Timeout:    nil,
+						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
+						Metadata:   make(map[string]any),
+					},
+				},
+				InitialStep: "",
+				Budget:      nil,
+			},
+			wantErr: true,
+			errMsg:  "github step must have a known operation",
+		},
+		{
+			name: "parallel step waiting for more branches than it has",
+			flow: types.FlowDefinition{
+				Schema:      "",
+				Version:     "1.0",
+				ID:          "test-flow",
+				Name:        "Test Flow",
+				Description: "A test flow",
+				Variables:   make(map[string]string),
+				Steps: map[string]types.Step{
+					"step1": {
+						Type:       types.StepTypeParallel,
+						Prompt:     nil,
+						Model:      "",
+						Tools:      []string{},
+						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   &types.ParallelConfig{Branches: []string{"step2"}, Wait: 2, Merge: ""},
+						GitHub:     nil,
+						Next:       "",
+						Conditions: []types.ConditionConfig{},
+						Timeout:    nil,
+						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
+						Metadata:   make(map[string]any),
+					},
+					"step2": {
+						Type:       types.StepTypeEnd,
+						Prompt:     nil,
+						Model:      "",
+						Tools:      []string{},
+						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
+						Next:       "",
+						Conditions: []types.ConditionConfig{},
+						Timeout:    nil,
+						Retry:      nil,
+						MaxVisits:  0,
+						Budget:     nil,
+						Metadata:   make(map[string]any),
+					},
+				},
+				InitialStep: "",
+				Budget:      nil,
+			},
+			wantErr: true,
+			errMsg:  "parallel wait must be between 0 and 1",
+		},
+		{
+			name: "unknown retry backoff",
+			flow: types.FlowDefinition{
+				Schema:      "",
+				Version:     "1.0",
+				ID:          "test-flow",
+				Name:        "Test Flow",
+				Description: "A test flow",
+				Variables:   make(map[string]string),
+				Steps: map[string]types.Step{
+					"step1": {
+						Type:       types.StepTypeEnd,
+						Prompt:     nil,
+						Model:      "",
+						Tools:      []string{},
+						MCPServer:  "",
+						Tool:       nil,
+						Approval:   nil,
+						Parallel:   nil,
+						GitHub:     nil,
+						Next:       "",
+						Conditions: []types.ConditionConfig{},
+						Timeout:    nil,
+						Retry:      &types.RetryConfig{MaxAttempts: 3, Delay: time.Second, Backoff: "random", MaxDelay: 0},
+						MaxVisits:  0,
+						Budget:     nil,
+						Metadata:   make(map[string]any),
+					},
+				},
+				InitialStep: "",
+				Budget:      nil,
+			},
+			wantErr: true,
+			errMsg:  "unknown retry backoff",
+		},
 	}
 
 	for _, testCase := range tests {
@@ -279,6 +526,238 @@ func TestFlowDefinition_Validate(t *testing.T) {
 	}
 }
 
+type stepTypeSet map[types.StepType]bool
+
+func (s stepTypeSet) Supports(stepType types.StepType) bool {
+	return s[stepType]
+}
+
+func TestFlowDefinition_Validate_StepTypes(t *testing.T) {
+	t.Parallel()
+
+	flow := types.FlowDefinition{
+		Schema:      "",
+		Version:     "1.0",
+		ID:          "custom-flow",
+		Name:        "Custom Flow",
+		Description: "",
+		Variables:   nil,
+		Steps: map[string]types.Step{
+			"run": {
+				Type:       "shell",
+				Prompt:     nil,
+				Model:      "",
+				Tools:      nil,
+				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
+				Next:       "",
+				Conditions: nil,
+				Timeout:    nil,
+				Retry:      nil,
+				MaxVisits:  0,
+				Budget:     nil,
+				Metadata:   nil,
+			},
+		},
+		InitialStep: "run",
+		Budget:      nil,
+	}
+
+	// Unknown step types are rejected against the built-in step types
+	err := flow.Validate()
+	require.Error(t, err)
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, "UNKNOWN_STEP_TYPE", execErr.Code)
+
+	// A registry that claims the step type accepts it
+	require.NoError(t, flow.Validate(types.WithStepTypes(stepTypeSet{"shell": true})))
+
+	// A registry that does not claim the step type rejects it
+	require.Error(t, flow.Validate(types.WithStepTypes(stepTypeSet{types.StepTypeEnd: true})))
+}
+
+func TestFlowDefinition_Diagnose(t *testing.T) {
+	t.Parallel()
+
+	var flow types.FlowDefinition
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "id": "broken",
+  "steps": {
+    "start": {"type": "prompt", "next": "missing"},
+    "check": {
+      "type": "condition",
+      "conditions": [{"expression": "vars.unknown == 1", "next": "gone"}]
+    },
+    "fan.out": {"type": "parallel", "parallel": {"branches": ["start", "nowhere"], "wait": 3}},
+    "done": {"type": "end", "retry": {"maxAttempts": 1, "backoff": "random"}}
+  }
+}`), &flow))
+
+	diagnostics := flow.Diagnose()
+
+	paths := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, types.SeverityError, diagnostic.Severity)
+
+		paths = append(paths, diagnostic.Code+" "+diagnostic.Path)
+	}
+
+	assert.Equal(t, []string{
+		"INVALID_FLOW $.name",
+		"INVALID_EXPRESSION $.steps.check.conditions[0].expression",
+		"INVALID_REFERENCE $.steps.check.conditions[0].next",
+		"INVALID_STEP $.steps.done.retry.backoff",
+		`INVALID_STEP $.steps["fan.out"].parallel.wait`,
+		`INVALID_REFERENCE $.steps["fan.out"].parallel.branches[1]`,
+		"INVALID_STEP $.steps.start.prompt",
+		"INVALID_REFERENCE $.steps.start.next",
+	}, paths)
+	assert.Equal(t, "start", diagnostics[len(diagnostics)-1].StepID)
+
+	// Validate reports the first of them
+	err := flow.Validate()
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, "INVALID_FLOW", execErr.Code)
+	assert.Equal(t, "flow name is required", execErr.Message)
+}
+
+func TestFlowDefinition_Diagnose_Budgets(t *testing.T) {
+	t.Parallel()
+
+	var flow types.FlowDefinition
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "id": "budgeted",
+  "name": "Budgeted",
+  "budget": {"maxTokens": 10000, "maxCost": -1},
+  "steps": {
+    "ask": {"type": "prompt", "prompt": {"template": "hi"}, "budget": {"maxTokens": 500, "warnAt": 1.5}, "next": "done"},
+    "done": {"type": "end", "budget": {"maxCost": 0.25, "warnAt": 0.5}}
+  }
+}`), &flow))
+
+	diagnostics := flow.Diagnose()
+
+	messages := make([]string, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.Path+": "+diagnostic.Message)
+	}
+
+	assert.Equal(t, []string{
+		"$.budget: budget limits must not be negative",
+		"$.steps.ask.budget: budget warnAt must be between 0 and 1",
+	}, messages)
+}
+
+func TestBudgetConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	var budget *types.BudgetConfig
+	require.NoError(t, budget.Validate())
+	require.NoError(t, (&types.BudgetConfig{MaxTokens: 1000, MaxCost: 2.5, WarnAt: 0.9}).Validate())
+
+	err := (&types.BudgetConfig{MaxTokens: -1}).Validate()
+
+	var execErr *types.ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, "INVALID_BUDGET", execErr.Code)
+	assert.Equal(t, "budget limits must not be negative", execErr.Message)
+}
+
+func TestFlowDefinition_Validate_Expressions(t *testing.T) {
+	t.Parallel()
+
+	newFlow := func(expression string) types.FlowDefinition {
+		return types.FlowDefinition{
+			Schema:      "",
+			Version:     "1.0",
+			ID:          "expression-flow",
+			Name:        "Expression Flow",
+			Description: "",
+			Variables:   map[string]string{"env": "dev"},
+			Steps: map[string]types.Step{
+				"check": {
+					Type:       types.StepTypeCondition,
+					Prompt:     nil,
+					Model:      "",
+					Tools:      nil,
+					MCPServer:  "",
+					Tool:       nil,
+					Approval:   nil,
+					Parallel:   nil,
+					GitHub:     nil,
+					Next:       "done",
+					Conditions: []types.ConditionConfig{{Expression: expression, Next: "done"}},
+					Timeout:    nil,
+					Retry:      nil,
+					MaxVisits:  0,
+					Budget:     nil,
+					Metadata:   nil,
+				},
+				"done": {
+					Type:       types.StepTypeEnd,
+					Prompt:     nil,
+					Model:      "",
+					Tools:      nil,
+					MCPServer:  "",
+					Tool:       nil,
+					Approval:   nil,
+					Parallel:   nil,
+					GitHub:     nil,
+					Next:       "",
+					Conditions: nil,
+					Timeout:    nil,
+					Retry:      nil,
+					MaxVisits:  0,
+					Budget:     nil,
+					Metadata:   nil,
+				},
+			},
+			InitialStep: "check",
+			Budget:      nil,
+		}
+	}
+
+	validFlow := newFlow(`steps.done.status == "completed" && vars.env == "prod"`)
+	require.NoError(t, validFlow.Validate())
+
+	tests := []struct {
+		name       string
+		expression string
+		errMsg     string
+	}{
+		{"syntax error", "vars.env ==", "syntax error"},
+		{"misspelled variable", `vars.evn == "prod"`, "unknown variable"},
+		{"misspelled step", "steps.dne.output > 1", "unknown step"},
+		{"non-boolean", "1 + 2", "does not produce a boolean"},
+		{"variable compared with number", "vars.env > 3", "type mismatch"},
+		{"variable as condition", "vars.env", "does not produce a boolean"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			flow := newFlow(testCase.expression)
+			err := flow.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.errMsg)
+
+			var execErr *types.ExecutionError
+			require.ErrorAs(t, err, &execErr)
+			assert.Equal(t, "INVALID_EXPRESSION", execErr.Code)
+		})
+	}
+}
+
 func TestExecutionError_Error(t *testing.T) {
 	t.Parallel()
 
@@ -374,6 +853,7 @@ func TestRetryConfig_Validation(t *testing.T) {
 		MaxAttempts: 3,
 		Delay:       time.Second * 5,
 		Backoff:     "",
+		MaxDelay:    0,
 	}
 
 	// Basic validation
@@ -401,10 +881,16 @@ func BenchmarkFlowDefinition_Validate(b *testing.B) {
 				Model:      "",
 				Tools:      []string{},
 				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
 				Next:       "step2",
 				Conditions: []types.ConditionConfig{},
 				Timeout:    nil,
 				Retry:      nil,
+				MaxVisits:  0,
+				Budget:     nil,
 				Metadata:   make(map[string]any),
 			},
 			"step2": {
@@ -413,14 +899,21 @@ func BenchmarkFlowDefinition_Validate(b *testing.B) {
 				Model:      "",
 				Tools:      []string{},
 				MCPServer:  "",
+				Tool:       nil,
+				Approval:   nil,
+				Parallel:   nil,
+				GitHub:     nil,
 				Next:       "",
 				Conditions: []types.ConditionConfig{},
 				Timeout:    nil,
 				Retry:      nil,
+				MaxVisits:  0,
+				Budget:     nil,
 				Metadata:   make(map[string]any),
 			},
 		},
 		InitialStep: "",
+		Budget:      nil,
 	}
 
 	b.ResetTimer()
@@ -2,11 +2,13 @@
 package types
 
 import (
+	"fmt"
 	"time"
 )
 
 // MCPServerConfig represents the configuration for an MCP server.
 type MCPServerConfig struct {
+	Schema           string            `json:"$schema,omitempty"          yaml:"schema,omitempty"`
 	Name             string            `json:"name"                       yaml:"name"`
 	Command          string            `json:"command"                    yaml:"command"`
 	Args             []string          `json:"args,omitempty"             yaml:"args,omitempty"`
@@ -135,6 +137,11 @@ type MCPError struct {
 	Data    map[string]any `json:"data,omitempty"`
 }
 
+// Error implements the error interface.
+func (e *MCPError) Error() string {
+	return fmt.Sprintf("MCP error %d: %s", e.Code, e.Message)
+}
+
 // Validate validates the MCP server configuration.
 func (m *MCPServerConfig) Validate() error {
 	if m.Name == "" {
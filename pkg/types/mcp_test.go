@@ -22,6 +22,7 @@ func TestMCPServerConfig_Validate(t *testing.T) {
 		{
 			name: "valid stdio config",
 			config: types.MCPServerConfig{
+				Schema:           "",
 				Name:             "test-server",
 				Command:          "python",
 				Args:             []string{"-m", "test_server"},
@@ -46,6 +47,7 @@ func TestMCPServerConfig_Validate(t *testing.T) {
 		{
 			name: "valid http config",
 			config: types.MCPServerConfig{
+				Schema:        "",
 				Name:          "http-server",
 				Command:       "node",
 				Args:          []string{"server.js"},
@@ -73,6 +75,7 @@ func TestMCPServerConfig_Validate(t *testing.T) {
 		{
 			name: "missing name",
 			config: types.MCPServerConfig{
+				Schema:           "",
 				Name:             "",
 				Command:          "python",
 				Args:             []string{},
@@ -97,6 +100,7 @@ func TestMCPServerConfig_Validate(t *testing.T) {
 		{
 			name: "missing command",
 			config: types.MCPServerConfig{
+				Schema:           "",
 				Name:             "test-server",
 				Command:          "",
 				Args:             []string{},
@@ -121,6 +125,7 @@ func TestMCPServerConfig_Validate(t *testing.T) {
 		{
 			name: "invalid transport type",
 			config: types.MCPServerConfig{
+				Schema:           "",
 				Name:             "test-server",
 				Command:          "python",
 				Args:             []string{},
@@ -145,6 +150,7 @@ func TestMCPServerConfig_Validate(t *testing.T) {
 		{
 			name: "no capabilities enabled",
 			config: types.MCPServerConfig{
+				Schema:           "",
 				Name:             "test-server",
 				Command:          "python",
 				Args:             []string{},
@@ -169,6 +175,7 @@ func TestMCPServerConfig_Validate(t *testing.T) {
 		{
 			name: "http without transport options",
 			config: types.MCPServerConfig{
+				Schema:           "",
 				Name:             "http-server",
 				Command:          "node",
 				Args:             []string{},
@@ -464,6 +471,7 @@ func TestMCPToolResult_Validation(t *testing.T) {
 // Benchmark tests.
 func BenchmarkMCPServerConfig_Validate(b *testing.B) {
 	config := types.MCPServerConfig{
+		Schema:           "",
 		Name:             "bench-server",
 		Command:          "python",
 		Args:             []string{"-m", "test_server"},
@@ -0,0 +1,74 @@
+package e2e_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/flow-test-go/tests/e2e/testutil"
+)
+
+const branchingFlow = `{
+	"id": "branching",
+	"name": "Branching Flow",
+	"initialStep": "check",
+	"steps": {
+		"check": {
+			"type": "condition",
+			"conditions": [
+				{"expression": "false", "next": "false-branch"},
+				{"expression": "true", "next": "true-branch"}
+			]
+		},
+		"true-branch": {"type": "end"},
+		"false-branch": {"type": "end"}
+	}
+}`
+
+func TestExecuteCommand_ConditionalFlow(t *testing.T) {
+	t.Parallel()
+
+	exec := testutil.NewTestExecution(t, "execute-conditional").Start()
+
+	tempDir, _ := testutil.SetupTestWithCustomFlows(t, map[string]string{
+		"branching.json": branchingFlow,
+	})
+
+	result := testutil.NewFlowTest(t).
+		WithWorkDir(tempDir).
+		WithArgs("execute", "branching").
+		WithTimeout(30 * time.Second).
+		ExpectSuccess().
+		Run()
+
+	duration := exec.Complete(result)
+
+	require.Equal(t, 0, result.ExitCode, "Execute command should complete successfully")
+	assert.Contains(t, result.Stderr, "true-branch", "Should take the matching branch")
+	assert.NotContains(t, result.Stderr, "false-branch", "Should not take the non-matching branch")
+	assert.Contains(t, result.Stderr, "completed", "Should report the completed status")
+
+	t.Logf("Execute conditional flow test completed in %v", duration)
+}
+
+func TestExecuteCommand_UnknownFlow(t *testing.T) {
+	t.Parallel()
+
+	exec := testutil.NewTestExecution(t, "execute-unknown").Start()
+
+	tempDir, _ := testutil.SetupEmptyTest(t)
+
+	result := testutil.NewFlowTest(t).
+		WithWorkDir(tempDir).
+		WithArgs("execute", "does-not-exist").
+		WithTimeout(30 * time.Second).
+		ExpectFailure().
+		ExpectError("failed to load flow").
+		Run()
+
+	duration := exec.Complete(result)
+
+	t.Logf("Execute unknown flow test completed in %v with exit code %d", duration, result.ExitCode)
+}
@@ -16,6 +16,7 @@ type FlowTestBuilder struct {
 	configDir   string
 	timeout     time.Duration
 	workDir     string
+	args        []string
 	expectExit  *int
 	expectError string
 	expectOut   string
@@ -39,6 +40,7 @@ func NewFlowTest(t *testing.T) *FlowTestBuilder {
 		flowFile:    "",
 		configDir:   "",
 		workDir:     "",
+		args:        nil,
 		timeout:     defaultTestTimeout, // Default timeout
 		expectExit:  nil,
 		expectError: "",
@@ -74,6 +76,13 @@ func (b *FlowTestBuilder) WithWorkDir(workDir string) *FlowTestBuilder {
 	return b
 }
 
+// WithArgs sets the command and arguments to run instead of the default list command.
+func (b *FlowTestBuilder) WithArgs(args ...string) *FlowTestBuilder {
+	b.args = args
+
+	return b
+}
+
 // ExpectExitCode sets the expected exit code.
 func (b *FlowTestBuilder) ExpectExitCode(code int) *FlowTestBuilder {
 	b.expectExit = &code
@@ -130,6 +139,10 @@ func (b *FlowTestBuilder) Run() *FlowTestResult {
 		runner.SetConfigDir(b.configDir)
 	}
 
+	if len(b.args) > 0 {
+		runner.SetArgs(b.args)
+	}
+
 	// Execute the flow
 	result := runner.Execute()
 
@@ -34,6 +34,7 @@ type FlowRunner struct {
 	workDir     string
 	timeout     time.Duration
 	binaryPath  string
+	args        []string
 	coverageDir string
 	stdout      bytes.Buffer
 	stderr      bytes.Buffer
@@ -70,6 +71,7 @@ func NewFlowRunner(t *testing.T) *FlowRunner {
 		workDir:     "",
 		timeout:     defaultRunnerTimeout,
 		binaryPath:  binaryPath, // Use absolute path to coverage-instrumented binary
+		args:        nil,
 		coverageDir: "",
 		stdout:      bytes.Buffer{},
 		stderr:      bytes.Buffer{},
@@ -101,6 +103,11 @@ func (r *FlowRunner) SetBinaryPath(binaryPath string) {
 	r.binaryPath = binaryPath
 }
 
+// SetArgs sets the command and arguments passed to the binary.
+func (r *FlowRunner) SetArgs(args []string) {
+	r.args = args
+}
+
 // Execute runs the flow and returns the result.
 func (r *FlowRunner) Execute() *FlowTestResult {
 	start := time.Now()
@@ -282,9 +289,12 @@ func (r *FlowRunner) createAndConfigureCommand(ctx context.Context) (*exec.Cmd,
 		args = append(args, "--config", r.configDir)
 	}
 
-	// For now, use 'list' command as a placeholder
-	// since the 'run' command doesn't exist yet
-	args = append(args, "list")
+	// Default to the list command when no explicit command is given
+	if len(r.args) > 0 {
+		args = append(args, r.args...)
+	} else {
+		args = append(args, "list")
+	}
 
 	// Sanitize arguments to prevent command injection
 	sanitizedArgs := sanitizeArgs(args)